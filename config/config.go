@@ -9,11 +9,14 @@ import (
 )
 
 type Options struct {
-	MySQL    MySQLOptions `json:"mysql"`
-	Redis    RedisOptions `json:"redis"`
-	Log      LogConfig    `json:"log"`
-	Jwt      JWT          `json:"jwt"`
-	Language string       `json:"language"`
+	MySQL     MySQLOptions     `json:"mysql"`
+	Redis     RedisOptions     `json:"redis"`
+	Log       LogConfig        `json:"log"`
+	Jwt       JWT              `json:"jwt"`
+	Language  string           `json:"language"`
+	Trash     TrashOptions     `json:"trash"`
+	Recent    RecentOptions    `json:"recent"`
+	WebSocket WebSocketOptions `json:"websocket"`
 }
 
 func New() (*Options, error) {
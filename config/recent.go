@@ -0,0 +1,26 @@
+package config
+
+import "time"
+
+// RecentOptions 最近使用记录配置
+type RecentOptions struct {
+	MaxItems int    `mapstructure:"max_items"` // 每个用户保留的条目数，默认 50
+	TTL      string `mapstructure:"ttl"`       // 最后一次使用后保留的时长，默认 720h
+}
+
+// Limit 每个用户保留的条目数
+func (o RecentOptions) Limit() int {
+	if o.MaxItems <= 0 {
+		return 50
+	}
+	return o.MaxItems
+}
+
+// Expiry 最后一次使用后保留的时长，未配置或无法解析时为 30 天
+func (o RecentOptions) Expiry() time.Duration {
+	d, err := time.ParseDuration(o.TTL)
+	if err != nil || d <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return d
+}
@@ -0,0 +1,27 @@
+package config
+
+import "time"
+
+// TrashOptions 回收站配置
+type TrashOptions struct {
+	RetentionDays int    `mapstructure:"retention_days"` // 回收站内容保留天数，默认 30 天
+	SweepInterval string `mapstructure:"sweep_interval"` // 清理过期内容的间隔，默认 1h
+}
+
+// Retention 回收站内容的保留时长
+func (o TrashOptions) Retention() time.Duration {
+	days := o.RetentionDays
+	if days <= 0 {
+		days = 30
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// Interval 清理间隔，未配置或无法解析时为 1 小时
+func (o TrashOptions) Interval() time.Duration {
+	d, err := time.ParseDuration(o.SweepInterval)
+	if err != nil || d <= 0 {
+		return time.Hour
+	}
+	return d
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+// WebSocketOptions WebSocket 连接配置
+type WebSocketOptions struct {
+	AllowedOrigins []string `mapstructure:"allowed_origins"` // 允许跨域建立连接的来源，如 https://app.example.com，* 表示全部
+	TicketTTL      string   `mapstructure:"ticket_ttl"`      // 连接票据的有效期，默认 30s
+}
+
+// TicketExpiry 连接票据的有效期，未配置或无法解析时为 30 秒
+func (o WebSocketOptions) TicketExpiry() time.Duration {
+	d, err := time.ParseDuration(o.TicketTTL)
+	if err != nil || d <= 0 {
+		return 30 * time.Second
+	}
+	return d
+}
+
+// OriginAllowed 是否允许来自 origin 的连接，非浏览器客户端不带 Origin，与服务同源的来源始终允许
+func (o WebSocketOptions) OriginAllowed(origin, host string) bool {
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(u.Host, host) {
+		return true
+	}
+	for _, allowed := range o.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(strings.TrimSuffix(allowed, "/"), u.Scheme+"://"+u.Host) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package config
+
+import "testing"
+
+func TestOriginAllowed(t *testing.T) {
+	opts := WebSocketOptions{AllowedOrigins: []string{"https://app.example.com/"}}
+	cases := []struct {
+		origin string
+		want   bool
+	}{
+		{"", true},
+		{"http://api.example.com:8080", true},
+		{"https://app.example.com", true},
+		{"https://APP.example.com", true},
+		{"http://app.example.com", false},
+		{"https://evil.example.com", false},
+		{"null", false},
+	}
+	for _, c := range cases {
+		if got := opts.OriginAllowed(c.origin, "api.example.com:8080"); got != c.want {
+			t.Errorf("OriginAllowed(%q) = %v, want %v", c.origin, got, c.want)
+		}
+	}
+	if !(WebSocketOptions{AllowedOrigins: []string{"*"}}).OriginAllowed("https://any.example.com", "api.example.com") {
+		t.Error("expected * to allow any origin")
+	}
+}
@@ -25,6 +25,8 @@ type App struct {
 	Redis  *redis.Client
 	Server *http.Server
 	srv    *Server
+
+	stopSweeper context.CancelFunc
 }
 
 // Setup 初始化应用
@@ -72,6 +74,9 @@ func Setup() (*App, error) {
 
 // Run 运行应用
 func (app *App) Run() error {
+	// 定期清理回收站
+	app.stopSweeper = startTrashSweeper(app.Config.Trash)
+
 	// 启动服务器
 	go func() {
 		if err := app.srv.Start(); err != nil {
@@ -96,6 +101,10 @@ func (app *App) GracefulShutdown() {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	if app.stopSweeper != nil {
+		app.stopSweeper()
+	}
+
 	if app.Server != nil {
 		global.Log.Info("正在关闭 HTTP 服务器...")
 		if err := app.Server.Shutdown(ctx); err != nil {
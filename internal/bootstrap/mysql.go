@@ -3,6 +3,7 @@ package bootstrap
 import (
 	"FastGo/internal/global"
 	"FastGo/internal/model"
+	"errors"
 	"fmt"
 
 	"go.uber.org/zap"
@@ -48,10 +49,10 @@ func (app *App) setupMySQL() error {
 }
 
 // Migrate 执行数据库迁移
-func Migrate() {
+func Migrate() error {
 	if global.GetDB() == nil {
 		global.Log.Error("数据库未初始化")
-		return
+		return errors.New("database not initialized")
 	}
 
 	err := global.GetDB().AutoMigrate(
@@ -59,15 +60,25 @@ func Migrate() {
 		// &model.Team{},
 		// &model.TeamMember{},
 		// &model.TeamInvite{},
-		// &model.Request{},
-		// &model.Workspace{},
+		&model.Request{},
+		&model.Workspace{},
 		&model.Collections{},
-		// &model.Folder{},
-		// &model.FolderClosure{},
+		&model.Folder{},
+		&model.FolderClosure{},
+		&model.Environment{},
+		&model.RequestExample{},
+		&model.ProtoSchema{},
+		&model.Execution{},
+		&model.RequestRevision{},
+		&model.SavedFilter{},
+		&model.Favorite{},
+		&model.CollectionFork{},
+		&model.MergeRequest{},
 	)
 	if err != nil {
 		global.Log.Error("数据库迁移失败", zap.Error(err))
-	} else {
-		global.Log.Info("数据库迁移成功")
+		return err
 	}
+	global.Log.Info("数据库迁移成功")
+	return nil
 }
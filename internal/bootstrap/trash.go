@@ -0,0 +1,55 @@
+package bootstrap
+
+import (
+	"FastGo/config"
+	"FastGo/internal/global"
+	"FastGo/internal/service"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// startTrashSweeper 启动后台任务，定期永久删除超过保留期的回收站内容，返回停止函数
+func startTrashSweeper(opts config.TrashOptions) context.CancelFunc {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		ticker := time.NewTicker(opts.Interval())
+		defer ticker.Stop()
+		for {
+			sweepTrash(opts.Retention())
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return cancel
+}
+
+func sweepTrash(retention time.Duration) {
+	db := global.GetDB()
+	if db == nil {
+		return
+	}
+	var report *service.DeleteReport
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		report, err = service.PurgeTrash(tx, time.Now().Add(-retention))
+		return err
+	})
+	if err != nil {
+		global.Log.Error("清理回收站失败", zap.Error(err))
+		return
+	}
+	if report.Workspaces+report.Collections+report.Folders+report.Requests > 0 {
+		global.Log.Info("已清理回收站过期内容",
+			zap.Int64("workspaces", report.Workspaces),
+			zap.Int64("collections", report.Collections),
+			zap.Int64("folders", report.Folders),
+			zap.Int64("requests", report.Requests),
+		)
+	}
+}
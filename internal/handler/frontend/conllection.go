@@ -1,26 +1,36 @@
 package frontend
 
 import (
+	"FastGo/internal/global"
 	"FastGo/internal/handler"
 	"FastGo/internal/model"
 	"FastGo/internal/router"
+	"FastGo/internal/service"
+	"FastGo/internal/service/invoke"
 	"FastGo/pkg/response"
 	"FastGo/pkg/uid"
 	"FastGo/pkg/validator"
 	"errors"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/cast"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type CollectionHandler struct {
 	*handler.CommonHandler
+	invoker *invoke.Registry
+	recents *service.RecentStore
 }
 
 func NewCollectionHandler() *CollectionHandler {
+	common := handler.NewCommonHandler()
 	return &CollectionHandler{
-		CommonHandler: handler.NewCommonHandler(),
+		CommonHandler: common,
+		invoker:       invoke.NewRegistry(common.DB, common.Redis),
+		recents:       service.NewRecentStore(common.Redis, global.Config.Recent),
 	}
 }
 
@@ -28,7 +38,12 @@ func (h *CollectionHandler) RegisterRoutes(routerRegistry *router.RouteRegistry)
 	routerRegistry.Register("POST", "collections", "/create", h.Create, 2, "创建集合")
 	routerRegistry.Register("DELETE", "collections", "/delete", h.Delete, 2, "删除集合")
 	routerRegistry.Register("POST", "collections", "/edit", h.Edit, 2, "编辑集合")
+	routerRegistry.Register("POST", "collections", "/defaults", h.SetDefaults, 2, "设置集合的请求默认值")
 	routerRegistry.Register("GET", "collections", "/list", h.GetList, 2, "获取集合列表")
+	routerRegistry.Register("GET", "collections", "/tree", h.GetTree, 2, "获取集合树")
+	routerRegistry.Register("POST", "collections", "/duplicate", h.Duplicate, 2, "复制集合")
+	routerRegistry.Register("POST", "collections", "/reorder", h.Reorder, 2, "调整同级文件夹和请求的顺序")
+	routerRegistry.Register("POST", "collections", "/run", h.Run, 2, "按顺序运行集合中的请求")
 }
 
 // create 创建收藏夹
@@ -120,15 +135,20 @@ func (h *CollectionHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	//记录删除个数
-	err = h.DB.Delete(&model.Collections{ID: cast.ToUint64(id)}).Error
+	// 级联删除文件夹、请求、集合级环境和 proto 描述
+	var report *service.DeleteReport
+	err = h.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		report, err = service.DeleteCollections(tx, []string{collection.CollectionID})
+		return err
+	})
 	if err != nil {
 		h.Logger.Error("delete collection failed due to database error", zap.Error(err))
 		result.FailWithMsg(response.Success, "delete collection failed")
 		return
 	}
 
-	result.Success(nil)
+	result.Success(report)
 }
 
 // 获取收藏夹列表
@@ -137,15 +157,16 @@ func (h *CollectionHandler) GetList(c *gin.Context) {
 	workspaceID := c.Query("workspace_id")
 
 	type resp struct {
-		ID           string `json:"id"`
-		Name         string `json:"name"`
-		Protocol     string `json:"protocol"`
-		Owner        string `json:"owner"`
-		Description  string `json:"description"`
-		WorkspaceID  string `json:"workspace_id"`
-		CollectionID string `json:"collection_id"`
-		MembersCount int    `json:"members_count"`
-		CreatedAt    string `json:"created_at"`
+		ID           string   `json:"id"`
+		Name         string   `json:"name"`
+		Protocol     string   `json:"protocol"`
+		Owner        string   `json:"owner"`
+		Description  string   `json:"description"`
+		WorkspaceID  string   `json:"workspace_id"`
+		CollectionID string   `json:"collection_id"`
+		MembersCount int      `json:"members_count"`
+		Tags         []string `json:"tags"`
+		CreatedAt    string   `json:"created_at"`
 	}
 
 	collections := []model.Collections{}
@@ -184,6 +205,7 @@ func (h *CollectionHandler) GetList(c *gin.Context) {
 			WorkspaceID:  cast.ToString(collection.WorkspaceID),
 			CollectionID: collection.CollectionID,
 			MembersCount: collection.MembersCount,
+			Tags:         model.DecodeTags(collection.Tags),
 			CreatedAt:    collection.CreatedAt.Format("2006-01-02 15:04:05"),
 		}
 
@@ -195,176 +217,385 @@ func (h *CollectionHandler) GetList(c *gin.Context) {
 	})
 }
 
-// 定义响应结构体
-// type RequestResponse struct {
-// 	ID           string `json:"id"`
-// 	Name         string `json:"name"`
-// 	Type         string `json:"type"`
-// 	Method       string `json:"method"`
-// 	CollectionID string `json:"collection_id"`
-// 	RequestID    string `json:"request_id"`
-// 	FolderID     string `json:"folder_id"`
-// 	Kind         string `json:"kind"`
-// }
-
-// // 定义响应结构体
-// type FolderResponse struct {
-// 	ID           string        `json:"id"`
-// 	Name         string        `json:"name"`
-// 	CollectionID string        `json:"collection_id"`
-// 	FolderID     string        `json:"folder_id"`
-// 	Children     []interface{} `json:"children"`
-// 	Kind         string        `json:"kind"`
-// }
-
-// type CollectionResponse struct {
-// 	ID           string        `json:"id"`
-// 	Name         string        `json:"name"`
-// 	WorkspaceID  string        `json:"workspace_id"`
-// 	CollectionID string        `json:"collection_id"`
-// 	Kind         string        `json:"kind"`
-// 	Children     []interface{} `json:"children"`
-// }
-
-// func (h *CollectionHandler) GetList(c *gin.Context) {
-// 	result := response.NewResult(c)
-// 	workspaceID := c.Query("workspace_id")
-
-// 	// 获取所有集合
-// 	collections := []model.Collections{}
-// 	err := h.DB.Where("workspace_id = ?", workspaceID).Find(&collections).Error
-// 	if err != nil {
-// 		h.Logger.Error("get collection list failed due to database error", zap.Error(err))
-// 		result.FailWithMsg(response.Success, "get collection list failed")
-// 		return
-// 	}
-
-// 	var responseList []CollectionResponse
-
-// 	for _, collection := range collections {
-
-// 		// 获取所有属于当前集合的文件夹
-// 		var allFolders []model.Folder
-// 		err = h.DB.Where("collection_id = ?", collection.CollectionID).Find(&allFolders).Error
-// 		if err != nil {
-// 			h.Logger.Error("failed to get folders", zap.Error(err))
-// 			result.FailWithMsg(response.Success, "failed to get folders")
-// 			return
-// 		}
-
-// 		folderIDs := getFolderIDs(allFolders)
-
-// 		// 获取所有闭包关系
-// 		var folderClosures []model.FolderClosure
-// 		err = h.DB.Where("ancestor IN (?)", folderIDs).Find(&folderClosures).Error
-// 		if err != nil {
-// 			h.Logger.Error("failed to get folder closures", zap.Error(err))
-// 			result.FailWithMsg(response.Success, "failed to get folder closures")
-// 			return
-// 		}
-
-// 		// 构建文件夹树
-// 		folderMap := make(map[string]FolderResponse)
-// 		for _, folder := range allFolders {
-// 			folderMap[folder.FolderID] = FolderResponse{
-// 				ID:           cast.ToString(folder.ID),
-// 				Name:         folder.Name,
-// 				CollectionID: folder.CollectionID,
-// 				FolderID:     folder.FolderID,
-// 				Kind:         "folder",
-// 				Children:     []interface{}{},
-// 			}
-// 		}
-
-// 		// 使用闭包表构建树
-// 		for _, closure := range folderClosures {
-// 			ancestor := closure.Ancestor
-// 			descendant := closure.Descendant
-// 			if ancestor != descendant {
-// 				parentFolder, parentExists := folderMap[ancestor]
-// 				childFolder, childExists := folderMap[descendant]
-// 				if parentExists && childExists {
-// 					parentFolder.Children = append(parentFolder.Children, childFolder)
-// 					folderMap[ancestor] = parentFolder
-// 				}
-// 			}
-// 		}
-
-// 		// 获取所有属于当前集合的请求
-// 		var allRequests []model.Request
-// 		err := h.DB.Where("collection_id = ?", collection.CollectionID).Find(&allRequests).Error
-// 		if err != nil {
-// 			h.Logger.Error("failed to get requests", zap.Error(err))
-// 			result.FailWithMsg(response.Success, "failed to get requests")
-// 			return
-// 		}
-
-// 		// 构建请求映射
-// 		requestMap := make(map[string][]RequestResponse)
-// 		for _, request := range allRequests {
-// 			reqResponse := RequestResponse{
-// 				ID:           cast.ToString(request.ID),
-// 				Name:         request.Name,
-// 				Type:         string(request.Type),
-// 				Method:       string(request.Method),
-// 				CollectionID: request.CollectionID,
-// 				RequestID:    request.RequestID,
-// 				FolderID:     request.FolderID,
-// 				Kind:         "request",
-// 			}
-// 			requestMap[request.FolderID] = append(requestMap[request.FolderID], reqResponse)
-// 		}
-
-// 		// 将请求添加到文件夹中
-// 		for folderID, folder := range folderMap {
-// 			for _, req := range requestMap[folderID] {
-// 				folder.Children = append(folder.Children, req)
-// 			}
-// 			folderMap[folderID] = folder
-// 		}
-
-// 		// 找到根文件夹并添加请求
-// 		var rootFolders []interface{}
-// 		for _, folder := range folderMap {
-// 			if folder.FolderID == "" || !isDescendant(folder.FolderID, folderClosures) {
-// 				rootFolders = append(rootFolders, folder)
-// 			}
-// 		}
-// 		for _, req := range requestMap[""] {
-// 			rootFolders = append(rootFolders, req)
-// 		}
-
-// 		// 添加到响应列表
-// 		responseList = append(responseList, CollectionResponse{
-// 			ID:           cast.ToString(collection.ID),
-// 			Name:         collection.Name,
-// 			WorkspaceID:  cast.ToString(collection.WorkspaceID),
-// 			CollectionID: collection.CollectionID,
-// 			Kind:         "collection",
-// 			Children:     rootFolders,
-// 		})
-// 	}
-
-// 	result.Success(map[string]interface{}{
-// 		"list": responseList,
-// 	})
-// }
-
-// // 获取文件夹ID列表
-// func getFolderIDs(folders []model.Folder) []string {
-// 	ids := make([]string, len(folders))
-// 	for i, folder := range folders {
-// 		ids[i] = cast.ToString(folder.FolderID)
-// 	}
-// 	return ids
-// }
-
-// // 检查文件夹是否是其他文件夹的后代
-// func isDescendant(folderID string, closures []model.FolderClosure) bool {
-// 	for _, closure := range closures {
-// 		if closure.Descendant == folderID && closure.Ancestor != folderID {
-// 			return true
-// 		}
-// 	}
-// 	return false
-// }
+// 集合树节点
+type (
+	requestNode struct {
+		ID           string   `json:"id"`
+		Name         string   `json:"name"`
+		Type         string   `json:"type"`
+		Method       string   `json:"method"`
+		CollectionID string   `json:"collection_id"`
+		RequestID    string   `json:"request_id"`
+		FolderID     string   `json:"folder_id"`
+		Tags         []string `json:"tags"`
+		Kind         string   `json:"kind"`
+	}
+
+	folderNode struct {
+		ID           string        `json:"id"`
+		Name         string        `json:"name"`
+		CollectionID string        `json:"collection_id"`
+		FolderID     string        `json:"folder_id"`
+		Tags         []string      `json:"tags"`
+		Kind         string        `json:"kind"`
+		Children     []interface{} `json:"children"`
+	}
+
+	collectionNode struct {
+		ID           string        `json:"id"`
+		Name         string        `json:"name"`
+		WorkspaceID  string        `json:"workspace_id"`
+		CollectionID string        `json:"collection_id"`
+		Tags         []string      `json:"tags"`
+		Kind         string        `json:"kind"`
+		Children     []interface{} `json:"children"`
+	}
+)
+
+// GetTree 获取工作区下的集合树：集合 → 多级文件夹 → 请求，同级先列文件夹再列请求
+// 传入 collection_id 时只返回该集合；传入标签表达式 tag 时只保留满足表达式的请求及其所在目录，
+// 节点的标签为直接设置的标签，筛选时请求继承集合和各级文件夹的标签
+func (h *CollectionHandler) GetTree(c *gin.Context) {
+	result := response.NewResult(c)
+	workspaceID := c.Query("workspace_id")
+	if workspaceID == "" {
+		result.FailWithMsg(response.InvalidParams, "workspace_id is required")
+		return
+	}
+	expr, err := parseTagExpr(c.Query("tag"))
+	if err != nil {
+		result.FailWithMsg(response.InvalidParams, err.Error())
+		return
+	}
+
+	query := h.DB.Where("workspace_id = ?", workspaceID)
+	collectionID := c.Query("collection_id")
+	if collectionID != "" {
+		query = query.Where("collection_id = ?", collectionID)
+	}
+	collections := []model.Collections{}
+	if err := query.Order("id").Find(&collections).Error; err != nil {
+		h.Logger.Error("get collection tree failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "get collection tree failed")
+		return
+	}
+
+	trees, err := service.LoadCollectionTrees(h.DB, collections)
+	if err != nil {
+		h.Logger.Error("load collection tree failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "get collection tree failed")
+		return
+	}
+	if collectionID != "" && len(collections) > 0 {
+		userID, _ := c.Get("user_id")
+		h.recents.Touch(c.Request.Context(), cast.ToUint64(userID), service.KindCollection, collectionID)
+	}
+
+	list := make([]collectionNode, 0, len(trees))
+	for _, tree := range trees {
+		if expr != nil && !tree.Filter(expr) {
+			continue
+		}
+		list = append(list, collectionNode{
+			ID:           cast.ToString(tree.Collection.ID),
+			Name:         tree.Collection.Name,
+			WorkspaceID:  cast.ToString(tree.Collection.WorkspaceID),
+			CollectionID: tree.Collection.CollectionID,
+			Tags:         model.DecodeTags(tree.Collection.Tags),
+			Kind:         "collection",
+			Children:     treeChildren(tree.Folders, tree.Requests),
+		})
+	}
+
+	result.Success(map[string]interface{}{
+		"list": list,
+	})
+}
+
+func treeChildren(folders []*service.FolderNode, requests []*model.Request) []interface{} {
+	children := make([]interface{}, 0, len(folders)+len(requests))
+	for _, f := range folders {
+		children = append(children, folderNode{
+			ID:           cast.ToString(f.Folder.ID),
+			Name:         f.Folder.Name,
+			CollectionID: f.Folder.CollectionID,
+			FolderID:     f.Folder.FolderID,
+			Tags:         model.DecodeTags(f.Folder.Tags),
+			Kind:         "folder",
+			Children:     treeChildren(f.Folders, f.Requests),
+		})
+	}
+	for _, r := range requests {
+		children = append(children, requestNode{
+			ID:           cast.ToString(r.ID),
+			Name:         r.Name,
+			Type:         string(r.Type),
+			Method:       string(r.Method),
+			CollectionID: r.CollectionID,
+			RequestID:    r.RequestID,
+			FolderID:     r.FolderID,
+			Tags:         model.DecodeTags(r.Tags),
+			Kind:         "request",
+		})
+	}
+	return children
+}
+
+// Reorder 拖拽排序后按新的顺序保存同级文件夹和请求，parent_id 为空表示集合根目录
+// items 需包含该父目录下的全部子文件夹和请求
+func (h *CollectionHandler) Reorder(c *gin.Context) {
+	var req struct {
+		CollectionID string                `json:"collection_id" binding:"required"`
+		ParentID     string                `json:"parent_id"`
+		Items        []service.ReorderItem `json:"items" binding:"required,min=1,dive"`
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("reorder collection items failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		return service.Reorder(tx, req.CollectionID, req.ParentID, req.Items)
+	})
+	if errors.Is(err, service.ErrNotSibling) || errors.Is(err, service.ErrIncompleteSiblings) {
+		result.FailWithMsg(response.InvalidParams, err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("reorder collection items failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "reorder failed")
+		return
+	}
+
+	result.Success(nil)
+}
+
+// runItem 集合运行中单个请求的结果
+type runItem struct {
+	RequestID   string `json:"request_id"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	ExecutionID string `json:"execution_id,omitempty"`
+	Success     bool   `json:"success"`
+	Skipped     bool   `json:"skipped,omitempty"`
+	StatusCode  int    `json:"status_code"`
+	Duration    int64  `json:"duration"`
+	Error       string `json:"error,omitempty"`
+	Passed      int    `json:"passed"` // 通过的断言数量
+	Failed      int    `json:"failed"` // 未通过的断言数量
+}
+
+// Run 按树中的顺序依次发送集合（或其中一个文件夹）的请求，每个请求保存发送记录
+// 请求出错或断言未通过视为失败，stop_on_failure 为 true 时遇到失败即停止，SSE 请求跳过
+// 传入标签表达式 tag_expr 时只运行继承标签后满足表达式的请求
+func (h *CollectionHandler) Run(c *gin.Context) {
+	var req struct {
+		CollectionID  string `json:"collection_id" binding:"required"`
+		FolderID      string `json:"folder_id"`
+		EnvironmentID string `json:"environment_id"`
+		StopOnFailure bool   `json:"stop_on_failure"`
+		TagExpr       string `json:"tag_expr" binding:"max=512"`
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("run collection failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+	expr, err := parseTagExpr(req.TagExpr)
+	if err != nil {
+		result.FailWithMsg(response.InvalidParams, err.Error())
+		return
+	}
+
+	var collections []model.Collections
+	if err := h.DB.Where("collection_id = ?", req.CollectionID).Find(&collections).Error; err != nil {
+		h.Logger.Error("query collection failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "run collection failed")
+		return
+	}
+	if len(collections) == 0 {
+		result.FailWithMsg(response.NotFound, "collection not found")
+		return
+	}
+	trees, err := service.LoadCollectionTrees(h.DB, collections)
+	if err != nil {
+		h.Logger.Error("load collection tree failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "run collection failed")
+		return
+	}
+	requests, ok := trees[0].OrderedRequests(req.FolderID)
+	if !ok {
+		result.FailWithMsg(response.NotFound, "folder not found")
+		return
+	}
+	if expr != nil {
+		tags := trees[0].RequestTags()
+		matched := requests[:0]
+		for _, r := range requests {
+			if expr.Match(tags[r.RequestID]) {
+				matched = append(matched, r)
+			}
+		}
+		requests = matched
+	}
+	vars, ok := loadVariables(h.CommonHandler, result, req.EnvironmentID)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	h.recents.Touch(c.Request.Context(), cast.ToUint64(userID), service.KindCollection, req.CollectionID)
+	start := time.Now()
+	items := make([]runItem, 0, len(requests))
+	passed, failed := 0, 0
+	for _, r := range requests {
+		item := runItem{RequestID: r.RequestID, Name: r.Name, Type: string(r.Type)}
+		if r.Type == model.SSE {
+			item.Skipped = true
+			items = append(items, item)
+			continue
+		}
+
+		resolved, err := service.ResolveRequest(h.DB, *r)
+		if err != nil {
+			h.Logger.Error("resolve request defaults failed due to database error", zap.Error(err))
+			result.FailWithMsg(response.ServerError, "run collection failed")
+			return
+		}
+		sent, res, err := h.invoker.Invoke(c.Request.Context(), &invoke.Call{Request: resolved.Request, Variables: vars})
+		execution := invoke.NewExecution(sent, cast.ToUint64(userID), res, err, nil)
+		if err := h.DB.Create(execution).Error; err != nil {
+			h.Logger.Error("save execution failed due to database error", zap.Error(err))
+		}
+
+		item.ExecutionID, item.Success, item.Error = execution.ExecutionID, execution.Success, execution.Error
+		if res != nil {
+			item.StatusCode, item.Duration = res.StatusCode, res.Duration
+			for _, a := range res.Assertions {
+				if a.Passed {
+					item.Passed++
+				} else {
+					item.Failed++
+				}
+			}
+		}
+		item.Success = item.Success && item.Failed == 0
+		items = append(items, item)
+
+		if item.Success {
+			passed++
+		} else {
+			failed++
+			if req.StopOnFailure {
+				break
+			}
+		}
+		if c.Request.Context().Err() != nil {
+			break
+		}
+	}
+
+	result.Success(map[string]interface{}{
+		"total":    len(requests),
+		"passed":   passed,
+		"failed":   failed,
+		"duration": time.Since(start).Milliseconds(),
+		"list":     items,
+	})
+}
+
+// Duplicate 在原工作区中复制集合及其全部内容
+func (h *CollectionHandler) Duplicate(c *gin.Context) {
+	var req struct {
+		CollectionID string `json:"collection_id" binding:"required"`
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("duplicate collection failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	var copied *model.Collections
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		copied, err = service.DuplicateCollection(tx, req.CollectionID, cast.ToUint64(userID))
+		return err
+	})
+	if errors.Is(err, service.ErrCollectionNotFound) {
+		result.FailWithMsg(response.NotFound, err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("duplicate collection failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "duplicate collection failed")
+		return
+	}
+
+	result.Success(copied)
+}
+
+// defaultsInput 集合和文件夹的请求默认值，请求头和认证以结构化形式传入
+type defaultsInput struct {
+	BaseURL    string           `json:"base_url" binding:"omitempty,max=255"`
+	Headers    []model.KeyValue `json:"headers"`
+	Auth       *model.Auth      `json:"auth"`
+	Timeout    int              `json:"timeout" binding:"min=0"`
+	RetryCount int              `json:"retry_count" binding:"min=0,max=10"`
+}
+
+func (in *defaultsInput) toModel() model.Defaults {
+	return model.Defaults{
+		BaseURL:    in.BaseURL,
+		Headers:    model.EncodeKeyValues(in.Headers),
+		Auth:       model.EncodeAuth(in.Auth),
+		Timeout:    in.Timeout,
+		RetryCount: in.RetryCount,
+	}
+}
+
+// SetDefaults 整体替换集合的请求默认值，文件夹和请求未设置的项继承这里的值
+func (h *CollectionHandler) SetDefaults(c *gin.Context) {
+	var req struct {
+		CollectionID string        `json:"collection_id" binding:"required"`
+		Defaults     defaultsInput `json:"defaults"`
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("set collection defaults failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	defaults := req.Defaults.toModel()
+	if err := invoke.ValidateDefaults(&defaults); err != nil {
+		result.FailWithMsg(response.InvalidParams, err.Error())
+		return
+	}
+
+	var count int64
+	if err := h.DB.Model(&model.Collections{}).Where("collection_id = ?", req.CollectionID).Count(&count).Error; err != nil {
+		h.Logger.Error("query collection failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "set collection defaults failed")
+		return
+	}
+	if count == 0 {
+		result.FailWithMsg(response.NotFound, "collection not found")
+		return
+	}
+
+	err := h.DB.Model(&model.Collections{}).Where("collection_id = ?", req.CollectionID).
+		Select("default_base_url", "default_headers", "default_auth", "default_timeout", "default_retry_count").
+		Updates(&model.Collections{Defaults: defaults}).Error
+	if err != nil {
+		h.Logger.Error("set collection defaults failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "set collection defaults failed")
+		return
+	}
+
+	result.Success(defaults)
+}
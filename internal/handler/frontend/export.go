@@ -0,0 +1,105 @@
+package frontend
+
+import (
+	"FastGo/internal/handler"
+	"FastGo/internal/model"
+	"FastGo/internal/router"
+	"FastGo/internal/service/exporter"
+	"FastGo/pkg/response"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type ExportHandler struct {
+	*handler.CommonHandler
+}
+
+func NewExportHandler() *ExportHandler {
+	return &ExportHandler{
+		CommonHandler: handler.NewCommonHandler(),
+	}
+}
+
+func (h *ExportHandler) RegisterRoutes(routerRegistry *router.RouteRegistry) {
+	routerRegistry.Register("GET", "export", "/collection", h.Collection, 2, "导出集合")
+}
+
+// Collection 导出集合为文件下载
+// format: native（默认，可无损导回）、postman（Postman v2.1，可通过 environment_id 附带环境变量）、
+// openapi（OpenAPI 3.1，被跳过的请求通过 X-Export-Warnings 响应头返回）
+func (h *ExportHandler) Collection(c *gin.Context) {
+	result := response.NewResult(c)
+
+	collectionID := c.Query("collection_id")
+	if collectionID == "" {
+		result.FailWithMsg(response.InvalidParams, "collection_id is required")
+		return
+	}
+	format := c.DefaultQuery("format", "native")
+
+	var collection model.Collections
+	if err := h.DB.Where("collection_id = ?", collectionID).First(&collection).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			result.FailWithMsg(response.NotFound, "collection not found")
+			return
+		}
+		h.Logger.Error("export collection failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "export collection failed")
+		return
+	}
+
+	data, err := exporter.Load(h.DB, collection)
+	if err != nil {
+		h.Logger.Error("load collection tree failed", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "export collection failed")
+		return
+	}
+
+	var doc interface{}
+	var suffix string
+	switch format {
+	case "native":
+		doc, suffix = exporter.Native(data), "rpc-master"
+	case "postman":
+		var environment *model.Environment
+		if environmentID := c.Query("environment_id"); environmentID != "" {
+			for i := range data.Environments {
+				if data.Environments[i].EnvironmentID == environmentID {
+					environment = &data.Environments[i]
+				}
+			}
+			if environment == nil {
+				result.FailWithMsg(response.NotFound, "environment not found")
+				return
+			}
+		}
+		doc, suffix = exporter.Postman(data, environment), "postman_collection"
+	case "openapi":
+		spec, warnings := exporter.OpenAPI(data)
+		if len(warnings) > 0 {
+			encoded, _ := json.Marshal(warnings)
+			c.Header("X-Export-Warnings", url.QueryEscape(string(encoded)))
+		}
+		doc, suffix = spec, "openapi"
+	default:
+		result.FailWithMsg(response.InvalidParams, "unsupported export format")
+		return
+	}
+
+	body, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		h.Logger.Error("encode export document failed", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "export collection failed")
+		return
+	}
+
+	filename := fmt.Sprintf("%s.%s.json", collection.Name, suffix)
+	c.Header("Content-Disposition", "attachment; filename*=UTF-8''"+url.PathEscape(filename))
+	c.Data(200, "application/json; charset=utf-8", body)
+}
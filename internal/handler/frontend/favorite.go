@@ -0,0 +1,215 @@
+package frontend
+
+import (
+	"FastGo/internal/global"
+	"FastGo/internal/handler"
+	"FastGo/internal/model"
+	"FastGo/internal/router"
+	"FastGo/internal/service"
+	"FastGo/pkg/response"
+	"FastGo/pkg/validator"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"go.uber.org/zap"
+)
+
+type FavoriteHandler struct {
+	*handler.CommonHandler
+	recents *service.RecentStore
+}
+
+func NewFavoriteHandler() *FavoriteHandler {
+	common := handler.NewCommonHandler()
+	return &FavoriteHandler{
+		CommonHandler: common,
+		recents:       service.NewRecentStore(common.Redis, global.Config.Recent),
+	}
+}
+
+func (h *FavoriteHandler) RegisterRoutes(routerRegistry *router.RouteRegistry) {
+	routerRegistry.Register("GET", "favorites", "/list", h.List, 2, "获取当前用户置顶的集合和请求")
+	routerRegistry.Register("POST", "favorites", "/pin", h.Pin, 2, "置顶集合或请求")
+	routerRegistry.Register("POST", "favorites", "/unpin", h.Unpin, 2, "取消置顶")
+	routerRegistry.Register("GET", "recents", "/list", h.Recents, 2, "获取当前用户最近使用的集合和请求")
+	routerRegistry.Register("POST", "recents", "/remove", h.RemoveRecent, 2, "从最近使用中移除")
+	routerRegistry.Register("POST", "recents", "/clear", h.ClearRecents, 2, "清空最近使用")
+}
+
+// itemInput 置顶和最近使用的条目
+type itemInput struct {
+	Kind string `json:"kind" binding:"required,oneof=collection request"`
+	ID   string `json:"id" binding:"required"`
+}
+
+// List 按置顶时间倒序列出当前用户置顶的条目，已删除的条目不返回，从回收站恢复后重新出现
+func (h *FavoriteHandler) List(c *gin.Context) {
+	result := response.NewResult(c)
+	userID, _ := c.Get("user_id")
+
+	var favorites []model.Favorite
+	if err := h.DB.Where("user_id = ?", cast.ToUint64(userID)).Order("id DESC").Find(&favorites).Error; err != nil {
+		h.Logger.Error("list favorites failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "list favorites failed")
+		return
+	}
+	refs := make([]service.ItemRef, 0, len(favorites))
+	for _, f := range favorites {
+		refs = append(refs, service.ItemRef{Kind: f.Kind, ID: f.TargetID})
+	}
+	summaries, err := service.DescribeItems(h.DB, refs)
+	if err != nil {
+		h.Logger.Error("describe favorites failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "list favorites failed")
+		return
+	}
+
+	type favoriteItem struct {
+		service.ItemSummary
+		PinnedAt time.Time `json:"pinned_at"`
+	}
+	list := make([]favoriteItem, 0, len(favorites))
+	for i, ref := range refs {
+		if s, ok := summaries[ref]; ok {
+			list = append(list, favoriteItem{ItemSummary: s, PinnedAt: favorites[i].CreatedAt})
+		}
+	}
+
+	result.Success(map[string]interface{}{
+		"list": list,
+	})
+}
+
+// Pin 置顶集合或请求，重复置顶不报错
+func (h *FavoriteHandler) Pin(c *gin.Context) {
+	var req itemInput
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("pin item failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	ref := service.ItemRef{Kind: req.Kind, ID: req.ID}
+	summaries, err := service.DescribeItems(h.DB, []service.ItemRef{ref})
+	if err != nil {
+		h.Logger.Error("query pinned item failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "pin failed")
+		return
+	}
+	if _, ok := summaries[ref]; !ok {
+		result.FailWithMsg(response.NotFound, req.Kind+" not found")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	favorite := model.Favorite{UserID: cast.ToUint64(userID), Kind: req.Kind, TargetID: req.ID}
+	err = h.DB.Where("user_id = ? AND kind = ? AND target_id = ?", favorite.UserID, favorite.Kind, favorite.TargetID).
+		FirstOrCreate(&favorite).Error
+	if err != nil {
+		h.Logger.Error("pin item failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "pin failed")
+		return
+	}
+
+	result.Success(favorite)
+}
+
+// Unpin 取消置顶
+func (h *FavoriteHandler) Unpin(c *gin.Context) {
+	var req itemInput
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("unpin item failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	res := h.DB.Where("user_id = ? AND kind = ? AND target_id = ?", cast.ToUint64(userID), req.Kind, req.ID).
+		Delete(&model.Favorite{})
+	if res.Error != nil {
+		h.Logger.Error("unpin item failed due to database error", zap.Error(res.Error))
+		result.FailWithMsg(response.ServerError, "unpin failed")
+		return
+	}
+	if res.RowsAffected == 0 {
+		result.FailWithMsg(response.NotFound, "favorite not found")
+		return
+	}
+
+	result.Success(nil)
+}
+
+// Recents 按最后使用时间倒序列出最近使用的条目，已删除的条目不返回
+func (h *FavoriteHandler) Recents(c *gin.Context) {
+	result := response.NewResult(c)
+	userID, _ := c.Get("user_id")
+
+	items, err := h.recents.List(c.Request.Context(), cast.ToUint64(userID), cast.ToInt(c.Query("limit")))
+	if err != nil {
+		h.Logger.Error("list recent items failed due to redis error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "list recent items failed")
+		return
+	}
+	refs := make([]service.ItemRef, 0, len(items))
+	for _, item := range items {
+		refs = append(refs, item.ItemRef)
+	}
+	summaries, err := service.DescribeItems(h.DB, refs)
+	if err != nil {
+		h.Logger.Error("describe recent items failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "list recent items failed")
+		return
+	}
+
+	type recentItem struct {
+		service.ItemSummary
+		UsedAt time.Time `json:"used_at"`
+	}
+	list := make([]recentItem, 0, len(items))
+	for _, item := range items {
+		if s, ok := summaries[item.ItemRef]; ok {
+			list = append(list, recentItem{ItemSummary: s, UsedAt: item.UsedAt})
+		}
+	}
+
+	result.Success(map[string]interface{}{
+		"list": list,
+	})
+}
+
+// RemoveRecent 从最近使用中移除一个条目
+func (h *FavoriteHandler) RemoveRecent(c *gin.Context) {
+	var req itemInput
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("remove recent item failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	ref := service.ItemRef{Kind: req.Kind, ID: req.ID}
+	if err := h.recents.Remove(c.Request.Context(), cast.ToUint64(userID), ref); err != nil {
+		h.Logger.Error("remove recent item failed due to redis error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "remove recent item failed")
+		return
+	}
+
+	result.Success(nil)
+}
+
+// ClearRecents 清空当前用户的最近使用
+func (h *FavoriteHandler) ClearRecents(c *gin.Context) {
+	result := response.NewResult(c)
+	userID, _ := c.Get("user_id")
+	if err := h.recents.Clear(c.Request.Context(), cast.ToUint64(userID)); err != nil {
+		h.Logger.Error("clear recent items failed due to redis error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "clear recent items failed")
+		return
+	}
+
+	result.Success(nil)
+}
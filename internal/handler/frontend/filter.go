@@ -0,0 +1,189 @@
+package frontend
+
+import (
+	"FastGo/internal/handler"
+	"FastGo/internal/model"
+	"FastGo/internal/router"
+	"FastGo/pkg/response"
+	"FastGo/pkg/uid"
+	"FastGo/pkg/validator"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"go.uber.org/zap"
+)
+
+type FilterHandler struct {
+	*handler.CommonHandler
+}
+
+func NewFilterHandler() *FilterHandler {
+	return &FilterHandler{
+		CommonHandler: handler.NewCommonHandler(),
+	}
+}
+
+func (h *FilterHandler) RegisterRoutes(routerRegistry *router.RouteRegistry) {
+	routerRegistry.Register("GET", "filters", "/list", h.List, 2, "获取当前用户在工作区内保存的筛选条件")
+	routerRegistry.Register("POST", "filters", "/create", h.Create, 2, "保存筛选条件")
+	routerRegistry.Register("POST", "filters", "/update", h.Update, 2, "修改筛选条件")
+	routerRegistry.Register("POST", "filters", "/delete", h.Delete, 2, "删除筛选条件")
+}
+
+// filterFields 筛选条件的可编辑字段，标签表达式和搜索关键词至少填写一项
+type filterFields struct {
+	Name    string   `json:"name" binding:"required,max=128"`
+	TagExpr string   `json:"tag_expr" binding:"max=512"`
+	Query   string   `json:"query" binding:"max=255"`
+	Types   []string `json:"types" binding:"dive,oneof=HTTP WebSocket gRPC GraphQL SSE JSON-RPC"`
+	Methods []string `json:"methods" binding:"dive,oneof=GET POST PUT DELETE PATCH HEAD OPTIONS"`
+}
+
+// apply 校验并写入筛选条件，标签表达式保存为规范化的形式
+func (f *filterFields) apply(filter *model.SavedFilter) error {
+	expr, err := parseTagExpr(f.TagExpr)
+	if err != nil {
+		return err
+	}
+	filter.TagExpr = ""
+	if expr != nil {
+		filter.TagExpr = expr.String()
+	}
+	filter.Name = f.Name
+	filter.Query = strings.TrimSpace(f.Query)
+	filter.Types = strings.Join(f.Types, ",")
+	filter.Methods = strings.Join(f.Methods, ",")
+	return nil
+}
+
+// List 列出当前用户在工作区内保存的筛选条件
+func (h *FilterHandler) List(c *gin.Context) {
+	result := response.NewResult(c)
+	workspaceID := c.Query("workspace_id")
+	if workspaceID == "" {
+		result.FailWithMsg(response.InvalidParams, "workspace_id is required")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	filters := []model.SavedFilter{}
+	err := h.DB.Where("user_id = ? AND workspace_id = ?", cast.ToUint64(userID), cast.ToUint64(workspaceID)).
+		Order("id").Find(&filters).Error
+	if err != nil {
+		h.Logger.Error("list saved filters failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "list saved filters failed")
+		return
+	}
+
+	result.Success(map[string]interface{}{
+		"list": filters,
+	})
+}
+
+// Create 保存筛选条件
+func (h *FilterHandler) Create(c *gin.Context) {
+	var req struct {
+		WorkspaceID uint64 `json:"workspace_id" binding:"required"`
+		filterFields
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("create saved filter failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	filter := model.SavedFilter{
+		FilterID:    uid.NewUUID(),
+		UserID:      cast.ToUint64(userID),
+		WorkspaceID: req.WorkspaceID,
+	}
+	if err := req.apply(&filter); err != nil {
+		result.FailWithMsg(response.InvalidParams, err.Error())
+		return
+	}
+	if filter.TagExpr == "" && filter.Query == "" {
+		result.FailWithMsg(response.InvalidParams, "tag_expr or query is required")
+		return
+	}
+
+	if err := h.DB.Create(&filter).Error; err != nil {
+		h.Logger.Error("create saved filter failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "create saved filter failed")
+		return
+	}
+
+	result.Success(filter)
+}
+
+// Update 修改当前用户的筛选条件，整体替换可编辑字段
+func (h *FilterHandler) Update(c *gin.Context) {
+	var req struct {
+		FilterID string `json:"filter_id" binding:"required"`
+		filterFields
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("update saved filter failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	var filters []model.SavedFilter
+	if err := h.DB.Where("filter_id = ? AND user_id = ?", req.FilterID, cast.ToUint64(userID)).Find(&filters).Error; err != nil {
+		h.Logger.Error("query saved filter failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "update saved filter failed")
+		return
+	}
+	if len(filters) == 0 {
+		result.FailWithMsg(response.NotFound, "saved filter not found")
+		return
+	}
+	filter := filters[0]
+	if err := req.apply(&filter); err != nil {
+		result.FailWithMsg(response.InvalidParams, err.Error())
+		return
+	}
+	if filter.TagExpr == "" && filter.Query == "" {
+		result.FailWithMsg(response.InvalidParams, "tag_expr or query is required")
+		return
+	}
+
+	if err := h.DB.Save(&filter).Error; err != nil {
+		h.Logger.Error("update saved filter failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "update saved filter failed")
+		return
+	}
+
+	result.Success(filter)
+}
+
+// Delete 删除当前用户的筛选条件
+func (h *FilterHandler) Delete(c *gin.Context) {
+	var req struct {
+		FilterID string `json:"filter_id" binding:"required"`
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("delete saved filter failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	res := h.DB.Where("filter_id = ? AND user_id = ?", req.FilterID, cast.ToUint64(userID)).Delete(&model.SavedFilter{})
+	if res.Error != nil {
+		h.Logger.Error("delete saved filter failed due to database error", zap.Error(res.Error))
+		result.FailWithMsg(response.ServerError, "delete saved filter failed")
+		return
+	}
+	if res.RowsAffected == 0 {
+		result.FailWithMsg(response.NotFound, "saved filter not found")
+		return
+	}
+
+	result.Success(nil)
+}
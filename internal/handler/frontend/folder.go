@@ -4,12 +4,17 @@ import (
 	"FastGo/internal/handler"
 	"FastGo/internal/model"
 	"FastGo/internal/router"
+	"FastGo/internal/service"
+	"FastGo/internal/service/invoke"
 	"FastGo/pkg/response"
 	"FastGo/pkg/uid"
 	"FastGo/pkg/validator"
+	"errors"
 
 	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type FolderHandler struct {
@@ -26,6 +31,9 @@ func (h *FolderHandler) RegisterRoutes(routerRegistry *router.RouteRegistry) {
 	routerRegistry.Register("POST", "folder", "/create", h.Create, 2, "创建文件夹")
 	routerRegistry.Register("POST", "folder", "/delete", h.Delete, 2, "删除文件夹")
 	routerRegistry.Register("POST", "folder", "/rename", h.Rename, 2, "重命名文件夹")
+	routerRegistry.Register("POST", "folder", "/move", h.Move, 2, "移动文件夹")
+	routerRegistry.Register("POST", "folder", "/defaults", h.SetDefaults, 2, "设置文件夹的请求默认值")
+	routerRegistry.Register("POST", "folder", "/duplicate", h.Duplicate, 2, "复制文件夹")
 	routerRegistry.Register("GET", "folder", "/list", h.List, 2, "获取文件夹列表")
 }
 
@@ -55,11 +63,18 @@ func (h *FolderHandler) Create(c *gin.Context) {
 		return
 	}
 
-	// 创建新文件夹
+	// 创建新文件夹，排在同级已有文件夹之后
+	order, err := service.NextSortOrder(h.DB, &model.Folder{}, collection.CollectionID, req.FolderID)
+	if err != nil {
+		h.Logger.Error("get folder sort order failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "create folder failed")
+		return
+	}
 	folder := model.Folder{
 		CollectionID: collection.CollectionID,
 		Name:         req.Name,
 		FolderID:     uid.NewUUID(),
+		SortOrder:    order,
 	}
 
 	if err := h.DB.Create(&folder).Error; err != nil {
@@ -130,9 +145,12 @@ func (h *FolderHandler) Create(c *gin.Context) {
 }
 
 // Delete 删除文件夹的处理函数
+// 默认删除整个子树，rehome 为 true 时子文件夹和请求移动到上级目录，返回删除统计
 func (h *FolderHandler) Delete(c *gin.Context) {
 	var req struct {
-		ID uint64 `json:"id" binding:"required"`
+		ID       uint64 `json:"id"`
+		FolderID string `json:"folder_id"`
+		Rehome   bool   `json:"rehome"`
 	}
 	result := response.NewResult(c)
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -140,14 +158,40 @@ func (h *FolderHandler) Delete(c *gin.Context) {
 		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
 		return
 	}
+	if req.ID == 0 && req.FolderID == "" {
+		result.FailWithMsg(response.InvalidParams, "id or folder_id is required")
+		return
+	}
 
-	if err := h.DB.Delete(&model.Folder{}, req.ID).Error; err != nil {
+	userID, _ := c.Get("user_id")
+	var report *service.DeleteReport
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		folderID := req.FolderID
+		if folderID == "" {
+			var folder model.Folder
+			if err := tx.First(&folder, req.ID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return service.ErrFolderNotFound
+				}
+				return err
+			}
+			folderID = folder.FolderID
+		}
+		var err error
+		report, err = service.DeleteFolder(tx, folderID, req.Rehome, cast.ToUint64(userID))
+		return err
+	})
+	if errors.Is(err, service.ErrFolderNotFound) {
+		result.FailWithMsg(response.NotFound, err.Error())
+		return
+	}
+	if err != nil {
 		h.Logger.Error("delete folder failed due to database error", zap.Error(err))
 		result.FailWithMsg(response.ServerError, "delete folder failed")
 		return
 	}
 
-	result.Success(nil)
+	result.Success(report)
 }
 
 // Rename 重命名文件夹的处理函数
@@ -175,6 +219,43 @@ func (h *FolderHandler) Rename(c *gin.Context) {
 	})
 }
 
+// Move 将文件夹及其子树移动到其他文件夹下或集合根目录，可跨集合移动
+func (h *FolderHandler) Move(c *gin.Context) {
+	var req struct {
+		FolderID     string `json:"folder_id" binding:"required"`
+		ParentID     string `json:"parent_id"`
+		CollectionID string `json:"collection_id"`
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("move folder failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		return service.MoveFolder(tx, req.FolderID, req.ParentID, req.CollectionID, cast.ToUint64(userID))
+	})
+	switch {
+	case errors.Is(err, service.ErrFolderNotFound), errors.Is(err, service.ErrCollectionNotFound):
+		result.FailWithMsg(response.NotFound, err.Error())
+		return
+	case errors.Is(err, service.ErrMoveIntoDescendant), errors.Is(err, service.ErrParentInCollection):
+		result.FailWithMsg(response.InvalidParams, err.Error())
+		return
+	case err != nil:
+		h.Logger.Error("move folder failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "move folder failed")
+		return
+	}
+
+	result.Success(map[string]interface{}{
+		"folder_id": req.FolderID,
+		"parent_id": req.ParentID,
+	})
+}
+
 // List 获取文件夹列表
 func (h *FolderHandler) List(c *gin.Context) {
 	result := response.NewResult(c)
@@ -191,3 +272,77 @@ func (h *FolderHandler) List(c *gin.Context) {
 		"list": folders,
 	})
 }
+
+// Duplicate 在同一父目录下复制文件夹及其子树
+func (h *FolderHandler) Duplicate(c *gin.Context) {
+	var req struct {
+		FolderID string `json:"folder_id" binding:"required"`
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("duplicate folder failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	var copied *model.Folder
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		copied, err = service.DuplicateFolder(tx, req.FolderID, cast.ToUint64(userID))
+		return err
+	})
+	if errors.Is(err, service.ErrFolderNotFound) {
+		result.FailWithMsg(response.NotFound, err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("duplicate folder failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "duplicate folder failed")
+		return
+	}
+
+	result.Success(copied)
+}
+
+// SetDefaults 整体替换文件夹的请求默认值，覆盖集合和上级文件夹的同名设置
+func (h *FolderHandler) SetDefaults(c *gin.Context) {
+	var req struct {
+		FolderID string        `json:"folder_id" binding:"required"`
+		Defaults defaultsInput `json:"defaults"`
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("set folder defaults failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	defaults := req.Defaults.toModel()
+	if err := invoke.ValidateDefaults(&defaults); err != nil {
+		result.FailWithMsg(response.InvalidParams, err.Error())
+		return
+	}
+
+	var count int64
+	if err := h.DB.Model(&model.Folder{}).Where("folder_id = ?", req.FolderID).Count(&count).Error; err != nil {
+		h.Logger.Error("query folder failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "set folder defaults failed")
+		return
+	}
+	if count == 0 {
+		result.FailWithMsg(response.NotFound, "folder not found")
+		return
+	}
+
+	err := h.DB.Model(&model.Folder{}).Where("folder_id = ?", req.FolderID).
+		Select("default_base_url", "default_headers", "default_auth", "default_timeout", "default_retry_count").
+		Updates(&model.Folder{Defaults: defaults}).Error
+	if err != nil {
+		h.Logger.Error("set folder defaults failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "set folder defaults failed")
+		return
+	}
+
+	result.Success(defaults)
+}
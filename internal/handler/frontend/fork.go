@@ -0,0 +1,399 @@
+package frontend
+
+import (
+	"FastGo/internal/handler"
+	"FastGo/internal/model"
+	"FastGo/internal/router"
+	"FastGo/internal/service"
+	"FastGo/pkg/response"
+	"FastGo/pkg/uid"
+	"FastGo/pkg/validator"
+	"encoding/json"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type ForkHandler struct {
+	*handler.CommonHandler
+}
+
+func NewForkHandler() *ForkHandler {
+	return &ForkHandler{
+		CommonHandler: handler.NewCommonHandler(),
+	}
+}
+
+func (h *ForkHandler) RegisterRoutes(routerRegistry *router.RouteRegistry) {
+	routerRegistry.Register("POST", "collections", "/fork", h.Fork, 2, "将集合复制到个人工作区作为分支")
+	routerRegistry.Register("GET", "forks", "/list", h.ListForks, 2, "获取集合的分支列表")
+	routerRegistry.Register("POST", "merges", "/create", h.CreateMerge, 2, "创建合并请求")
+	routerRegistry.Register("GET", "merges", "/get", h.GetMerge, 2, "获取合并请求及三方差异")
+	routerRegistry.Register("GET", "merges", "/list", h.ListMerges, 2, "获取合并到集合的合并请求列表")
+	routerRegistry.Register("POST", "merges", "/resolve", h.Resolve, 2, "处理合并冲突")
+	routerRegistry.Register("POST", "merges", "/apply", h.Apply, 2, "执行合并")
+	routerRegistry.Register("POST", "merges", "/close", h.Close, 2, "关闭合并请求")
+}
+
+// Fork 将集合复制到当前用户拥有的工作区，记录复制时的内容作为以后合并的共同版本
+func (h *ForkHandler) Fork(c *gin.Context) {
+	var req struct {
+		CollectionID string `json:"collection_id" binding:"required"`
+		WorkspaceID  uint64 `json:"workspace_id" binding:"required"`
+		Name         string `json:"name" binding:"omitempty,max=128"`
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("fork collection failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	ownerID := cast.ToUint64(userID)
+	var workspace model.Workspace
+	if err := h.DB.Where("id = ?", req.WorkspaceID).First(&workspace).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			result.FailWithMsg(response.NotFound, "workspace not found")
+			return
+		}
+		h.Logger.Error("query workspace failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "fork collection failed")
+		return
+	}
+	if workspace.OwnerID != ownerID {
+		result.FailWithMsg(response.InvalidParams, "can only fork into your own workspace")
+		return
+	}
+
+	var copied *model.Collections
+	var fork *model.CollectionFork
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		copied, fork, err = service.ForkCollection(tx, req.CollectionID, req.WorkspaceID, ownerID, req.Name)
+		return err
+	})
+	if errors.Is(err, service.ErrCollectionNotFound) {
+		result.FailWithMsg(response.NotFound, err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("fork collection failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "fork collection failed")
+		return
+	}
+
+	result.Success(map[string]interface{}{
+		"fork":       fork,
+		"collection": copied,
+	})
+}
+
+// ListForks 列出集合的分支，collection_id 可以是源集合也可以是分支集合
+func (h *ForkHandler) ListForks(c *gin.Context) {
+	result := response.NewResult(c)
+	collectionID := c.Query("collection_id")
+	if collectionID == "" {
+		result.FailWithMsg(response.InvalidParams, "collection_id is required")
+		return
+	}
+
+	var forks []model.CollectionFork
+	err := h.DB.Where("source_collection_id = ? OR fork_collection_id = ?", collectionID, collectionID).
+		Order("id DESC").Find(&forks).Error
+	if err != nil {
+		h.Logger.Error("list forks failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "list forks failed")
+		return
+	}
+
+	result.Success(map[string]interface{}{
+		"list": forks,
+	})
+}
+
+// CreateMerge 为分支创建合并请求，同一分支同时只能有一个未关闭的合并请求
+func (h *ForkHandler) CreateMerge(c *gin.Context) {
+	var req struct {
+		ForkID string `json:"fork_id" binding:"required"`
+		Title  string `json:"title" binding:"required,max=255"`
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("create merge request failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	mr := model.MergeRequest{
+		MergeID:  uid.NewUUID(),
+		ForkID:   req.ForkID,
+		Title:    req.Title,
+		Status:   model.MergeOpen,
+		AuthorID: cast.ToUint64(userID),
+	}
+	var exists bool
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := service.FindFork(tx, req.ForkID); err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&model.MergeRequest{}).Where("fork_id = ? AND status = ?", req.ForkID, model.MergeOpen).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if exists = count > 0; exists {
+			return nil
+		}
+		return tx.Create(&mr).Error
+	})
+	if errors.Is(err, service.ErrForkNotFound) {
+		result.FailWithMsg(response.NotFound, err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("create merge request failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "create merge request failed")
+		return
+	}
+	if exists {
+		result.FailWithMsg(response.InvalidParams, "fork already has an open merge request")
+		return
+	}
+
+	result.Success(mr)
+}
+
+// mergeSummary 合并请求中各类变化的数量
+func mergeSummary(changes []service.MergeChange) map[string]int {
+	summary := map[string]int{
+		service.ChangeAdded:    0,
+		service.ChangeModified: 0,
+		service.ChangeDeleted:  0,
+		"conflicts":            0,
+		"unresolved":           0,
+	}
+	for i := range changes {
+		summary[changes[i].Action]++
+		if changes[i].Conflict != "" {
+			summary["conflicts"]++
+		}
+		if changes[i].Unresolved() {
+			summary["unresolved"]++
+		}
+	}
+	return summary
+}
+
+// GetMerge 获取合并请求，未关闭的合并请求返回按当前内容计算的三方差异
+func (h *ForkHandler) GetMerge(c *gin.Context) {
+	result := response.NewResult(c)
+	mergeID := c.Query("merge_id")
+	if mergeID == "" {
+		result.FailWithMsg(response.InvalidParams, "merge_id is required")
+		return
+	}
+
+	mr, err := service.FindMergeRequest(h.DB, mergeID)
+	if errors.Is(err, service.ErrMergeNotFound) {
+		result.FailWithMsg(response.NotFound, err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("query merge request failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "get merge request failed")
+		return
+	}
+
+	data := map[string]interface{}{"merge": mr}
+	if mr.Status == model.MergeOpen {
+		changes, err := service.DiffMerge(h.DB, mr)
+		if errors.Is(err, service.ErrForkNotFound) || errors.Is(err, service.ErrCollectionNotFound) {
+			result.FailWithMsg(response.NotFound, err.Error())
+			return
+		}
+		if err != nil {
+			h.Logger.Error("diff merge request failed due to database error", zap.Error(err))
+			result.FailWithMsg(response.ServerError, "get merge request failed")
+			return
+		}
+		data["changes"] = changes
+		data["summary"] = mergeSummary(changes)
+	}
+
+	result.Success(data)
+}
+
+// ListMerges 列出合并到集合的合并请求，可按状态筛选
+func (h *ForkHandler) ListMerges(c *gin.Context) {
+	result := response.NewResult(c)
+	collectionID := c.Query("collection_id")
+	if collectionID == "" {
+		result.FailWithMsg(response.InvalidParams, "collection_id is required")
+		return
+	}
+
+	query := h.DB.Where("fork_id IN (?)",
+		h.DB.Model(&model.CollectionFork{}).Select("fork_id").Where("source_collection_id = ?", collectionID))
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var merges []model.MergeRequest
+	if err := query.Order("id DESC").Find(&merges).Error; err != nil {
+		h.Logger.Error("list merge requests failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "list merge requests failed")
+		return
+	}
+
+	result.Success(map[string]interface{}{
+		"list": merges,
+	})
+}
+
+// Resolve 按条目设置冲突的处理方式，resolution 为空时清除已设置的处理方式
+func (h *ForkHandler) Resolve(c *gin.Context) {
+	var req struct {
+		MergeID     string `json:"merge_id" binding:"required"`
+		Resolutions []struct {
+			Key        string `json:"key" binding:"required"`
+			Resolution string `json:"resolution" binding:"omitempty,oneof=ours theirs"`
+		} `json:"resolutions" binding:"required,min=1,dive"`
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("resolve merge conflicts failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	var changes []service.MergeChange
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		mr, err := service.FindMergeRequest(tx, req.MergeID)
+		if err != nil {
+			return err
+		}
+		if mr.Status != model.MergeOpen {
+			return service.ErrMergeNotOpen
+		}
+		resolutions := service.DecodeResolutions(mr.Resolutions)
+		for _, r := range req.Resolutions {
+			if r.Resolution == "" {
+				delete(resolutions, r.Key)
+			} else {
+				resolutions[r.Key] = r.Resolution
+			}
+		}
+		data, err := json.Marshal(resolutions)
+		if err != nil {
+			return err
+		}
+		mr.Resolutions = string(data)
+		if err := tx.Model(mr).Update("resolutions", mr.Resolutions).Error; err != nil {
+			return err
+		}
+		changes, err = service.DiffMerge(tx, mr)
+		return err
+	})
+	if errors.Is(err, service.ErrMergeNotFound) || errors.Is(err, service.ErrForkNotFound) {
+		result.FailWithMsg(response.NotFound, err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrMergeNotOpen) {
+		result.FailWithMsg(response.InvalidParams, err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("resolve merge conflicts failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "resolve merge conflicts failed")
+		return
+	}
+
+	result.Success(map[string]interface{}{
+		"changes": changes,
+		"summary": mergeSummary(changes),
+	})
+}
+
+// Apply 在一个事务中将分支的修改写入源集合，有未处理的冲突时不做任何修改
+func (h *ForkHandler) Apply(c *gin.Context) {
+	var req struct {
+		MergeID string `json:"merge_id" binding:"required"`
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("apply merge failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	var mr *model.MergeRequest
+	var changes []service.MergeChange
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		mr, changes, err = service.ApplyMerge(tx, req.MergeID, cast.ToUint64(userID))
+		return err
+	})
+	switch {
+	case errors.Is(err, service.ErrMergeNotFound), errors.Is(err, service.ErrForkNotFound),
+		errors.Is(err, service.ErrCollectionNotFound):
+		result.FailWithMsg(response.NotFound, err.Error())
+		return
+	case errors.Is(err, service.ErrUnresolvedConflicts), errors.Is(err, service.ErrMergeNotOpen),
+		errors.Is(err, service.ErrMoveIntoDescendant):
+		result.FailWithMsg(response.InvalidParams, err.Error())
+		return
+	case err != nil:
+		h.Logger.Error("apply merge failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "apply merge failed")
+		return
+	}
+
+	result.Success(map[string]interface{}{
+		"merge":   mr,
+		"changes": changes,
+		"summary": mergeSummary(changes),
+	})
+}
+
+// Close 关闭未合并的合并请求
+func (h *ForkHandler) Close(c *gin.Context) {
+	var req struct {
+		MergeID string `json:"merge_id" binding:"required"`
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("close merge request failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	mr, err := service.FindMergeRequest(h.DB, req.MergeID)
+	if errors.Is(err, service.ErrMergeNotFound) {
+		result.FailWithMsg(response.NotFound, err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("query merge request failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "close merge request failed")
+		return
+	}
+	res := h.DB.Model(&model.MergeRequest{}).Where("id = ? AND status = ?", mr.ID, model.MergeOpen).
+		Update("status", model.MergeClosed)
+	if res.Error != nil {
+		h.Logger.Error("close merge request failed due to database error", zap.Error(res.Error))
+		result.FailWithMsg(response.ServerError, "close merge request failed")
+		return
+	}
+	if res.RowsAffected == 0 {
+		result.FailWithMsg(response.InvalidParams, service.ErrMergeNotOpen.Error())
+		return
+	}
+
+	mr.Status = model.MergeClosed
+	result.Success(mr)
+}
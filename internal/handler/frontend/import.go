@@ -0,0 +1,287 @@
+package frontend
+
+import (
+	"FastGo/internal/handler"
+	"FastGo/internal/model"
+	"FastGo/internal/router"
+	"FastGo/internal/service/importer"
+	"FastGo/pkg/response"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 导入文件大小上限
+const maxImportFileSize = 20 << 20
+
+type ImportHandler struct {
+	*handler.CommonHandler
+}
+
+func NewImportHandler() *ImportHandler {
+	return &ImportHandler{
+		CommonHandler: handler.NewCommonHandler(),
+	}
+}
+
+func (h *ImportHandler) RegisterRoutes(routerRegistry *router.RouteRegistry) {
+	routerRegistry.Register("POST", "import", "/openapi", h.OpenAPI, 2, "导入 OpenAPI/Swagger 文档")
+	routerRegistry.Register("POST", "import", "/har/preview", h.HARPreview, 2, "预览 HAR 文件")
+	routerRegistry.Register("POST", "import", "/har", h.HAR, 2, "导入 HAR 文件")
+	routerRegistry.Register("POST", "import", "/insomnia", h.Insomnia, 2, "导入 Insomnia v4 导出文件")
+	routerRegistry.Register("POST", "import", "/bruno", h.Bruno, 2, "导入 Bruno 集合压缩包")
+	routerRegistry.Register("POST", "import", "/native", h.Native, 2, "导入原生导出文件")
+}
+
+// OpenAPI 导入 OpenAPI 3 或 Swagger 2 文档
+func (h *ImportHandler) OpenAPI(c *gin.Context) {
+	result := response.NewResult(c)
+
+	opts, ok := h.importOptions(c, result, true)
+	if !ok {
+		return
+	}
+	data, ok := h.readUpload(c, result)
+	if !ok {
+		return
+	}
+
+	col, warnings, err := importer.ParseOpenAPI(data)
+	if err != nil {
+		h.Logger.Error("parse openapi spec failed", zap.Error(err))
+		result.FailWithError(response.InvalidParams, err.Error())
+		return
+	}
+
+	h.save(result, col, opts, warnings)
+}
+
+// HARPreview 解析 HAR 文件，返回条目列表及域名、内容类型统计，供选择要导入的条目
+func (h *ImportHandler) HARPreview(c *gin.Context) {
+	result := response.NewResult(c)
+
+	data, ok := h.readUpload(c, result)
+	if !ok {
+		return
+	}
+
+	har, err := importer.ParseHAR(data)
+	if err != nil {
+		h.Logger.Error("parse har file failed", zap.Error(err))
+		result.FailWithError(response.InvalidParams, err.Error())
+		return
+	}
+
+	result.Success(har.Preview())
+}
+
+// HAR 将 HAR 文件中选中的条目导入到集合下的新文件夹，响应保存为示例
+func (h *ImportHandler) HAR(c *gin.Context) {
+	result := response.NewResult(c)
+
+	opts, ok := h.importOptions(c, result, true)
+	if !ok {
+		return
+	}
+	data, ok := h.readUpload(c, result)
+	if !ok {
+		return
+	}
+	if opts.CollectionID == "" {
+		result.FailWithMsg(response.InvalidParams, "collection_id is required")
+		return
+	}
+
+	har, err := importer.ParseHAR(data)
+	if err != nil {
+		h.Logger.Error("parse har file failed", zap.Error(err))
+		result.FailWithError(response.InvalidParams, err.Error())
+		return
+	}
+
+	filter := importer.HARFilter{
+		Domains:      formList(c, "domains"),
+		ContentTypes: formList(c, "content_types"),
+	}
+	for _, i := range formList(c, "entries") {
+		filter.Entries = append(filter.Entries, cast.ToInt(i))
+	}
+
+	folderName := c.PostForm("folder_name")
+	if folderName == "" {
+		folderName = "HAR Import " + time.Now().Format("2006-01-02 15:04:05")
+	}
+
+	col := har.Collection(filter, folderName)
+	if len(col.Folders[0].Requests) == 0 {
+		result.FailWithMsg(response.InvalidParams, "no entries matched the filter")
+		return
+	}
+
+	h.save(result, col, opts, nil)
+}
+
+// Insomnia 导入 Insomnia v4 导出文件，未指定工作区时按导出内容新建工作区
+func (h *ImportHandler) Insomnia(c *gin.Context) {
+	h.importWorkspace(c, importer.ParseInsomnia)
+}
+
+// Bruno 导入 Bruno 集合目录的 zip 压缩包，未指定工作区时按集合名称新建工作区
+func (h *ImportHandler) Bruno(c *gin.Context) {
+	h.importWorkspace(c, importer.ParseBruno)
+}
+
+// Native 导入原生导出文件，保留原有标识、顺序和示例
+func (h *ImportHandler) Native(c *gin.Context) {
+	result := response.NewResult(c)
+
+	opts, ok := h.importOptions(c, result, true)
+	if !ok {
+		return
+	}
+	data, ok := h.readUpload(c, result)
+	if !ok {
+		return
+	}
+
+	col, err := importer.ParseNative(data)
+	if err != nil {
+		h.Logger.Error("parse native export failed", zap.Error(err))
+		result.FailWithError(response.InvalidParams, err.Error())
+		return
+	}
+
+	h.save(result, col, opts, nil)
+}
+
+// importWorkspace 解析包含多个集合的导出文件并写入工作区
+func (h *ImportHandler) importWorkspace(c *gin.Context, parse func([]byte) (*importer.Workspace, []string, error)) {
+	result := response.NewResult(c)
+
+	opts, ok := h.importOptions(c, result, false)
+	if !ok {
+		return
+	}
+	data, ok := h.readUpload(c, result)
+	if !ok {
+		return
+	}
+
+	ws, warnings, err := parse(data)
+	if err != nil {
+		h.Logger.Error("parse import file failed", zap.Error(err))
+		result.FailWithError(response.InvalidParams, err.Error())
+		return
+	}
+	if opts.CollectionID != "" && (opts.WorkspaceID == 0 || len(ws.Collections) != 1) {
+		result.FailWithMsg(response.InvalidParams, "collection_id requires workspace_id and a single collection in the file")
+		return
+	}
+
+	report, err := importer.SaveWorkspace(h.DB, ws, opts)
+	if err != nil {
+		if errors.Is(err, importer.ErrCollectionNotFound) {
+			result.FailWithMsg(response.NotFound, "collection not found")
+			return
+		}
+		h.Logger.Error("save import failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "import failed")
+		return
+	}
+
+	report.Warnings = append(report.Warnings, warnings...)
+	result.Success(report)
+}
+
+// formList 读取表单中的列表参数，兼容重复字段和逗号分隔两种写法
+func formList(c *gin.Context, key string) []string {
+	var list []string
+	for _, v := range c.PostFormArray(key) {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
+
+// importOptions 读取公共导入参数，requireWorkspace 为 true 时必须指定工作区，指定的工作区必须存在且不在回收站中
+func (h *ImportHandler) importOptions(c *gin.Context, result *response.Result, requireWorkspace bool) (importer.Options, bool) {
+	userID, _ := c.Get("user_id")
+	opts := importer.Options{
+		WorkspaceID:  cast.ToUint64(c.PostForm("workspace_id")),
+		OwnerID:      cast.ToUint64(userID),
+		CollectionID: c.PostForm("collection_id"),
+		Conflict:     importer.ParseConflictStrategy(c.PostForm("conflict")),
+	}
+	if requireWorkspace && opts.WorkspaceID == 0 {
+		result.FailWithMsg(response.InvalidParams, "workspace_id is required")
+		return opts, false
+	}
+	if opts.WorkspaceID != 0 {
+		if err := h.DB.Where("id = ?", opts.WorkspaceID).First(&model.Workspace{}).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				result.FailWithMsg(response.NotFound, "workspace not found")
+				return opts, false
+			}
+			h.Logger.Error("query workspace failed due to database error", zap.Error(err))
+			result.FailWithMsg(response.ServerError, "import failed")
+			return opts, false
+		}
+	}
+	return opts, true
+}
+
+// readUpload 读取上传的导入文件
+func (h *ImportHandler) readUpload(c *gin.Context, result *response.Result) ([]byte, bool) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		h.Logger.Error("import failed due to missing file", zap.Error(err))
+		result.FailWithMsg(response.InvalidParams, "file is required")
+		return nil, false
+	}
+	if fileHeader.Size > maxImportFileSize {
+		result.FailWithMsg(response.InvalidParams, "file is too large")
+		return nil, false
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		h.Logger.Error("open import file failed", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "open file failed")
+		return nil, false
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		h.Logger.Error("read import file failed", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "read file failed")
+		return nil, false
+	}
+	return data, true
+}
+
+// save 写入导入结果并返回报告
+func (h *ImportHandler) save(result *response.Result, col *importer.Collection, opts importer.Options, warnings []string) {
+	report, err := importer.Save(h.DB, col, opts)
+	if err != nil {
+		if errors.Is(err, importer.ErrCollectionNotFound) {
+			result.FailWithMsg(response.NotFound, "collection not found")
+			return
+		}
+		h.Logger.Error("save import failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "import failed")
+		return
+	}
+
+	report.Warnings = append(report.Warnings, warnings...)
+	result.Success(report)
+}
@@ -21,4 +21,44 @@ func RegisterFrontendRoutes(routerRegistry *router.RouteRegistry) {
 	// request 请求
 	requestHandler := NewRequestHandler()
 	requestHandler.RegisterRoutes(routerRegistry)
+
+	// import 导入
+	importHandler := NewImportHandler()
+	importHandler.RegisterRoutes(routerRegistry)
+
+	// export 导出
+	exportHandler := NewExportHandler()
+	exportHandler.RegisterRoutes(routerRegistry)
+
+	// proto 描述与 gRPC 请求生成
+	protoHandler := NewProtoHandler()
+	protoHandler.RegisterRoutes(routerRegistry)
+
+	// trash 回收站
+	trashHandler := NewTrashHandler()
+	trashHandler.RegisterRoutes(routerRegistry)
+
+	// revision 请求修订历史
+	revisionHandler := NewRevisionHandler()
+	revisionHandler.RegisterRoutes(routerRegistry)
+
+	// search 全文搜索
+	searchHandler := NewSearchHandler()
+	searchHandler.RegisterRoutes(routerRegistry)
+
+	// tag 标签
+	tagHandler := NewTagHandler()
+	tagHandler.RegisterRoutes(routerRegistry)
+
+	// filter 保存的筛选条件
+	filterHandler := NewFilterHandler()
+	filterHandler.RegisterRoutes(routerRegistry)
+
+	// favorite 置顶与最近使用
+	favoriteHandler := NewFavoriteHandler()
+	favoriteHandler.RegisterRoutes(routerRegistry)
+
+	// fork 集合分支与合并请求
+	forkHandler := NewForkHandler()
+	forkHandler.RegisterRoutes(routerRegistry)
 }
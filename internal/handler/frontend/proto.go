@@ -0,0 +1,458 @@
+package frontend
+
+import (
+	"FastGo/internal/handler"
+	"FastGo/internal/model"
+	"FastGo/internal/router"
+	"FastGo/internal/service/importer"
+	"FastGo/internal/service/invoke"
+	"FastGo/internal/service/protoschema"
+	"FastGo/pkg/response"
+	"FastGo/pkg/uid"
+	"FastGo/pkg/validator"
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"gorm.io/gorm"
+)
+
+// 服务反射超时时间
+const protoReflectTimeout = 15 * time.Second
+
+// 同时探测的 gRPC 地址数量
+const targetProbeConcurrency = 8
+
+type ProtoHandler struct {
+	*handler.CommonHandler
+	inventory *protoschema.InventoryCache
+}
+
+func NewProtoHandler() *ProtoHandler {
+	common := handler.NewCommonHandler()
+	return &ProtoHandler{
+		CommonHandler: common,
+		inventory:     protoschema.NewInventoryCache(common.Redis),
+	}
+}
+
+func (h *ProtoHandler) RegisterRoutes(routerRegistry *router.RouteRegistry) {
+	routerRegistry.Register("POST", "proto", "/upload", h.Upload, 2, "上传 proto 文件")
+	routerRegistry.Register("POST", "proto", "/reflect", h.Reflect, 2, "通过服务反射获取 proto 描述")
+	routerRegistry.Register("GET", "proto", "/list", h.List, 2, "获取集合的 proto 描述列表")
+	routerRegistry.Register("GET", "proto", "/services", h.Services, 2, "获取 proto 描述中的服务")
+	routerRegistry.Register("POST", "proto", "/generate", h.Generate, 2, "根据 proto 服务生成请求")
+	routerRegistry.Register("GET", "proto", "/targets", h.Targets, 2, "获取工作区 gRPC 地址的服务清单")
+	routerRegistry.Register("POST", "proto", "/health", h.Health, 2, "对 gRPC 地址执行健康检查")
+}
+
+// Upload 上传 proto 源文件（可多个或 zip 压缩包）或 protoc 生成的描述文件（.protoset/.pb）
+func (h *ProtoHandler) Upload(c *gin.Context) {
+	result := response.NewResult(c)
+
+	collection, ok := h.collection(result, c.PostForm("collection_id"))
+	if !ok {
+		return
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil || len(form.File["files"]) == 0 {
+		result.FailWithMsg(response.InvalidParams, "files is required")
+		return
+	}
+
+	sources := map[string]string{}
+	var set *descriptorpb.FileDescriptorSet
+	for _, fileHeader := range form.File["files"] {
+		if fileHeader.Size > maxImportFileSize {
+			result.FailWithMsg(response.InvalidParams, "file is too large")
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			h.Logger.Error("open proto file failed", zap.Error(err))
+			result.FailWithMsg(response.ServerError, "open file failed")
+			return
+		}
+		data, err := io.ReadAll(file)
+		file.Close()
+		if err != nil {
+			h.Logger.Error("read proto file failed", zap.Error(err))
+			result.FailWithMsg(response.ServerError, "read file failed")
+			return
+		}
+
+		switch strings.ToLower(path.Ext(fileHeader.Filename)) {
+		case ".proto":
+			sources[fileHeader.Filename] = string(data)
+		case ".zip":
+			if err := unzipProtos(data, sources); err != nil {
+				result.FailWithError(response.InvalidParams, err.Error())
+				return
+			}
+		default:
+			if set, err = protoschema.ParseDescriptorSet(data); err != nil {
+				result.FailWithError(response.InvalidParams, err.Error())
+				return
+			}
+		}
+	}
+
+	if set == nil {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), protoReflectTimeout)
+		defer cancel()
+		if set, err = protoschema.Compile(ctx, sources); err != nil {
+			result.FailWithError(response.InvalidParams, err.Error())
+			return
+		}
+	}
+
+	name := c.PostForm("name")
+	if name == "" {
+		name = form.File["files"][0].Filename
+	}
+	h.saveSchema(result, model.ProtoSchema{
+		CollectionID: collection.CollectionID,
+		Name:         name,
+		Source:       model.ProtoSourceUpload,
+	}, set)
+}
+
+// Reflect 通过服务反射获取 proto 描述并保存
+func (h *ProtoHandler) Reflect(c *gin.Context) {
+	var req struct {
+		CollectionID string            `json:"collection_id" binding:"required"`
+		Target       string            `json:"target" binding:"required"`
+		TLS          bool              `json:"tls"`
+		Metadata     map[string]string `json:"metadata"`
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("reflect proto failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	collection, ok := h.collection(result, req.CollectionID)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), protoReflectTimeout)
+	defer cancel()
+	set, err := protoschema.Reflect(ctx, protoschema.ReflectOptions{Target: req.Target, TLS: req.TLS, Metadata: req.Metadata})
+	if err != nil {
+		h.Logger.Error("grpc reflection failed", zap.String("target", req.Target), zap.Error(err))
+		result.FailWithError(response.InvalidParams, err.Error())
+		return
+	}
+
+	h.saveSchema(result, model.ProtoSchema{
+		CollectionID: collection.CollectionID,
+		Name:         req.Target,
+		Source:       model.ProtoSourceReflection,
+		Target:       req.Target,
+	}, set)
+}
+
+// List 获取集合下保存的 proto 描述
+func (h *ProtoHandler) List(c *gin.Context) {
+	result := response.NewResult(c)
+
+	var schemas []model.ProtoSchema
+	err := h.DB.Select("id, schema_id, collection_id, name, source, target, created_at, updated_at").
+		Where("collection_id = ?", c.Query("collection_id")).Order("id").Find(&schemas).Error
+	if err != nil {
+		h.Logger.Error("get proto list failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "get proto list failed")
+		return
+	}
+
+	result.Success(map[string]interface{}{
+		"list": schemas,
+	})
+}
+
+// Services 获取 proto 描述中的服务和方法
+func (h *ProtoHandler) Services(c *gin.Context) {
+	result := response.NewResult(c)
+
+	schema, ok := h.schema(result, c.Query("schema_id"))
+	if !ok {
+		return
+	}
+	files, err := protoschema.Load(schema.Descriptor)
+	if err != nil {
+		h.Logger.Error("load proto schema failed", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "load proto schema failed")
+		return
+	}
+
+	result.Success(map[string]interface{}{
+		"services": protoschema.Services(files),
+	})
+}
+
+// Generate 为选中的服务生成文件夹和请求骨架，重复生成时按方法匹配已有请求
+func (h *ProtoHandler) Generate(c *gin.Context) {
+	var req struct {
+		SchemaID string   `json:"schema_id" binding:"required"`
+		Services []string `json:"services"`
+		Target   string   `json:"target"`
+		Conflict string   `json:"conflict"`
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("generate grpc requests failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	schema, ok := h.schema(result, req.SchemaID)
+	if !ok {
+		return
+	}
+	collection, ok := h.collection(result, schema.CollectionID)
+	if !ok {
+		return
+	}
+	files, err := protoschema.Load(schema.Descriptor)
+	if err != nil {
+		h.Logger.Error("load proto schema failed", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "load proto schema failed")
+		return
+	}
+
+	target := req.Target
+	if target == "" {
+		target = schema.Target
+	}
+	col, err := importer.FromProto(files, target, req.Services)
+	if err != nil {
+		result.FailWithError(response.InvalidParams, err.Error())
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	report, err := importer.Save(h.DB, col, importer.Options{
+		WorkspaceID:  collection.WorkspaceID,
+		OwnerID:      cast.ToUint64(userID),
+		CollectionID: collection.CollectionID,
+		Conflict:     importer.ParseConflictStrategy(req.Conflict),
+	})
+	if err != nil {
+		h.Logger.Error("save generated grpc requests failed", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "generate requests failed")
+		return
+	}
+
+	result.Success(report)
+}
+
+// Targets 列出工作区内 gRPC 请求和反射来源中出现的地址，并返回各地址缓存的服务清单
+// 指定 environment_id 时先替换地址中的变量，refresh 为 true 时忽略缓存重新反射
+func (h *ProtoHandler) Targets(c *gin.Context) {
+	result := response.NewResult(c)
+
+	workspaceID := cast.ToUint64(c.Query("workspace_id"))
+	if workspaceID == 0 {
+		result.FailWithMsg(response.InvalidParams, "workspace_id is required")
+		return
+	}
+
+	vars, ok := loadVariables(h.CommonHandler, result, c.Query("environment_id"))
+	if !ok {
+		return
+	}
+
+	targets, err := h.workspaceTargets(workspaceID, vars)
+	if err != nil {
+		h.Logger.Error("query grpc targets failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "query grpc targets failed")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), protoReflectTimeout)
+	defer cancel()
+	refresh := cast.ToBool(c.Query("refresh"))
+	list := make([]*protoschema.Inventory, len(targets))
+	sem := make(chan struct{}, targetProbeConcurrency)
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, target invoke.GRPCTarget) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			list[i] = h.inventory.Get(ctx, protoschema.ReflectOptions{Target: target.Base, TLS: target.TLS}, refresh)
+		}(i, target)
+	}
+	wg.Wait()
+
+	result.Success(map[string]interface{}{
+		"list": list,
+	})
+}
+
+// workspaceTargets 汇总工作区内原生 gRPC 请求和反射来源的地址，含未替换变量的地址跳过
+func (h *ProtoHandler) workspaceTargets(workspaceID uint64, vars map[string]string) ([]invoke.GRPCTarget, error) {
+	var requests []model.Request
+	err := h.DB.Model(&model.Request{}).Select("requests.path, requests.transport").
+		Joins("JOIN collections ON collections.collection_id = requests.collection_id").
+		Where("collections.workspace_id = ? AND requests.type = ?", workspaceID, model.GRPC1).
+		Where("requests.transport IN ?", []string{"", invoke.TransportGRPC}).
+		Find(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+	var schemas []model.ProtoSchema
+	err = h.DB.Model(&model.ProtoSchema{}).Select("proto_schemas.target").
+		Joins("JOIN collections ON collections.collection_id = proto_schemas.collection_id").
+		Where("collections.workspace_id = ? AND proto_schemas.target <> ''", workspaceID).
+		Find(&schemas).Error
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var targets []invoke.GRPCTarget
+	add := func(target invoke.GRPCTarget) {
+		key := target.Base + "|" + cast.ToString(target.TLS)
+		if strings.Contains(target.Base, "{{") || seen[key] {
+			return
+		}
+		seen[key] = true
+		targets = append(targets, target)
+	}
+	for _, r := range requests {
+		if target, err := invoke.ParseGRPCTarget(invoke.ExpandString(r.Path, vars), invoke.TransportGRPC); err == nil {
+			add(*target)
+		}
+	}
+	for _, s := range schemas {
+		add(invoke.GRPCTarget{Base: invoke.ExpandString(s.Target, vars)})
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Base < targets[j].Base
+	})
+	return targets, nil
+}
+
+// Health 对 gRPC 地址执行 grpc.health.v1 健康检查，service 为空时检查整个服务端
+func (h *ProtoHandler) Health(c *gin.Context) {
+	var req struct {
+		Target   string            `json:"target" binding:"required"`
+		TLS      bool              `json:"tls"`
+		Service  string            `json:"service"`
+		Metadata map[string]string `json:"metadata"`
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("grpc health check failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), protoReflectTimeout)
+	defer cancel()
+	result.Success(protoschema.Health(ctx, protoschema.ReflectOptions{Target: req.Target, TLS: req.TLS, Metadata: req.Metadata}, req.Service))
+}
+
+func (h *ProtoHandler) saveSchema(result *response.Result, schema model.ProtoSchema, set *descriptorpb.FileDescriptorSet) {
+	data, err := protoschema.Marshal(set)
+	if err != nil {
+		h.Logger.Error("encode proto schema failed", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "save proto schema failed")
+		return
+	}
+	files, err := protoschema.Load(data)
+	if err != nil {
+		result.FailWithError(response.InvalidParams, err.Error())
+		return
+	}
+	services := protoschema.Services(files)
+	if len(services) == 0 {
+		result.FailWithError(response.InvalidParams, protoschema.ErrNoServices.Error())
+		return
+	}
+
+	schema.SchemaID = uid.NewUUID()
+	schema.Descriptor = data
+	if err := h.DB.Create(&schema).Error; err != nil {
+		h.Logger.Error("save proto schema failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "save proto schema failed")
+		return
+	}
+
+	result.Success(map[string]interface{}{
+		"schema":   schema,
+		"services": services,
+	})
+}
+
+func (h *ProtoHandler) collection(result *response.Result, collectionID string) (*model.Collections, bool) {
+	if collectionID == "" {
+		result.FailWithMsg(response.InvalidParams, "collection_id is required")
+		return nil, false
+	}
+	var collection model.Collections
+	if err := h.DB.Where("collection_id = ?", collectionID).First(&collection).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			result.FailWithMsg(response.NotFound, "collection not found")
+			return nil, false
+		}
+		h.Logger.Error("query collection failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "query collection failed")
+		return nil, false
+	}
+	return &collection, true
+}
+
+func (h *ProtoHandler) schema(result *response.Result, schemaID string) (*model.ProtoSchema, bool) {
+	var schema model.ProtoSchema
+	if err := h.DB.Where("schema_id = ?", schemaID).First(&schema).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			result.FailWithMsg(response.NotFound, "proto schema not found")
+			return nil, false
+		}
+		h.Logger.Error("query proto schema failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "query proto schema failed")
+		return nil, false
+	}
+	return &schema, true
+}
+
+// unzipProtos 读取压缩包中的 .proto 文件，保留相对路径以便解析 import
+func unzipProtos(data []byte, sources map[string]string) error {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return err
+	}
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() || !strings.HasSuffix(f.Name, ".proto") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		content, err := io.ReadAll(io.LimitReader(rc, maxImportFileSize))
+		rc.Close()
+		if err != nil {
+			return err
+		}
+		sources[path.Clean(f.Name)] = string(content)
+	}
+	return nil
+}
@@ -1,38 +1,82 @@
 package frontend
 
 import (
+	"FastGo/config"
+	"FastGo/internal/global"
 	"FastGo/internal/handler"
+	"FastGo/internal/middleware/auth"
 	"FastGo/internal/model"
 	"FastGo/internal/router"
+	"FastGo/internal/service"
+	"FastGo/internal/service/invoke"
 	"FastGo/pkg/response"
 	"FastGo/pkg/uid"
 	"FastGo/pkg/validator"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/spf13/cast"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
+// 单次流式执行记录的消息上限，超出后不再记录但继续推送
+const maxTranscriptMessages = 10000
+
 type RequestHandler struct {
 	*handler.CommonHandler
+	invoker *invoke.Registry
+	sse     *invoke.SSEStreamer
+	recents *service.RecentStore
+	ws      config.WebSocketOptions
+	upgrade websocket.Upgrader
 }
 
 func NewRequestHandler() *RequestHandler {
+	common := handler.NewCommonHandler()
+	ws := global.Config.WebSocket
 	return &RequestHandler{
-		CommonHandler: handler.NewCommonHandler(),
+		CommonHandler: common,
+		invoker:       invoke.NewRegistry(common.DB, common.Redis),
+		sse:           &invoke.SSEStreamer{},
+		recents:       service.NewRecentStore(common.Redis, global.Config.Recent),
+		ws:            ws,
+		upgrade: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool {
+				return ws.OriginAllowed(r.Header.Get("Origin"), r.Host)
+			},
+		},
 	}
 }
 
 func (h *RequestHandler) RegisterRoutes(routerRegistry *router.RouteRegistry) {
 	routerRegistry.Register("POST", "request", "/create", h.Create, 2, "创建请求")
+	routerRegistry.Register("GET", "request", "/get", h.Get, 2, "获取请求详情")
+	routerRegistry.Register("GET", "request", "/resolved", h.Resolved, 2, "获取合并默认值后的请求及各项来源")
+	routerRegistry.Register("POST", "request", "/update", h.Update, 2, "更新请求")
+	routerRegistry.Register("POST", "request", "/delete", h.Delete, 2, "删除请求")
+	routerRegistry.Register("POST", "request", "/move", h.Move, 2, "移动请求到其他文件夹或集合")
+	routerRegistry.Register("POST", "request", "/send", h.Send, 2, "发送请求")
+	routerRegistry.Register("POST", "request", "/duplicate", h.Duplicate, 2, "复制请求")
+	routerRegistry.Register("POST", "request", "/stream/ticket", h.StreamTicket, 2, "获取建立 WebSocket 连接用的一次性票据")
+	routerRegistry.Register("GET", "request", "/stream", h.Stream, 2, "建立 SSE 流并通过 WebSocket 推送事件")
+	routerRegistry.Register("POST", "request", "/graphql/schema", h.GraphQLSchema, 2, "获取 GraphQL 内省结果")
 }
 
+// Create 创建请求，除集合、文件夹和类型外的字段与 Update 相同
 func (h *RequestHandler) Create(c *gin.Context) {
 	var req struct {
-		Name         string `json:"name"`
 		CollectionID string `json:"collection_id" binding:"required,uuid"`
 		FolderID     string `json:"folder_id"`
-		Type         string `json:"type" binding:"required,oneof=HTTP WebSocket GRPC"`
-		Method       string `json:"method" binding:"required,oneof=GET POST PUT DELETE"`
+		Type         string `json:"type" binding:"required,oneof=HTTP WebSocket gRPC GraphQL SSE JSON-RPC"`
+		Method       string `json:"method" binding:"required,oneof=GET POST PUT DELETE PATCH HEAD OPTIONS"`
+		requestFields
 	}
 	result := response.NewResult(c)
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -41,24 +85,679 @@ func (h *RequestHandler) Create(c *gin.Context) {
 		return
 	}
 
-	if req.Name == "" {
-		req.Name = "New Request"
+	_, err := service.ValidateParent(h.DB, req.FolderID, req.CollectionID)
+	switch {
+	case errors.Is(err, service.ErrFolderNotFound), errors.Is(err, service.ErrCollectionNotFound):
+		result.FailWithMsg(response.NotFound, err.Error())
+		return
+	case errors.Is(err, service.ErrParentInCollection):
+		result.FailWithMsg(response.InvalidParams, err.Error())
+		return
+	case err != nil:
+		h.Logger.Error("query request parent failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "创建请求失败")
+		return
+	}
+
+	order, err := service.NextSortOrder(h.DB, &model.Request{}, req.CollectionID, req.FolderID)
+	if err != nil {
+		h.Logger.Error("get request sort order failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "创建请求失败")
+		return
 	}
 
 	request := model.Request{
-		Name:         req.Name,
+		Name:         "New Request",
+		SortOrder:    order,
 		CollectionID: req.CollectionID,
 		FolderID:     req.FolderID,
 		RequestID:    uid.NewUUID(),
 		Type:         model.RequestType(req.Type),
 		Method:       model.RequestMethod(req.Method),
 	}
+	req.apply(&request)
+	if request.Name == "" {
+		request.Name = "New Request"
+	}
+	if err := validateTags(req.Tags); err != nil {
+		result.FailWithMsg(response.InvalidParams, err.Error())
+		return
+	}
+	if err := invoke.Validate(&request); err != nil {
+		result.FailWithMsg(response.InvalidParams, err.Error())
+		return
+	}
 
-	if err := h.DB.Model(&model.Request{}).Create(&request).Error; err != nil {
+	userID, _ := c.Get("user_id")
+	err = h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Request{}).Create(&request).Error; err != nil {
+			return err
+		}
+		_, err := service.RecordRevision(tx, &request, cast.ToUint64(userID), model.RevisionCreate)
+		return err
+	})
+	if err != nil {
 		h.Logger.Error("创建请求失败", zap.Error(err))
-		result.FailWithError(response.Success, "创建请求失败")
+		result.FailWithMsg(response.ServerError, "创建请求失败")
+		return
+	}
+
+	result.Success(newRequestDetail(&request))
+}
+
+// Send 替换环境变量后发送已保存的请求，并保存发送记录
+// 发送失败（如网络错误、GraphQL 查询校验未通过）同样返回成功响应，失败原因在记录中
+func (h *RequestHandler) Send(c *gin.Context) {
+	var req struct {
+		RequestID     string `json:"request_id" binding:"required"`
+		EnvironmentID string `json:"environment_id"`
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("send request failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	call, ok := h.loadCall(result, req.RequestID, req.EnvironmentID)
+	if !ok {
+		return
+	}
+
+	if call.Request.Type == model.SSE {
+		result.FailWithMsg(response.InvalidParams, "SSE requests must be opened via /api/request/stream")
+		return
+	}
+
+	sent, res, err := h.invoker.Invoke(c.Request.Context(), call)
+	if errors.Is(err, invoke.ErrUnsupportedType) {
+		result.FailWithMsg(response.InvalidParams, "sending "+string(call.Request.Type)+" requests is not supported")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	h.recents.Touch(c.Request.Context(), cast.ToUint64(userID), service.KindRequest, req.RequestID)
+	execution := invoke.NewExecution(sent, cast.ToUint64(userID), res, err, nil)
+	if err := h.DB.Create(execution).Error; err != nil {
+		h.Logger.Error("save execution failed due to database error", zap.Error(err))
+	}
+
+	result.Success(map[string]interface{}{
+		"execution_id": execution.ExecutionID,
+		"success":      execution.Success,
+		"error":        execution.Error,
+		"result":       res,
+	})
+}
+
+// StreamTicket 签发一次性票据，浏览器建立 Stream 连接时放在 ticket 参数中代替令牌
+func (h *RequestHandler) StreamTicket(c *gin.Context) {
+	result := response.NewResult(c)
+	userID, _ := c.Get("user_id")
+	username, _ := c.Get("username")
+	role, _ := c.Get("role")
+
+	ttl := h.ws.TicketExpiry()
+	ticket, err := auth.IssueTicket(c.Request.Context(), h.Redis, auth.Ticket{
+		UserID:   cast.ToUint(userID),
+		Username: cast.ToString(username),
+		Role:     cast.ToUint8(role),
+	}, ttl)
+	if err != nil {
+		h.Logger.Error("issue websocket ticket failed", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "issue ticket failed")
+		return
+	}
+
+	result.Success(map[string]interface{}{
+		"ticket":     ticket,
+		"expires_in": int(ttl.Seconds()),
+	})
+}
+
+// Stream 连接 SSE 请求地址，将解析后的事件通过 WebSocket 实时推送给客户端
+// 客户端关闭连接或发送 {"type":"close"} 时结束，事件记录随执行记录保存
+func (h *RequestHandler) Stream(c *gin.Context) {
+	result := response.NewResult(c)
+
+	call, ok := h.loadCall(result, c.Query("request_id"), c.Query("environment_id"))
+	if !ok {
+		return
+	}
+	if call.Request.Type != model.SSE {
+		result.FailWithMsg(response.InvalidParams, "request is not an SSE request")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	h.recents.Touch(c.Request.Context(), cast.ToUint64(userID), service.KindRequest, call.Request.RequestID)
+
+	conn, err := h.upgrade.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		h.Logger.Error("upgrade stream connection failed", zap.Error(err))
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	go func() {
+		defer cancel()
+		for {
+			var msg struct {
+				Type string `json:"type"`
+			}
+			if err := conn.ReadJSON(&msg); err != nil || msg.Type == invoke.SSEClose {
+				return
+			}
+		}
+	}()
+
+	req := invoke.Expand(call.Request, call.Variables)
+	var transcript []invoke.SSEMessage
+	record := func(m invoke.SSEMessage) {
+		if len(transcript) < maxTranscriptMessages {
+			transcript = append(transcript, m)
+		}
+		if err := conn.WriteJSON(m); err != nil {
+			cancel()
+		}
+	}
+	record(invoke.SSEMessage{Type: invoke.SSEConnecting, Time: time.Now()})
+
+	err = h.sse.Stream(ctx, &req, record)
+	closing := invoke.SSEMessage{Type: invoke.SSEClose, Time: time.Now()}
+	if err != nil {
+		closing.Error = err.Error()
+	}
+	record(closing)
+
+	execution := invoke.NewExecution(&req, cast.ToUint64(userID), invoke.SSEResult(transcript, err), err, transcript)
+	if err := h.DB.Create(execution).Error; err != nil {
+		h.Logger.Error("save execution failed due to database error", zap.Error(err))
+	}
+	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, execution.ExecutionID))
+}
+
+// GraphQLSchema 获取 GraphQL 请求地址的内省结果，refresh 为 true 时忽略缓存重新内省
+func (h *RequestHandler) GraphQLSchema(c *gin.Context) {
+	var req struct {
+		RequestID     string `json:"request_id" binding:"required"`
+		EnvironmentID string `json:"environment_id"`
+		Refresh       bool   `json:"refresh"`
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("get graphql schema failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	call, ok := h.loadCall(result, req.RequestID, req.EnvironmentID)
+	if !ok {
+		return
+	}
+	invoker, _ := h.invoker.Invoker(model.GraphQL)
+	gql, ok := invoker.(*invoke.GraphQLInvoker)
+	if !ok || call.Request.Type != model.GraphQL {
+		result.FailWithMsg(response.InvalidParams, "request is not a GraphQL request")
+		return
+	}
+
+	endpoint, headers := invoke.GraphQLEndpoint(call.Request, call.Variables)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+	schema, err := gql.Schema(ctx, endpoint, headers, req.Refresh)
+	if err != nil {
+		h.Logger.Error("graphql introspection failed", zap.String("endpoint", endpoint), zap.Error(err))
+		result.FailWithError(response.InvalidParams, err.Error())
+		return
+	}
+
+	result.Success(map[string]interface{}{
+		"endpoint": endpoint,
+		"schema":   schema,
+	})
+}
+
+// loadCall 加载请求及其环境变量
+func (h *RequestHandler) loadCall(result *response.Result, requestID, environmentID string) (*invoke.Call, bool) {
+	call := &invoke.Call{}
+	if err := h.DB.Where("request_id = ?", requestID).First(&call.Request).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			result.FailWithMsg(response.NotFound, "request not found")
+			return nil, false
+		}
+		h.Logger.Error("query request failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "query request failed")
+		return nil, false
+	}
+
+	resolved, err := service.ResolveRequest(h.DB, call.Request)
+	if err != nil {
+		h.Logger.Error("resolve request defaults failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "query request failed")
+		return nil, false
+	}
+	call.Request = resolved.Request
+
+	vars, ok := loadVariables(h.CommonHandler, result, environmentID)
+	if !ok {
+		return nil, false
+	}
+	call.Variables = vars
+	return call, true
+}
+
+// loadVariables 读取环境中启用的变量，environmentID 为空时返回空变量表
+func loadVariables(h *handler.CommonHandler, result *response.Result, environmentID string) (map[string]string, bool) {
+	if environmentID == "" {
+		return nil, true
+	}
+	var environment model.Environment
+	if err := h.DB.Where("environment_id = ?", environmentID).First(&environment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			result.FailWithMsg(response.NotFound, "environment not found")
+			return nil, false
+		}
+		h.Logger.Error("query environment failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "query environment failed")
+		return nil, false
+	}
+	return invoke.Variables(model.DecodeKeyValues(environment.Variables)), true
+}
+
+// Duplicate 在原位置复制请求及其响应示例
+func (h *RequestHandler) Duplicate(c *gin.Context) {
+	var req struct {
+		RequestID string `json:"request_id" binding:"required"`
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("duplicate request failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	var copied *model.Request
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		copied, err = service.DuplicateRequest(tx, req.RequestID, cast.ToUint64(userID))
+		return err
+	})
+	if errors.Is(err, service.ErrRequestNotFound) {
+		result.FailWithMsg(response.NotFound, err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("duplicate request failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "duplicate request failed")
+		return
+	}
+
+	result.Success(map[string]interface{}{
+		"request_id":    copied.RequestID,
+		"name":          copied.Name,
+		"collection_id": copied.CollectionID,
+		"folder_id":     copied.FolderID,
+	})
+}
+
+// requestBody 请求体及其内容类型，内容类型保存为启用的 Content-Type 请求头
+type requestBody struct {
+	ContentType string `json:"content_type"`
+	Content     string `json:"content"`
+}
+
+// requestFields 创建和更新请求时可编辑的字段，未传的字段保持不变
+type requestFields struct {
+	Name        *string             `json:"name" binding:"omitempty,max=128"`
+	Path        *string             `json:"path" binding:"omitempty,max=128"`
+	Transport   *string             `json:"transport" binding:"omitempty,oneof=grpc grpc-web grpc-web-text connect-json connect-proto"`
+	Headers     *[]model.KeyValue   `json:"headers"`
+	QueryParams *[]model.KeyValue   `json:"query_params"`
+	Auth        *model.Auth         `json:"auth"`
+	Body        *requestBody        `json:"body"`
+	Query       *string             `json:"query"`
+	Variables   *string             `json:"variables"`
+	Assertions  *[]invoke.Assertion `json:"assertions"`
+	Timeout     *int                `json:"timeout" binding:"omitempty,min=0"`
+	RetryCount  *int                `json:"retry_count" binding:"omitempty,min=0,max=10"`
+	Priority    *int                `json:"priority"`
+	Description *string             `json:"description"`
+	Tags        *[]string           `json:"tags"`
+}
+
+// apply 将传入的字段写入请求
+func (f *requestFields) apply(r *model.Request) {
+	if f.Name != nil {
+		r.Name = *f.Name
+	}
+	if f.Path != nil {
+		r.Path = *f.Path
+	}
+	if f.Transport != nil {
+		r.Transport = *f.Transport
+	}
+	if f.Headers != nil {
+		r.Headers = model.EncodeKeyValues(*f.Headers)
+	}
+	if f.QueryParams != nil {
+		r.QueryParams = model.EncodeKeyValues(*f.QueryParams)
+	}
+	if f.Auth != nil {
+		r.Auth = model.EncodeAuth(f.Auth)
+	}
+	if f.Body != nil {
+		r.Body = f.Body.Content
+		if f.Body.ContentType != "" {
+			r.Headers = model.EncodeKeyValues(setContentType(model.DecodeKeyValues(r.Headers), f.Body.ContentType))
+		}
+	}
+	if f.Query != nil {
+		r.Query = *f.Query
+	}
+	if f.Variables != nil {
+		r.Variables = *f.Variables
+	}
+	if f.Assertions != nil {
+		r.Assertions = ""
+		if len(*f.Assertions) > 0 {
+			data, _ := json.Marshal(*f.Assertions)
+			r.Assertions = string(data)
+		}
+	}
+	if f.Timeout != nil {
+		r.Timeout = *f.Timeout
+	}
+	if f.RetryCount != nil {
+		r.RetryCount = *f.RetryCount
+	}
+	if f.Priority != nil {
+		r.Priority = *f.Priority
+	}
+	if f.Description != nil {
+		r.Description = *f.Description
+	}
+	if f.Tags != nil {
+		r.Tags = model.EncodeTags(*f.Tags)
+	}
+}
+
+// setContentType 用一个启用的 Content-Type 替换已有的同名请求头
+func setContentType(headers []model.KeyValue, contentType string) []model.KeyValue {
+	out := make([]model.KeyValue, 0, len(headers)+1)
+	for _, kv := range headers {
+		if !strings.EqualFold(kv.Key, "Content-Type") {
+			out = append(out, kv)
+		}
+	}
+	return append(out, model.KeyValue{Key: "Content-Type", Value: contentType, Enabled: true})
+}
+
+// requestDetail 请求详情，键值对和断言以结构化列表返回
+type requestDetail struct {
+	ID           string             `json:"id"`
+	RequestID    string             `json:"request_id"`
+	CollectionID string             `json:"collection_id"`
+	FolderID     string             `json:"folder_id"`
+	Name         string             `json:"name"`
+	Type         string             `json:"type"`
+	Method       string             `json:"method"`
+	Path         string             `json:"path"`
+	Transport    string             `json:"transport"`
+	SortOrder    int                `json:"sort_order"`
+	Headers      []model.KeyValue   `json:"headers"`
+	QueryParams  []model.KeyValue   `json:"query_params"`
+	Auth         model.Auth         `json:"auth"`
+	Body         requestBody        `json:"body"`
+	Query        string             `json:"query"`
+	Variables    string             `json:"variables"`
+	Assertions   []invoke.Assertion `json:"assertions"`
+	Timeout      int                `json:"timeout"`
+	RetryCount   int                `json:"retry_count"`
+	Priority     int                `json:"priority"`
+	Description  string             `json:"description"`
+	Tags         []string           `json:"tags"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+}
+
+func newRequestDetail(r *model.Request) *requestDetail {
+	headers := model.DecodeKeyValues(r.Headers)
+	body := requestBody{Content: r.Body}
+	for _, kv := range headers {
+		if kv.Enabled && strings.EqualFold(kv.Key, "Content-Type") {
+			body.ContentType = kv.Value
+		}
+	}
+	assertions, _ := invoke.ParseAssertions(r.Assertions)
+	if assertions == nil {
+		assertions = []invoke.Assertion{}
+	}
+	return &requestDetail{
+		ID:           cast.ToString(r.ID),
+		RequestID:    r.RequestID,
+		CollectionID: r.CollectionID,
+		FolderID:     r.FolderID,
+		Name:         r.Name,
+		Type:         string(r.Type),
+		Method:       string(r.Method),
+		Path:         r.Path,
+		Transport:    r.Transport,
+		SortOrder:    r.SortOrder,
+		Headers:      headers,
+		QueryParams:  model.DecodeKeyValues(r.QueryParams),
+		Auth:         model.DecodeAuth(r.Auth),
+		Body:         body,
+		Query:        r.Query,
+		Variables:    r.Variables,
+		Assertions:   assertions,
+		Timeout:      r.Timeout,
+		RetryCount:   r.RetryCount,
+		Priority:     r.Priority,
+		Description:  r.Description,
+		Tags:         model.DecodeTags(r.Tags),
+		CreatedAt:    r.CreatedAt,
+		UpdatedAt:    r.UpdatedAt,
+	}
+}
+
+// Get 获取请求详情
+func (h *RequestHandler) Get(c *gin.Context) {
+	result := response.NewResult(c)
+	requestID := c.Query("request_id")
+	if requestID == "" {
+		result.FailWithMsg(response.InvalidParams, "request_id is required")
+		return
+	}
+
+	var request model.Request
+	if err := h.DB.Where("request_id = ?", requestID).First(&request).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			result.FailWithMsg(response.NotFound, "request not found")
+			return
+		}
+		h.Logger.Error("query request failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "query request failed")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	h.recents.Touch(c.Request.Context(), cast.ToUint64(userID), service.KindRequest, request.RequestID)
+	result.Success(newRequestDetail(&request))
+}
+
+// Update 更新请求，只修改传入的字段，修改后按请求类型重新校验
+func (h *RequestHandler) Update(c *gin.Context) {
+	var req struct {
+		RequestID string  `json:"request_id" binding:"required"`
+		Type      *string `json:"type" binding:"omitempty,oneof=HTTP WebSocket gRPC GraphQL SSE JSON-RPC"`
+		Method    *string `json:"method" binding:"omitempty,oneof=GET POST PUT DELETE PATCH HEAD OPTIONS"`
+		requestFields
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("update request failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	var request model.Request
+	if err := h.DB.Where("request_id = ?", req.RequestID).First(&request).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			result.FailWithMsg(response.NotFound, "request not found")
+			return
+		}
+		h.Logger.Error("query request failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "update request failed")
+		return
+	}
+
+	if req.Type != nil {
+		request.Type = model.RequestType(*req.Type)
+	}
+	if req.Method != nil {
+		request.Method = model.RequestMethod(*req.Method)
+	}
+	req.apply(&request)
+	if request.Name == "" {
+		result.FailWithMsg(response.InvalidParams, "name is required")
+		return
+	}
+	if err := validateTags(req.Tags); err != nil {
+		result.FailWithMsg(response.InvalidParams, err.Error())
+		return
+	}
+	if err := invoke.Validate(&request); err != nil {
+		result.FailWithMsg(response.InvalidParams, err.Error())
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&request).Error; err != nil {
+			return err
+		}
+		_, err := service.RecordRevision(tx, &request, cast.ToUint64(userID), model.RevisionUpdate)
+		return err
+	})
+	if err != nil {
+		h.Logger.Error("update request failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "update request failed")
+		return
+	}
+
+	result.Success(newRequestDetail(&request))
+}
+
+// Delete 删除请求及其响应示例，发送记录作为历史保留
+func (h *RequestHandler) Delete(c *gin.Context) {
+	var req struct {
+		RequestID string `json:"request_id" binding:"required"`
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("delete request failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	var report *service.DeleteReport
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		report, err = service.DeleteRequest(tx, req.RequestID, cast.ToUint64(userID))
+		return err
+	})
+	if errors.Is(err, service.ErrRequestNotFound) {
+		result.FailWithMsg(response.NotFound, err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("delete request failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "delete request failed")
+		return
+	}
+
+	result.Success(report)
+}
+
+// Move 将请求移动到其他文件夹或集合，folder_id 为空表示集合根目录
+func (h *RequestHandler) Move(c *gin.Context) {
+	var req struct {
+		RequestID    string `json:"request_id" binding:"required"`
+		FolderID     string `json:"folder_id"`
+		CollectionID string `json:"collection_id"`
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("move request failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	var request *model.Request
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		request, err = service.MoveRequest(tx, req.RequestID, req.FolderID, req.CollectionID)
+		if err != nil {
+			return err
+		}
+		_, err = service.RecordRevision(tx, request, cast.ToUint64(userID), model.RevisionMove)
+		return err
+	})
+	switch {
+	case errors.Is(err, service.ErrRequestNotFound), errors.Is(err, service.ErrFolderNotFound),
+		errors.Is(err, service.ErrCollectionNotFound):
+		result.FailWithMsg(response.NotFound, err.Error())
+		return
+	case errors.Is(err, service.ErrParentInCollection):
+		result.FailWithMsg(response.InvalidParams, err.Error())
+		return
+	case err != nil:
+		h.Logger.Error("move request failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "move request failed")
+		return
+	}
+
+	result.Success(map[string]interface{}{
+		"request_id":    request.RequestID,
+		"collection_id": request.CollectionID,
+		"folder_id":     request.FolderID,
+	})
+}
+
+// Resolved 获取合并集合、文件夹默认值后的请求，并标明地址、请求头、认证、超时和重试的来源
+func (h *RequestHandler) Resolved(c *gin.Context) {
+	result := response.NewResult(c)
+	requestID := c.Query("request_id")
+	if requestID == "" {
+		result.FailWithMsg(response.InvalidParams, "request_id is required")
+		return
+	}
+
+	var request model.Request
+	if err := h.DB.Where("request_id = ?", requestID).First(&request).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			result.FailWithMsg(response.NotFound, "request not found")
+			return
+		}
+		h.Logger.Error("query request failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "query request failed")
+		return
+	}
+
+	resolved, err := service.ResolveRequest(h.DB, request)
+	if err != nil {
+		h.Logger.Error("resolve request defaults failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "resolve request failed")
 		return
 	}
 
-	result.Success(nil)
+	result.Success(map[string]interface{}{
+		"request": newRequestDetail(&resolved.Request),
+		"sources": resolved,
+	})
 }
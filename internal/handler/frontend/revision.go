@@ -0,0 +1,166 @@
+package frontend
+
+import (
+	"FastGo/internal/handler"
+	"FastGo/internal/model"
+	"FastGo/internal/router"
+	"FastGo/internal/service"
+	"FastGo/pkg/response"
+	"FastGo/pkg/validator"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 变更日志每页的默认和最大条数
+const (
+	defaultChangelogLimit = 50
+	maxChangelogLimit     = 200
+)
+
+type RevisionHandler struct {
+	*handler.CommonHandler
+}
+
+func NewRevisionHandler() *RevisionHandler {
+	return &RevisionHandler{
+		CommonHandler: handler.NewCommonHandler(),
+	}
+}
+
+func (h *RevisionHandler) RegisterRoutes(routerRegistry *router.RouteRegistry) {
+	routerRegistry.Register("GET", "request", "/revisions", h.List, 2, "获取请求修订历史")
+	routerRegistry.Register("GET", "request", "/revisions/diff", h.Diff, 2, "比较请求的两个修订")
+	routerRegistry.Register("POST", "request", "/revisions/restore", h.Restore, 2, "将请求恢复为历史修订")
+	routerRegistry.Register("GET", "collections", "/changelog", h.Changelog, 2, "获取集合变更日志")
+}
+
+// List 按版本倒序列出请求的修订，不包含快照内容
+func (h *RevisionHandler) List(c *gin.Context) {
+	result := response.NewResult(c)
+	requestID := c.Query("request_id")
+	if requestID == "" {
+		result.FailWithMsg(response.InvalidParams, "request_id is required")
+		return
+	}
+
+	var revisions []model.RequestRevision
+	if err := h.DB.Where("request_id = ?", requestID).Order("version DESC").Find(&revisions).Error; err != nil {
+		h.Logger.Error("list revisions failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "list revisions failed")
+		return
+	}
+
+	result.Success(map[string]interface{}{
+		"list": revisions,
+	})
+}
+
+// Diff 按字段比较请求的两个修订，from 为较早的修订
+func (h *RevisionHandler) Diff(c *gin.Context) {
+	result := response.NewResult(c)
+	requestID, from, to := c.Query("request_id"), c.Query("from"), c.Query("to")
+	if requestID == "" || from == "" || to == "" {
+		result.FailWithMsg(response.InvalidParams, "request_id, from and to are required")
+		return
+	}
+
+	snapshots := make([]service.RequestSnapshot, 0, 2)
+	for _, revisionID := range []string{from, to} {
+		rev, err := service.FindRevision(h.DB, requestID, revisionID)
+		if errors.Is(err, service.ErrRevisionNotFound) {
+			result.FailWithMsg(response.NotFound, err.Error())
+			return
+		}
+		if err != nil {
+			h.Logger.Error("query revision failed due to database error", zap.Error(err))
+			result.FailWithMsg(response.ServerError, "diff revisions failed")
+			return
+		}
+		snapshot, err := service.DecodeSnapshot(rev)
+		if err != nil {
+			h.Logger.Error("decode revision snapshot failed", zap.Error(err))
+			result.FailWithMsg(response.ServerError, "diff revisions failed")
+			return
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	result.Success(map[string]interface{}{
+		"from":    from,
+		"to":      to,
+		"changes": service.DiffSnapshots(snapshots[0], snapshots[1]),
+	})
+}
+
+// Restore 将请求内容恢复为历史修订，恢复本身记录为一条新修订，请求保持当前位置
+func (h *RevisionHandler) Restore(c *gin.Context) {
+	var req struct {
+		RequestID  string `json:"request_id" binding:"required"`
+		RevisionID string `json:"revision_id" binding:"required"`
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("restore revision failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	var request *model.Request
+	var revision *model.RequestRevision
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		request, revision, err = service.RestoreRevision(tx, req.RequestID, req.RevisionID, cast.ToUint64(userID))
+		return err
+	})
+	if errors.Is(err, service.ErrRequestNotFound) || errors.Is(err, service.ErrRevisionNotFound) {
+		result.FailWithMsg(response.NotFound, err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrInvalidRevision) {
+		result.FailWithMsg(response.InvalidParams, err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("restore revision failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "restore revision failed")
+		return
+	}
+
+	result.Success(map[string]interface{}{
+		"revision": revision,
+		"request":  newRequestDetail(request),
+	})
+}
+
+// Changelog 按时间倒序列出集合内请求的修订及变化的字段，before 为上一页最后一条修订的 id
+func (h *RevisionHandler) Changelog(c *gin.Context) {
+	result := response.NewResult(c)
+	collectionID := c.Query("collection_id")
+	if collectionID == "" {
+		result.FailWithMsg(response.InvalidParams, "collection_id is required")
+		return
+	}
+	limit := cast.ToInt(c.Query("limit"))
+	if limit <= 0 {
+		limit = defaultChangelogLimit
+	}
+	if limit > maxChangelogLimit {
+		limit = maxChangelogLimit
+	}
+
+	entries, err := service.CollectionChangelog(h.DB, collectionID, cast.ToUint64(c.Query("before")), limit)
+	if err != nil {
+		h.Logger.Error("query changelog failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "query changelog failed")
+		return
+	}
+
+	result.Success(map[string]interface{}{
+		"list": entries,
+	})
+}
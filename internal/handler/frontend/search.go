@@ -0,0 +1,98 @@
+package frontend
+
+import (
+	"FastGo/internal/handler"
+	"FastGo/internal/model"
+	"FastGo/internal/router"
+	"FastGo/internal/service"
+	"FastGo/pkg/response"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"go.uber.org/zap"
+)
+
+// 搜索结果的默认和最大条数
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+type SearchHandler struct {
+	*handler.CommonHandler
+}
+
+func NewSearchHandler() *SearchHandler {
+	return &SearchHandler{
+		CommonHandler: handler.NewCommonHandler(),
+	}
+}
+
+func (h *SearchHandler) RegisterRoutes(routerRegistry *router.RouteRegistry) {
+	routerRegistry.Register("GET", "search", "", h.Search, 2, "在工作区内全文搜索请求、文件夹和集合")
+}
+
+// Search 在当前用户的工作区内搜索，workspace_id 限定单个工作区，
+// type、method 以逗号分隔多个取值，指定后只返回请求；tag 为标签表达式
+func (h *SearchHandler) Search(c *gin.Context) {
+	result := response.NewResult(c)
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		result.FailWithMsg(response.InvalidParams, "q is required")
+		return
+	}
+	expr, err := parseTagExpr(c.Query("tag"))
+	if err != nil {
+		result.FailWithMsg(response.InvalidParams, err.Error())
+		return
+	}
+	limit := cast.ToInt(c.Query("limit"))
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	userID, _ := c.Get("user_id")
+	query := h.DB.Model(&model.Workspace{}).Where("owner_id = ?", cast.ToUint64(userID))
+	if workspaceID := c.Query("workspace_id"); workspaceID != "" {
+		query = query.Where("id = ?", cast.ToUint64(workspaceID))
+	}
+	var workspaceIDs []uint64
+	if err := query.Pluck("id", &workspaceIDs).Error; err != nil {
+		h.Logger.Error("query workspaces failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "search failed")
+		return
+	}
+
+	results, err := service.Search(h.DB, service.SearchOptions{
+		Query:        q,
+		WorkspaceIDs: workspaceIDs,
+		Types:        splitList(c.Query("type")),
+		Methods:      splitList(c.Query("method")),
+		TagExpr:      expr,
+		Limit:        limit,
+	})
+	if err != nil {
+		h.Logger.Error("search failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "search failed")
+		return
+	}
+
+	result.Success(map[string]interface{}{
+		"list": results,
+	})
+}
+
+// splitList 拆分逗号分隔的查询参数，忽略空项
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
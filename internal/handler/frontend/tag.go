@@ -0,0 +1,125 @@
+package frontend
+
+import (
+	"FastGo/internal/handler"
+	"FastGo/internal/router"
+	"FastGo/internal/service"
+	"FastGo/pkg/response"
+	"FastGo/pkg/tagexpr"
+	"FastGo/pkg/validator"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 单个条目最多的标签数量
+const maxTags = 32
+
+type TagHandler struct {
+	*handler.CommonHandler
+}
+
+func NewTagHandler() *TagHandler {
+	return &TagHandler{
+		CommonHandler: handler.NewCommonHandler(),
+	}
+}
+
+func (h *TagHandler) RegisterRoutes(routerRegistry *router.RouteRegistry) {
+	routerRegistry.Register("GET", "tags", "/list", h.List, 2, "获取工作区内的标签及使用次数")
+	routerRegistry.Register("POST", "tags", "/set", h.Set, 2, "设置请求、文件夹或集合的标签")
+}
+
+// List 列出工作区内使用的标签，次数只统计直接设置的标签，不含继承
+func (h *TagHandler) List(c *gin.Context) {
+	result := response.NewResult(c)
+	workspaceID := c.Query("workspace_id")
+	if workspaceID == "" {
+		result.FailWithMsg(response.InvalidParams, "workspace_id is required")
+		return
+	}
+
+	tags, err := service.ListTags(h.DB, cast.ToUint64(workspaceID))
+	if err != nil {
+		h.Logger.Error("list tags failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "list tags failed")
+		return
+	}
+
+	result.Success(map[string]interface{}{
+		"list": tags,
+	})
+}
+
+// Set 替换请求、文件夹或集合的全部标签，传入空列表清除标签
+func (h *TagHandler) Set(c *gin.Context) {
+	var req struct {
+		Kind string   `json:"kind" binding:"required,oneof=collection folder request"`
+		ID   string   `json:"id" binding:"required"`
+		Tags []string `json:"tags"`
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("set tags failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+	if err := validateTags(&req.Tags); err != nil {
+		result.FailWithMsg(response.InvalidParams, err.Error())
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	var tags []string
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		tags, err = service.SetTags(tx, req.Kind, req.ID, req.Tags, cast.ToUint64(userID))
+		return err
+	})
+	switch {
+	case errors.Is(err, service.ErrRequestNotFound), errors.Is(err, service.ErrFolderNotFound),
+		errors.Is(err, service.ErrCollectionNotFound):
+		result.FailWithMsg(response.NotFound, err.Error())
+		return
+	case err != nil:
+		h.Logger.Error("set tags failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "set tags failed")
+		return
+	}
+
+	result.Success(map[string]interface{}{
+		"kind": req.Kind,
+		"id":   req.ID,
+		"tags": tags,
+	})
+}
+
+// validateTags 检查标签能否在标签表达式中使用，未传入标签时跳过
+func validateTags(tags *[]string) error {
+	if tags == nil {
+		return nil
+	}
+	if len(*tags) > maxTags {
+		return fmt.Errorf("at most %d tags are allowed", maxTags)
+	}
+	for _, t := range *tags {
+		t = strings.TrimSpace(t)
+		if len(t) > 64 || (t != "" && !tagexpr.ValidTag(t)) {
+			return fmt.Errorf("invalid tag %q, use letters, digits and - _ . : /", t)
+		}
+	}
+	return nil
+}
+
+// parseTagExpr 解析查询参数中的标签表达式，为空时返回 nil
+func parseTagExpr(s string) (*tagexpr.Expr, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	return tagexpr.Parse(s)
+}
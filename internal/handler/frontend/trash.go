@@ -0,0 +1,98 @@
+package frontend
+
+import (
+	"FastGo/internal/global"
+	"FastGo/internal/handler"
+	"FastGo/internal/router"
+	"FastGo/internal/service"
+	"FastGo/pkg/response"
+	"FastGo/pkg/validator"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type TrashHandler struct {
+	*handler.CommonHandler
+}
+
+func NewTrashHandler() *TrashHandler {
+	return &TrashHandler{
+		CommonHandler: handler.NewCommonHandler(),
+	}
+}
+
+func (h *TrashHandler) RegisterRoutes(routerRegistry *router.RouteRegistry) {
+	routerRegistry.Register("GET", "trash", "/list", h.List, 2, "获取工作区回收站")
+	routerRegistry.Register("POST", "trash", "/restore", h.Restore, 2, "从回收站恢复")
+}
+
+// List 列出工作区回收站中的条目及其永久删除时间
+func (h *TrashHandler) List(c *gin.Context) {
+	result := response.NewResult(c)
+	workspaceID := c.Query("workspace_id")
+	if workspaceID == "" {
+		result.FailWithMsg(response.InvalidParams, "workspace_id is required")
+		return
+	}
+
+	items, err := service.ListTrash(h.DB, cast.ToUint64(workspaceID))
+	if err != nil {
+		h.Logger.Error("list trash failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "list trash failed")
+		return
+	}
+
+	retention := global.Config.Trash.Retention()
+	list := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		list = append(list, map[string]interface{}{
+			"kind":          item.Kind,
+			"id":            item.ID,
+			"name":          item.Name,
+			"collection_id": item.CollectionID,
+			"deleted_at":    item.DeletedAt,
+			"purge_at":      item.DeletedAt.Add(retention),
+		})
+	}
+
+	result.Success(map[string]interface{}{
+		"list": list,
+	})
+}
+
+// Restore 恢复回收站条目及与其同时删除的内容，已删除的上级文件夹、集合和工作区一并恢复
+func (h *TrashHandler) Restore(c *gin.Context) {
+	var req struct {
+		Kind string `json:"kind" binding:"required,oneof=workspace collection folder request"`
+		ID   string `json:"id" binding:"required"`
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("restore trash item failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	var report *service.RestoreReport
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		report, err = service.Restore(tx, req.Kind, req.ID, cast.ToUint64(userID))
+		return err
+	})
+	if errors.Is(err, service.ErrNotInTrash) {
+		result.FailWithMsg(response.NotFound, err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("restore trash item failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "restore failed")
+		return
+	}
+
+	result.Success(report)
+}
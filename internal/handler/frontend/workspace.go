@@ -4,6 +4,7 @@ import (
 	"FastGo/internal/handler"
 	"FastGo/internal/model"
 	"FastGo/internal/router"
+	"FastGo/internal/service"
 	"FastGo/pkg/response"
 	"FastGo/pkg/validator"
 	"errors"
@@ -11,6 +12,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/cast"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type WorkspaceHandler struct {
@@ -28,6 +30,7 @@ func (h *WorkspaceHandler) RegisterRoutes(routerRegistry *router.RouteRegistry)
 	routerRegistry.Register("DELETE", "workspaces", "/delete", h.Delete, 1, "删除工作区")
 	routerRegistry.Register("PUT", "workspaces", "/edit", h.Edit, 1, "重命名工作区")
 	routerRegistry.Register("GET", "workspaces", "/list", h.List, 2, "获取工作区列表")
+	routerRegistry.Register("POST", "workspaces", "/duplicate", h.Duplicate, 2, "复制工作区")
 }
 
 // Create 创建工作空间
@@ -156,12 +159,50 @@ func (h *WorkspaceHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	// 执行删除操作
-	if err := h.DB.Where("id = ?", id).Delete(&model.Workspace{}).Error; err != nil {
+	// 级联删除工作区下的集合、文件夹、请求和环境
+	var report *service.DeleteReport
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		report, err = service.DeleteWorkspace(tx, cast.ToUint64(id))
+		return err
+	})
+	if err != nil {
 		h.Logger.Error("delete workspace failed", zap.Error(err))
 		result.FailWithMsg(response.Success, "delete workspace failed")
 		return
 	}
 
-	result.Success(nil)
+	result.Success(report)
+}
+
+// Duplicate 复制工作区及其下全部集合和环境，副本归属当前用户
+func (h *WorkspaceHandler) Duplicate(c *gin.Context) {
+	var req struct {
+		ID string `json:"id" binding:"required,id"`
+	}
+	result := response.NewResult(c)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("duplicate workspace failed due to invalid parameters", zap.Error(err))
+		result.FailWithError(response.InvalidParams, validator.TranslateError(err))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	var copied *model.Workspace
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		copied, err = service.DuplicateWorkspace(tx, cast.ToUint64(req.ID), cast.ToUint64(userID))
+		return err
+	})
+	if errors.Is(err, service.ErrWorkspaceNotFound) {
+		result.FailWithMsg(response.NotFound, err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("duplicate workspace failed due to database error", zap.Error(err))
+		result.FailWithMsg(response.ServerError, "duplicate workspace failed")
+		return
+	}
+
+	result.Success(copied)
 }
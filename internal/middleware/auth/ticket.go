@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"FastGo/pkg/uid"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrInvalidTicket 票据不存在、已使用或已过期
+var ErrInvalidTicket = errors.New("invalid websocket ticket")
+
+// Ticket 一次性的 WebSocket 连接票据，浏览器无法为 WebSocket 设置请求头，先用令牌换取票据再放在地址参数中
+type Ticket struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+	Role     uint8  `json:"role"`
+}
+
+func ticketKey(ticket string) string {
+	return "ws_ticket:" + ticket
+}
+
+// IssueTicket 签发票据，票据在 ttl 内使用一次后失效
+func IssueTicket(ctx context.Context, rdb *redis.Client, t Ticket, ttl time.Duration) (string, error) {
+	if rdb == nil {
+		return "", errors.New("redis is not configured")
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+	ticket := uid.NewUUID()
+	if err := rdb.Set(ctx, ticketKey(ticket), data, ttl).Err(); err != nil {
+		return "", err
+	}
+	return ticket, nil
+}
+
+// redeemTicket 取出并删除票据
+func redeemTicket(ctx context.Context, rdb *redis.Client, ticket string) (*Ticket, error) {
+	if rdb == nil || ticket == "" {
+		return nil, ErrInvalidTicket
+	}
+	data, err := rdb.GetDel(ctx, ticketKey(ticket)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrInvalidTicket
+	}
+	if err != nil {
+		return nil, err
+	}
+	var t Ticket
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, ErrInvalidTicket
+	}
+	return &t, nil
+}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"FastGo/internal/global"
 	"FastGo/pkg/jwt"
 	"FastGo/pkg/response"
 	"errors"
@@ -20,6 +21,20 @@ var (
 func TokenAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := c.GetHeader("Authorization")
+		// 浏览器建立 WebSocket 连接时无法设置请求头，使用一次性票据，令牌不出现在地址中
+		if token == "" && c.IsWebsocket() {
+			ticket, err := redeemTicket(c.Request.Context(), global.GetRedis(), c.Query("ticket"))
+			if err != nil {
+				response.NewResult(c).Fail(response.Unauthorized)
+				c.Abort()
+				return
+			}
+			c.Set("user_id", ticket.UserID)
+			c.Set("username", ticket.Username)
+			c.Set("role", ticket.Role)
+			c.Next()
+			return
+		}
 		if token == "" {
 			response.NewResult(c).Fail(response.Unauthorized)
 			c.Abort()
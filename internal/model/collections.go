@@ -2,6 +2,8 @@ package model
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // 定义请求类型的枚举
@@ -14,15 +16,18 @@ const (
 
 type Collections struct {
 	ID           uint64         `gorm:"primarykey;autoIncrement" json:"id"`
-	Name         string         `gorm:"type:varchar(128);not null" json:"name"`
+	Name         string         `gorm:"type:varchar(128);not null;index:idx_collection_search,class:FULLTEXT,option:WITH PARSER ngram" json:"name"` // 全文索引用于搜索
 	OwnerID      uint64         `gorm:"not null;index" json:"owner_id"`
 	Protocol     CollectionType `gorm:"type:int(10);not null" json:"protocol"`
 	WorkspaceID  uint64         `gorm:"not null;index" json:"workspace_id"`
 	Description  string         `gorm:"type:text;not null" json:"description"`
 	MembersCount int            `gorm:"type:int(10);not null;default:1" json:"members_count"`
 	CollectionID string         `gorm:"type:varchar(128);not null;index" json:"collection_id"`
+	Tags         string         `gorm:"type:text" json:"-"`                               // 标签，JSON 数组
+	Defaults     Defaults       `gorm:"embedded;embeddedPrefix:default_" json:"defaults"` // 文件夹和请求继承的默认值
 	CreatedAt    time.Time      `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt    time.Time      `gorm:"type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at"` // 移入回收站的时间
 }
 
 func (Collections) TableName() string {
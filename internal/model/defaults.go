@@ -0,0 +1,57 @@
+package model
+
+import "encoding/json"
+
+// 认证方式
+const (
+	AuthInherit = ""       // 继承上级设置
+	AuthNone    = "none"   // 不认证，同时阻止继承
+	AuthBearer  = "bearer" // Authorization: Bearer <token>
+	AuthBasic   = "basic"  // Authorization: Basic base64(username:password)
+	AuthAPIKey  = "apikey" // 自定义请求头或查询参数
+)
+
+// Auth 认证设置
+type Auth struct {
+	Type     string `json:"type"`
+	Token    string `json:"token,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Key      string `json:"key,omitempty"`
+	Value    string `json:"value,omitempty"`
+	In       string `json:"in,omitempty"` // apikey 的位置：header（默认）、query
+}
+
+// EncodeAuth 将认证设置编码为存储用的 JSON 字符串，继承时为空
+func EncodeAuth(auth *Auth) string {
+	if auth == nil || auth.Type == AuthInherit {
+		return ""
+	}
+	data, err := json.Marshal(auth)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// DecodeAuth 解析存储的认证设置，为空或无法解析时返回继承
+func DecodeAuth(s string) Auth {
+	var auth Auth
+	if s == "" {
+		return auth
+	}
+	if err := json.Unmarshal([]byte(s), &auth); err != nil {
+		return Auth{}
+	}
+	return auth
+}
+
+// Defaults 集合和文件夹上的请求默认值，沿文件夹层级向下继承，下级的设置优先
+// Timeout、RetryCount 为 0 表示继承上级
+type Defaults struct {
+	BaseURL    string `gorm:"type:varchar(255)" json:"base_url"` // 请求地址以 / 开头或为空时拼接在前面
+	Headers    string `gorm:"type:text" json:"headers"`          // 默认请求头，KeyValue JSON
+	Auth       string `gorm:"type:text" json:"auth"`             // 默认认证，Auth JSON
+	Timeout    int    `gorm:"type:int" json:"timeout"`           // 超时时间，毫秒
+	RetryCount int    `gorm:"type:int" json:"retry_count"`
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// Environment 环境变量集合，可归属于工作区或某个集合
+type Environment struct {
+	ID            uint64    `gorm:"primarykey;autoIncrement" json:"id"`                                                     // 环境ID
+	EnvironmentID string    `gorm:"type:varchar(128);not null;index" json:"environment_id"`                                 // 环境唯一标识
+	WorkspaceID   uint64    `gorm:"not null;index" json:"workspace_id"`                                                     // 所属工作区
+	CollectionID  string    `gorm:"type:varchar(128);index" json:"collection_id"`                                           // 所属集合，为空表示工作区级别
+	Name          string    `gorm:"type:varchar(128);not null" json:"name"`                                                 // 环境名称
+	Variables     string    `gorm:"type:text" json:"variables"`                                                             // 变量列表，KeyValue JSON
+	CreatedAt     time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`                             // 创建时间
+	UpdatedAt     time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"` // 更新时间
+}
+
+func (Environment) TableName() string {
+	return "environments"
+}
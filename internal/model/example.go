@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// RequestExample 请求的响应示例
+type RequestExample struct {
+	ID          uint64    `gorm:"primarykey;autoIncrement" json:"id"`                                                     // 示例ID
+	ExampleID   string    `gorm:"type:varchar(128);not null;index" json:"example_id"`                                     // 示例唯一标识
+	RequestID   string    `gorm:"type:varchar(128);not null;index" json:"request_id"`                                     // 所属请求
+	Name        string    `gorm:"type:varchar(128);not null" json:"name"`                                                 // 示例名称
+	Status      int       `gorm:"type:int" json:"status"`                                                                 // 响应状态码
+	StatusText  string    `gorm:"type:varchar(64)" json:"status_text"`                                                    // 状态描述
+	ContentType string    `gorm:"type:varchar(128)" json:"content_type"`                                                  // 响应内容类型
+	Headers     string    `gorm:"type:text" json:"headers"`                                                               // 响应头，KeyValue JSON
+	Body        string    `gorm:"type:longtext" json:"body"`                                                              // 响应体
+	CreatedAt   time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`                             // 创建时间
+	UpdatedAt   time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"` // 更新时间
+}
+
+func (RequestExample) TableName() string {
+	return "request_examples"
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// Execution 请求的一次发送记录
+type Execution struct {
+	ID          uint64      `gorm:"primarykey;autoIncrement" json:"id"`                               // ID
+	ExecutionID string      `gorm:"type:varchar(128);not null;uniqueIndex" json:"execution_id"`       // 执行唯一标识
+	RequestID   string      `gorm:"type:varchar(128);not null;index" json:"request_id"`               // 所属请求
+	UserID      uint64      `gorm:"not null;index" json:"user_id"`                                    // 执行人
+	Type        RequestType `gorm:"type:varchar(64);not null" json:"type"`                            // 请求类型
+	Success     bool        `gorm:"not null" json:"success"`                                          // 是否成功完成
+	StatusCode  int         `gorm:"type:int" json:"status_code"`                                      // 状态码
+	Duration    int64       `gorm:"not null" json:"duration"`                                         // 耗时，毫秒
+	Error       string      `gorm:"type:text" json:"error"`                                           // 失败原因
+	Request     string      `gorm:"type:longtext" json:"request"`                                     // 变量替换后的请求快照，JSON
+	Response    string      `gorm:"type:longtext" json:"response"`                                    // 响应结果，JSON
+	Transcript  string      `gorm:"type:longtext" json:"transcript"`                                  // 流式请求的消息记录，JSON 数组
+	CreatedAt   time.Time   `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;index" json:"created_at"` // 执行时间
+}
+
+func (Execution) TableName() string {
+	return "executions"
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// Favorite 用户置顶的集合或请求
+type Favorite struct {
+	ID        uint64    `gorm:"primarykey;autoIncrement" json:"id"`
+	UserID    uint64    `gorm:"not null;uniqueIndex:idx_favorite_target" json:"user_id"`                           // 所属用户
+	Kind      string    `gorm:"type:varchar(32);not null;uniqueIndex:idx_favorite_target" json:"kind"`             // collection 或 request
+	TargetID  string    `gorm:"type:varchar(128);not null;uniqueIndex:idx_favorite_target;index" json:"target_id"` // CollectionID 或 RequestID
+	CreatedAt time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`                        // 置顶时间
+}
+
+func (Favorite) TableName() string {
+	return "favorites"
+}
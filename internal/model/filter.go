@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// SavedFilter 用户在工作区内保存的命名筛选条件
+type SavedFilter struct {
+	ID          uint64    `gorm:"primarykey;autoIncrement" json:"id"`
+	FilterID    string    `gorm:"type:varchar(128);not null;uniqueIndex" json:"filter_id"`                                // 筛选条件唯一标识
+	UserID      uint64    `gorm:"not null;index:idx_filter_owner" json:"user_id"`                                         // 所属用户
+	WorkspaceID uint64    `gorm:"not null;index:idx_filter_owner" json:"workspace_id"`                                    // 所属工作区
+	Name        string    `gorm:"type:varchar(128);not null" json:"name"`                                                 // 名称
+	TagExpr     string    `gorm:"type:varchar(512)" json:"tag_expr"`                                                      // 标签表达式，为空表示不按标签筛选
+	Query       string    `gorm:"type:varchar(255)" json:"query"`                                                         // 全文搜索关键词，为空表示只按标签筛选
+	Types       string    `gorm:"type:varchar(255)" json:"types"`                                                         // 请求类型，逗号分隔
+	Methods     string    `gorm:"type:varchar(255)" json:"methods"`                                                       // 请求方法，逗号分隔
+	CreatedAt   time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`                             // 创建时间
+	UpdatedAt   time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"` // 更新时间
+}
+
+func (SavedFilter) TableName() string {
+	return "saved_filters"
+}
@@ -1,14 +1,22 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Folder struct {
-	ID           uint64    `gorm:"primarykey;autoIncrement" json:"id"`                                                     // 文件夹ID
-	CollectionID string    `gorm:"type:varchar(128);not null;index" json:"collection_id"`                                  // 关联到集合
-	Name         string    `gorm:"type:varchar(128);not null" json:"name"`                                                 // 文件夹名称
-	FolderID     string    `gorm:"type:varchar(128);not null;index" json:"folder_id"`                                      // 文件夹ID
-	CreatedAt    time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`                             // 创建时间
-	UpdatedAt    time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"` // 更新时间
+	ID           uint64         `gorm:"primarykey;autoIncrement" json:"id"`                                                                     // 文件夹ID
+	CollectionID string         `gorm:"type:varchar(128);not null;index" json:"collection_id"`                                                  // 关联到集合
+	Name         string         `gorm:"type:varchar(128);not null;index:idx_folder_search,class:FULLTEXT,option:WITH PARSER ngram" json:"name"` // 文件夹名称，全文索引用于搜索
+	FolderID     string         `gorm:"type:varchar(128);not null;index" json:"folder_id"`                                                      // 文件夹ID
+	SortOrder    int            `gorm:"type:int;not null;default:0" json:"sort_order"`                                                          // 同级排序，越小越靠前
+	Tags         string         `gorm:"type:text" json:"-"`                                                                                     // 标签，JSON 数组
+	Defaults     Defaults       `gorm:"embedded;embeddedPrefix:default_" json:"defaults"`                                                       // 子文件夹和请求继承的默认值
+	CreatedAt    time.Time      `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`                                             // 创建时间
+	UpdatedAt    time.Time      `gorm:"type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`                 // 更新时间
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at"`                                                                                // 移入回收站的时间，闭包关系保留到永久删除
 }
 
 func (Folder) TableName() string {
@@ -16,9 +24,9 @@ func (Folder) TableName() string {
 }
 
 type FolderClosure struct {
-	Ancestor   string `gorm:"not null;index"` // 祖先文件夹的 ID
-	Descendant string `gorm:"not null;index"` // 后代文件夹的 ID
-	Depth      int    `gorm:"not null"`       // 祖先与后代之间的距离
+	Ancestor   string `gorm:"type:varchar(128);not null;index"` // 祖先文件夹的 ID
+	Descendant string `gorm:"type:varchar(128);not null;index"` // 后代文件夹的 ID
+	Depth      int    `gorm:"not null"`                         // 祖先与后代之间的距离
 }
 
 func (FolderClosure) TableName() string {
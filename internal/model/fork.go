@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// CollectionFork 从源集合复制到个人工作区的分支集合
+type CollectionFork struct {
+	ID                 uint64    `gorm:"primarykey;autoIncrement" json:"id"`
+	ForkID             string    `gorm:"type:varchar(128);not null;uniqueIndex" json:"fork_id"`                                  // 分支唯一标识
+	SourceCollectionID string    `gorm:"type:varchar(128);not null;index" json:"source_collection_id"`                           // 源集合
+	ForkCollectionID   string    `gorm:"type:varchar(128);not null;uniqueIndex" json:"fork_collection_id"`                       // 分支集合
+	OwnerID            uint64    `gorm:"not null;index" json:"owner_id"`                                                         // 创建人
+	Links              string    `gorm:"type:longtext" json:"-"`                                                                 // 源集合文件夹、请求 ID 到分支中 ID 的映射，JSON
+	Base               string    `gorm:"type:longtext" json:"-"`                                                                 // 上次复制或合并时的共同版本，按源集合中的 ID，JSON
+	CreatedAt          time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`                             // 创建时间
+	UpdatedAt          time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"` // 最近一次合并时间
+}
+
+func (CollectionFork) TableName() string {
+	return "collection_forks"
+}
+
+// 合并请求状态
+const (
+	MergeOpen   = "open"
+	MergeMerged = "merged"
+	MergeClosed = "closed"
+)
+
+// MergeRequest 将分支中的修改合并回源集合的请求
+type MergeRequest struct {
+	ID          uint64     `gorm:"primarykey;autoIncrement" json:"id"`
+	MergeID     string     `gorm:"type:varchar(128);not null;uniqueIndex" json:"merge_id"`                                 // 合并请求唯一标识
+	ForkID      string     `gorm:"type:varchar(128);not null;index" json:"fork_id"`                                        // 所属分支
+	Title       string     `gorm:"type:varchar(255);not null" json:"title"`                                                // 标题
+	Status      string     `gorm:"type:varchar(32);not null;index" json:"status"`                                          // 状态
+	AuthorID    uint64     `gorm:"not null" json:"author_id"`                                                              // 创建人
+	Resolutions string     `gorm:"type:text" json:"-"`                                                                     // 冲突的处理方式，条目 ID 到 ours/theirs 的映射，JSON
+	MergedBy    uint64     `gorm:"not null;default:0" json:"merged_by"`                                                    // 执行合并的用户
+	MergedAt    *time.Time `gorm:"type:timestamp NULL" json:"merged_at"`                                                   // 合并时间
+	CreatedAt   time.Time  `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`                             // 创建时间
+	UpdatedAt   time.Time  `gorm:"type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"` // 更新时间
+}
+
+func (MergeRequest) TableName() string {
+	return "merge_requests"
+}
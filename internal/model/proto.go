@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// ProtoSchema 上传或反射得到的 proto 描述，按集合保存
+type ProtoSchema struct {
+	ID           uint64    `gorm:"primarykey;autoIncrement" json:"id"`                                                     // ID
+	SchemaID     string    `gorm:"type:varchar(128);not null;uniqueIndex" json:"schema_id"`                                // 描述唯一标识
+	CollectionID string    `gorm:"type:varchar(128);not null;index" json:"collection_id"`                                  // 所属集合
+	Name         string    `gorm:"type:varchar(128);not null" json:"name"`                                                 // 名称
+	Source       string    `gorm:"type:varchar(32);not null" json:"source"`                                                // 来源：upload、reflection
+	Target       string    `gorm:"type:varchar(255)" json:"target"`                                                        // 反射时的服务地址
+	Descriptor   []byte    `gorm:"type:longblob" json:"-"`                                                                 // 序列化后的 FileDescriptorSet
+	CreatedAt    time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`                             // 创建时间
+	UpdatedAt    time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"` // 更新时间
+}
+
+// ProtoSchema 来源
+const (
+	ProtoSourceUpload     = "upload"
+	ProtoSourceReflection = "reflection"
+)
+
+func (ProtoSchema) TableName() string {
+	return "proto_schemas"
+}
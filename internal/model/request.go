@@ -1,6 +1,11 @@
 package model
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // 定义请求类型的枚举
 type RequestType string
@@ -9,37 +14,84 @@ const (
 	HTTP1     RequestType = "HTTP"
 	WebSocket RequestType = "WebSocket"
 	GRPC1     RequestType = "gRPC"
+	GraphQL   RequestType = "GraphQL"
+	SSE       RequestType = "SSE"
+	JSONRPC   RequestType = "JSON-RPC"
 )
 
 type RequestMethod string
 
 const (
-	GET    RequestMethod = "GET"
-	POST   RequestMethod = "POST"
-	PUT    RequestMethod = "PUT"
-	DELETE RequestMethod = "DELETE"
+	GET     RequestMethod = "GET"
+	POST    RequestMethod = "POST"
+	PUT     RequestMethod = "PUT"
+	DELETE  RequestMethod = "DELETE"
+	PATCH   RequestMethod = "PATCH"
+	HEAD    RequestMethod = "HEAD"
+	OPTIONS RequestMethod = "OPTIONS"
 )
 
+// KeyValue 请求头、查询参数、变量等键值对
+type KeyValue struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Enabled     bool   `json:"enabled"`
+	Description string `json:"description,omitempty"`
+}
+
+// EncodeKeyValues 将键值对列表编码为存储用的 JSON 字符串
+func EncodeKeyValues(kvs []KeyValue) string {
+	if len(kvs) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(kvs)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// DecodeKeyValues 解析存储的键值对 JSON 字符串
+func DecodeKeyValues(s string) []KeyValue {
+	kvs := []KeyValue{}
+	if s == "" {
+		return kvs
+	}
+	if err := json.Unmarshal([]byte(s), &kvs); err != nil {
+		return []KeyValue{}
+	}
+	return kvs
+}
+
 type Request struct {
-	ID           uint64        `gorm:"primaryKey;autoIncrement"`
-	Name         string        `gorm:"type:varchar(128);not null"`
-	CollectionID string        `gorm:"type:varchar(128);not null;index"`
-	FolderID     string        `gorm:"type:varchar(128);not null;index"`
-	RequestID    string        `gorm:"type:varchar(128);not null;index"`
-	Method       RequestMethod `gorm:"type:varchar(64);not null"`
-	Path         string        `gorm:"type:varchar(128);not null"`
-	Type         RequestType   `gorm:"type:varchar(64);not null"`
-	Headers      string        `gorm:"type:text"`
-	Body         string        `gorm:"type:text"`
-	QueryParams  string        `gorm:"type:text"`
-	Status       string        `gorm:"type:varchar(64)"`
-	Response     string        `gorm:"type:text"`
-	Timeout      int           `gorm:"type:int"`
-	RetryCount   int           `gorm:"type:int"`
-	Priority     int           `gorm:"type:int"`
-	Description  string        `gorm:"type:text"`
-	CreatedAt    time.Time     `gorm:"type:timestamp;default:CURRENT_TIMESTAMP"`
-	UpdatedAt    time.Time     `gorm:"type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP"`
+	ID           uint64         `gorm:"primaryKey;autoIncrement"`
+	Name         string         `gorm:"type:varchar(128);not null;index:idx_request_search,class:FULLTEXT,option:WITH PARSER ngram"`
+	CollectionID string         `gorm:"type:varchar(128);not null;index"`
+	FolderID     string         `gorm:"type:varchar(128);not null;index"`
+	SortOrder    int            `gorm:"type:int;not null;default:0"` // 同级排序，越小越靠前
+	RequestID    string         `gorm:"type:varchar(128);not null;index"`
+	Method       RequestMethod  `gorm:"type:varchar(64);not null"`
+	Path         string         `gorm:"type:varchar(128);not null;index:idx_request_search"`
+	Type         RequestType    `gorm:"type:varchar(64);not null"`
+	Transport    string         `gorm:"type:varchar(32)"` // gRPC 传输方式：grpc、grpc-web、grpc-web-text、connect-json、connect-proto
+	Headers      string         `gorm:"type:text;index:idx_request_search"`
+	Body         string         `gorm:"type:text;index:idx_request_search"`
+	Query        string         `gorm:"type:text"` // GraphQL 查询语句
+	Variables    string         `gorm:"type:text"` // GraphQL 变量，JSON 对象
+	QueryParams  string         `gorm:"type:text"`
+	Auth         string         `gorm:"type:text"` // 认证设置，Auth JSON，为空表示继承集合或文件夹
+	Assertions   string         `gorm:"type:text"` // 断言列表，JSON 数组
+	Status       string         `gorm:"type:varchar(64)"`
+	Response     string         `gorm:"type:text"`
+	Timeout      int            `gorm:"type:int"` // 超时时间，毫秒
+	RetryCount   int            `gorm:"type:int"`
+	Priority     int            `gorm:"type:int"`
+	Description  string         `gorm:"type:text;index:idx_request_search"` // 名称、地址、描述、请求头和请求体建立全文索引，用于搜索
+	Tags         string         `gorm:"type:text"`                          // 标签，JSON 数组，文件夹和集合的标签由其中的请求继承
+	SourceKey    string         `gorm:"type:varchar(255);index"`            // 导入来源标识，重复导入时用于匹配已有请求
+	CreatedAt    time.Time      `gorm:"type:timestamp;default:CURRENT_TIMESTAMP"`
+	UpdatedAt    time.Time      `gorm:"type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP"`
+	DeletedAt    gorm.DeletedAt `gorm:"index"` // 移入回收站的时间
 }
 
 func (Request) TableName() string {
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// 修订记录的操作类型
+const (
+	RevisionCreate  = "create"  // 创建、复制或导入新请求
+	RevisionUpdate  = "update"  // 编辑或导入覆盖
+	RevisionMove    = "move"    // 移动到其他文件夹或集合
+	RevisionRestore = "restore" // 恢复到历史修订或从回收站恢复
+	RevisionDelete  = "delete"  // 移入回收站
+)
+
+// RequestRevision 请求每次保存后的不可变快照
+type RequestRevision struct {
+	ID           uint64    `gorm:"primarykey;autoIncrement" json:"id"`                                                      // ID
+	RevisionID   string    `gorm:"type:varchar(128);not null;uniqueIndex" json:"revision_id"`                               // 修订唯一标识
+	RequestID    string    `gorm:"type:varchar(128);not null;uniqueIndex:idx_request_version,priority:1" json:"request_id"` // 所属请求
+	CollectionID string    `gorm:"type:varchar(128);not null;index" json:"collection_id"`                                   // 保存时所在集合，用于集合变更日志
+	Version      int       `gorm:"type:int;not null;uniqueIndex:idx_request_version,priority:2" json:"version"`             // 请求内从 1 开始递增的版本号
+	Action       string    `gorm:"type:varchar(32);not null" json:"action"`                                                 // 操作类型
+	AuthorID     uint64    `gorm:"not null;index" json:"author_id"`                                                         // 保存人
+	Snapshot     string    `gorm:"type:longtext" json:"-"`                                                                  // 请求内容快照，JSON
+	CreatedAt    time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`                              // 保存时间
+}
+
+func (RequestRevision) TableName() string {
+	return "request_revisions"
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// NormalizeTags 去掉首尾空白并转为小写，去重后排序，空标签被忽略
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	out := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// EncodeTags 将标签列表编码为存储用的 JSON 字符串
+func EncodeTags(tags []string) string {
+	tags = NormalizeTags(tags)
+	if len(tags) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(tags)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// DecodeTags 解析存储的标签 JSON 字符串
+func DecodeTags(s string) []string {
+	tags := []string{}
+	if s == "" {
+		return tags
+	}
+	if err := json.Unmarshal([]byte(s), &tags); err != nil {
+		return []string{}
+	}
+	return tags
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"FastGo/internal/model"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DeleteReport 级联删除的统计结果
+type DeleteReport struct {
+	Workspaces   int64 `json:"workspaces"`
+	Collections  int64 `json:"collections"`
+	Folders      int64 `json:"folders"`
+	Closures     int64 `json:"closures"`
+	Requests     int64 `json:"requests"`
+	Examples     int64 `json:"examples"`
+	Revisions    int64 `json:"revisions"`
+	Favorites    int64 `json:"favorites"`
+	Forks        int64 `json:"forks"`
+	Environments int64 `json:"environments"`
+	ProtoSchemas int64 `json:"proto_schemas"`
+	Rehomed      int64 `json:"rehomed"` // 移动到上级目录的子文件夹和请求数量
+}
+
+// 删除操作将工作区、集合、文件夹和请求移入回收站：同一次删除的记录使用相同的删除时间，
+// 恢复时据此找回一起删除的内容。闭包关系、响应示例、环境和 proto 描述保留到永久删除。
+
+// DeleteFolder 将文件夹移入回收站，需要在事务中调用
+// rehome 为 false 时删除整个子树（子文件夹及其中的请求），
+// 为 true 时只删除该文件夹，直接子文件夹和请求移动到其父文件夹或集合根目录，移动的请求记录移动修订
+func DeleteFolder(tx *gorm.DB, folderID string, rehome bool, authorID uint64) (*DeleteReport, error) {
+	var folder model.Folder
+	if err := tx.Where("folder_id = ?", folderID).First(&folder).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFolderNotFound
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	report := &DeleteReport{}
+	if !rehome {
+		var ids []string
+		err := tx.Model(&model.FolderClosure{}).Where("ancestor = ?", folderID).Pluck("descendant", &ids).Error
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 {
+			ids = []string{folderID}
+		}
+		if err := trashFolders(tx, ids, now, report); err != nil {
+			return nil, err
+		}
+		return report, nil
+	}
+
+	parentID, err := parentFolderID(tx, folderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 已在回收站中的子文件夹保持原位
+	var children []string
+	err = tx.Model(&model.Folder{}).
+		Where("folder_id IN (?)", tx.Model(&model.FolderClosure{}).Select("descendant").Where("ancestor = ? AND depth = 1", folderID)).
+		Pluck("folder_id", &children).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range children {
+		if err := MoveFolder(tx, child, parentID, folder.CollectionID, authorID); err != nil {
+			return nil, err
+		}
+	}
+
+	var requests []model.Request
+	if err := tx.Where("folder_id = ?", folderID).Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	if len(requests) > 0 {
+		if err := tx.Model(&model.Request{}).Where("folder_id = ?", folderID).Update("folder_id", parentID).Error; err != nil {
+			return nil, err
+		}
+		for i := range requests {
+			requests[i].FolderID = parentID
+		}
+		if err := recordMoved(tx, requests, authorID); err != nil {
+			return nil, err
+		}
+	}
+	report.Rehomed = int64(len(children) + len(requests))
+
+	if err := trashFolders(tx, []string{folderID}, now, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// DeleteCollections 将集合及其下的文件夹、请求移入回收站，需要在事务中调用
+func DeleteCollections(tx *gorm.DB, collectionIDs []string) (*DeleteReport, error) {
+	report := &DeleteReport{}
+	if err := trashCollections(tx, collectionIDs, time.Now(), report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// DeleteWorkspace 将工作区及其下全部集合移入回收站，需要在事务中调用
+func DeleteWorkspace(tx *gorm.DB, workspaceID uint64) (*DeleteReport, error) {
+	now := time.Now()
+	report := &DeleteReport{}
+
+	var collectionIDs []string
+	err := tx.Model(&model.Collections{}).Where("workspace_id = ?", workspaceID).
+		Pluck("collection_id", &collectionIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	if err := trashCollections(tx, collectionIDs, now, report); err != nil {
+		return nil, err
+	}
+
+	res := tx.Model(&model.Workspace{}).Where("id = ?", workspaceID).Update("deleted_at", now)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	report.Workspaces = res.RowsAffected
+	return report, nil
+}
+
+func trashCollections(tx *gorm.DB, collectionIDs []string, now time.Time, report *DeleteReport) error {
+	if len(collectionIDs) == 0 {
+		return nil
+	}
+
+	var folderIDs []string
+	err := tx.Model(&model.Folder{}).Where("collection_id IN (?)", collectionIDs).Pluck("folder_id", &folderIDs).Error
+	if err != nil {
+		return err
+	}
+	if err := trashFolders(tx, folderIDs, now, report); err != nil {
+		return err
+	}
+
+	// 根目录下的请求
+	if err := trashRequests(tx, "collection_id IN (?)", collectionIDs, now, report); err != nil {
+		return err
+	}
+
+	res := tx.Model(&model.Collections{}).Where("collection_id IN (?)", collectionIDs).Update("deleted_at", now)
+	if res.Error != nil {
+		return res.Error
+	}
+	report.Collections += res.RowsAffected
+	return nil
+}
+
+// trashFolders 将文件夹及其中的请求移入回收站，已在回收站中的记录保留原删除时间
+func trashFolders(tx *gorm.DB, folderIDs []string, now time.Time, report *DeleteReport) error {
+	if len(folderIDs) == 0 {
+		return nil
+	}
+	if err := trashRequests(tx, "folder_id IN (?)", folderIDs, now, report); err != nil {
+		return err
+	}
+
+	res := tx.Model(&model.Folder{}).Where("folder_id IN (?)", folderIDs).Update("deleted_at", now)
+	if res.Error != nil {
+		return res.Error
+	}
+	report.Folders += res.RowsAffected
+	return nil
+}
+
+func trashRequests(tx *gorm.DB, query string, ids []string, now time.Time, report *DeleteReport) error {
+	res := tx.Model(&model.Request{}).Where(query, ids).Update("deleted_at", now)
+	if res.Error != nil {
+		return res.Error
+	}
+	report.Requests += res.RowsAffected
+	return nil
+}
+
+// PurgeTrash 永久删除 before 之前移入回收站的内容及其闭包关系、响应示例、修订、置顶、环境和 proto 描述
+// 需要在事务中调用
+func PurgeTrash(tx *gorm.DB, before time.Time) (*DeleteReport, error) {
+	report := &DeleteReport{}
+	expired := "deleted_at IS NOT NULL AND deleted_at < ?"
+
+	var workspaceIDs []uint64
+	err := tx.Unscoped().Model(&model.Workspace{}).Where(expired, before).Pluck("id", &workspaceIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	var collectionIDs []string
+	err = tx.Unscoped().Model(&model.Collections{}).Where(expired, before).Pluck("collection_id", &collectionIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// 已删除集合中的文件夹和请求随集合一起删除
+	var folderIDs []string
+	query := tx.Unscoped().Model(&model.Folder{}).Where(expired, before)
+	if len(collectionIDs) > 0 {
+		query = query.Or("collection_id IN (?)", collectionIDs)
+	}
+	if err := query.Pluck("folder_id", &folderIDs).Error; err != nil {
+		return nil, err
+	}
+	if err := purgeRequests(tx, report, expired, before); err != nil {
+		return nil, err
+	}
+	if len(folderIDs) > 0 {
+		if err := purgeRequests(tx, report, "folder_id IN (?)", folderIDs); err != nil {
+			return nil, err
+		}
+		res := tx.Where("ancestor IN (?) OR descendant IN (?)", folderIDs, folderIDs).Delete(&model.FolderClosure{})
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		report.Closures += res.RowsAffected
+		res = tx.Unscoped().Where("folder_id IN (?)", folderIDs).Delete(&model.Folder{})
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		report.Folders += res.RowsAffected
+	}
+
+	if len(collectionIDs) > 0 {
+		if err := purgeRequests(tx, report, "collection_id IN (?)", collectionIDs); err != nil {
+			return nil, err
+		}
+		res := tx.Where("collection_id IN (?)", collectionIDs).Delete(&model.Environment{})
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		report.Environments += res.RowsAffected
+		res = tx.Where("collection_id IN (?)", collectionIDs).Delete(&model.ProtoSchema{})
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		report.ProtoSchemas += res.RowsAffected
+		res = tx.Where("kind = ? AND target_id IN (?)", KindCollection, collectionIDs).Delete(&model.Favorite{})
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		report.Favorites += res.RowsAffected
+		// 源集合或分支集合被删除时，分支及其合并请求一并删除
+		forks := tx.Model(&model.CollectionFork{}).Select("fork_id").
+			Where("source_collection_id IN (?) OR fork_collection_id IN (?)", collectionIDs, collectionIDs)
+		if err := tx.Where("fork_id IN (?)", forks).Delete(&model.MergeRequest{}).Error; err != nil {
+			return nil, err
+		}
+		res = tx.Where("source_collection_id IN (?) OR fork_collection_id IN (?)", collectionIDs, collectionIDs).
+			Delete(&model.CollectionFork{})
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		report.Forks += res.RowsAffected
+		res = tx.Unscoped().Where("collection_id IN (?)", collectionIDs).Delete(&model.Collections{})
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		report.Collections += res.RowsAffected
+	}
+
+	if len(workspaceIDs) > 0 {
+		res := tx.Where("workspace_id IN (?)", workspaceIDs).Delete(&model.Environment{})
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		report.Environments += res.RowsAffected
+		res = tx.Unscoped().Where("id IN (?)", workspaceIDs).Delete(&model.Workspace{})
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		report.Workspaces += res.RowsAffected
+	}
+	return report, nil
+}
+
+// purgeRequests 永久删除满足条件的请求及其响应示例、修订和置顶，执行记录作为历史保留
+func purgeRequests(tx *gorm.DB, report *DeleteReport, query string, args ...interface{}) error {
+	var requestIDs []string
+	if err := tx.Unscoped().Model(&model.Request{}).Where(query, args...).Pluck("request_id", &requestIDs).Error; err != nil {
+		return err
+	}
+	if len(requestIDs) == 0 {
+		return nil
+	}
+
+	res := tx.Where("request_id IN (?)", requestIDs).Delete(&model.RequestExample{})
+	if res.Error != nil {
+		return res.Error
+	}
+	report.Examples += res.RowsAffected
+
+	res = tx.Where("request_id IN (?)", requestIDs).Delete(&model.RequestRevision{})
+	if res.Error != nil {
+		return res.Error
+	}
+	report.Revisions += res.RowsAffected
+
+	res = tx.Where("kind = ? AND target_id IN (?)", KindRequest, requestIDs).Delete(&model.Favorite{})
+	if res.Error != nil {
+		return res.Error
+	}
+	report.Favorites += res.RowsAffected
+
+	res = tx.Unscoped().Where("request_id IN (?)", requestIDs).Delete(&model.Request{})
+	if res.Error != nil {
+		return res.Error
+	}
+	report.Requests += res.RowsAffected
+	return nil
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"FastGo/internal/model"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// 取值来源
+const (
+	OriginCollection = "collection"
+	OriginFolder     = "folder"
+	OriginRequest    = "request"
+)
+
+// Origin 合并后取值的来源，Kind 为空表示各级都未设置，使用内置默认值
+type Origin struct {
+	Kind string `json:"kind,omitempty"`
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// ResolvedValue 合并后的取值及其来源
+type ResolvedValue struct {
+	Value  interface{} `json:"value"`
+	Origin Origin      `json:"origin"`
+}
+
+// ResolvedHeader 合并后的请求头及其来源
+type ResolvedHeader struct {
+	model.KeyValue
+	Origin Origin `json:"origin"`
+}
+
+// ResolvedRequest 合并集合、文件夹默认值后的请求
+type ResolvedRequest struct {
+	Request    model.Request    `json:"-"`
+	URL        ResolvedValue    `json:"url"` // 来源为提供 BaseURL 的层级，未拼接时为请求本身
+	Headers    []ResolvedHeader `json:"headers"`
+	Auth       ResolvedValue    `json:"auth"`
+	Timeout    ResolvedValue    `json:"timeout"`
+	RetryCount ResolvedValue    `json:"retry_count"`
+}
+
+// defaultsLayer 一级默认值
+type defaultsLayer struct {
+	origin   Origin
+	defaults model.Defaults
+}
+
+// ResolveRequest 沿文件夹闭包表从集合根目录向下合并默认值，集合或文件夹缺失时跳过对应层级
+func ResolveRequest(db *gorm.DB, req model.Request) (*ResolvedRequest, error) {
+	var layers []defaultsLayer
+
+	var collection model.Collections
+	err := db.Where("collection_id = ?", req.CollectionID).First(&collection).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		layers = append(layers, defaultsLayer{
+			origin:   Origin{Kind: OriginCollection, ID: collection.CollectionID, Name: collection.Name},
+			defaults: collection.Defaults,
+		})
+	}
+
+	if req.FolderID != "" {
+		var folders []model.Folder
+		err := db.Model(&model.Folder{}).Select("folders.*").
+			Joins("JOIN folder_closures ON folder_closures.ancestor = folders.folder_id").
+			Where("folder_closures.descendant = ?", req.FolderID).
+			Order("folder_closures.depth DESC").Find(&folders).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range folders {
+			layers = append(layers, defaultsLayer{
+				origin:   Origin{Kind: OriginFolder, ID: f.FolderID, Name: f.Name},
+				defaults: f.Defaults,
+			})
+		}
+	}
+
+	return mergeDefaults(req, layers), nil
+}
+
+// mergeDefaults 按从上到下的顺序合并各级默认值，越靠近请求的设置优先，请求本身的设置最优先
+// 请求头按名称（不区分大小写）覆盖，请求中禁用的同名请求头会屏蔽继承的值
+func mergeDefaults(req model.Request, layers []defaultsLayer) *ResolvedRequest {
+	self := Origin{Kind: OriginRequest, ID: req.RequestID, Name: req.Name}
+	resolved := &ResolvedRequest{URL: ResolvedValue{Origin: self}}
+
+	var headers []ResolvedHeader
+	index := map[string]int{}
+	setHeader := func(kv model.KeyValue, origin Origin) {
+		key := strings.ToLower(kv.Key)
+		if i, ok := index[key]; ok {
+			headers[i] = ResolvedHeader{KeyValue: kv, Origin: origin}
+			return
+		}
+		index[key] = len(headers)
+		headers = append(headers, ResolvedHeader{KeyValue: kv, Origin: origin})
+	}
+
+	var baseURL string
+	var baseOrigin Origin
+	auth := model.Auth{}
+	var authOrigin, timeoutOrigin, retryOrigin Origin
+	timeout, retry := 0, 0
+	for _, l := range layers {
+		if l.defaults.BaseURL != "" {
+			baseURL, baseOrigin = l.defaults.BaseURL, l.origin
+		}
+		for _, kv := range model.DecodeKeyValues(l.defaults.Headers) {
+			if kv.Enabled && kv.Key != "" {
+				setHeader(kv, l.origin)
+			}
+		}
+		if a := model.DecodeAuth(l.defaults.Auth); a.Type != model.AuthInherit {
+			auth, authOrigin = a, l.origin
+		}
+		if l.defaults.Timeout > 0 {
+			timeout, timeoutOrigin = l.defaults.Timeout, l.origin
+		}
+		if l.defaults.RetryCount > 0 {
+			retry, retryOrigin = l.defaults.RetryCount, l.origin
+		}
+	}
+
+	for _, kv := range model.DecodeKeyValues(req.Headers) {
+		if kv.Key != "" {
+			setHeader(kv, self)
+		}
+	}
+	if a := model.DecodeAuth(req.Auth); a.Type != model.AuthInherit {
+		auth, authOrigin = a, self
+	}
+	if req.Timeout > 0 {
+		timeout, timeoutOrigin = req.Timeout, self
+	}
+	if req.RetryCount > 0 {
+		retry, retryOrigin = req.RetryCount, self
+	}
+
+	// 只有相对地址（以 / 开头）或空地址拼接 BaseURL
+	if baseURL != "" && (req.Path == "" || strings.HasPrefix(req.Path, "/")) {
+		req.Path = strings.TrimRight(baseURL, "/") + req.Path
+		resolved.URL.Origin = baseOrigin
+	}
+	resolved.URL.Value = req.Path
+
+	kvs := make([]model.KeyValue, 0, len(headers))
+	for _, h := range headers {
+		kvs = append(kvs, h.KeyValue)
+	}
+	req.Headers = model.EncodeKeyValues(kvs)
+	req.Auth = model.EncodeAuth(&auth)
+	req.Timeout, req.RetryCount = timeout, retry
+
+	if headers == nil {
+		headers = []ResolvedHeader{}
+	}
+	resolved.Request = req
+	resolved.Headers = headers
+	resolved.Auth = ResolvedValue{Value: auth, Origin: authOrigin}
+	resolved.Timeout = ResolvedValue{Value: timeout, Origin: timeoutOrigin}
+	resolved.RetryCount = ResolvedValue{Value: retry, Origin: retryOrigin}
+	return resolved
+}
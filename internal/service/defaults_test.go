@@ -0,0 +1,68 @@
+package service
+
+import (
+	"FastGo/internal/model"
+	"testing"
+)
+
+func TestMergeDefaults(t *testing.T) {
+	collection := defaultsLayer{
+		origin: Origin{Kind: OriginCollection, ID: "c"},
+		defaults: model.Defaults{
+			BaseURL: "https://api.example.com/",
+			Headers: model.EncodeKeyValues([]model.KeyValue{
+				{Key: "X-Team", Value: "core", Enabled: true},
+				{Key: "X-Trace", Value: "on", Enabled: true},
+			}),
+			Auth:    model.EncodeAuth(&model.Auth{Type: model.AuthBearer, Token: "{{token}}"}),
+			Timeout: 5000,
+		},
+	}
+	folder := defaultsLayer{
+		origin: Origin{Kind: OriginFolder, ID: "f"},
+		defaults: model.Defaults{
+			BaseURL:    "https://api.example.com/v2",
+			Headers:    model.EncodeKeyValues([]model.KeyValue{{Key: "x-team", Value: "platform", Enabled: true}}),
+			RetryCount: 2,
+		},
+	}
+	req := model.Request{
+		RequestID: "r",
+		Path:      "/users",
+		Headers:   model.EncodeKeyValues([]model.KeyValue{{Key: "X-Trace", Value: "on", Enabled: false}}),
+		Timeout:   1000,
+	}
+
+	resolved := mergeDefaults(req, []defaultsLayer{collection, folder})
+	if resolved.Request.Path != "https://api.example.com/v2/users" || resolved.URL.Origin.Kind != OriginFolder {
+		t.Fatalf("unexpected url %q from %+v", resolved.Request.Path, resolved.URL.Origin)
+	}
+	if len(resolved.Headers) != 2 {
+		t.Fatalf("expected 2 headers, got %+v", resolved.Headers)
+	}
+	if h := resolved.Headers[0]; h.Value != "platform" || h.Origin.Kind != OriginFolder {
+		t.Fatalf("unexpected team header %+v", h)
+	}
+	if h := resolved.Headers[1]; h.Enabled || h.Origin.Kind != OriginRequest {
+		t.Fatalf("request should disable inherited header, got %+v", h)
+	}
+	if resolved.Auth.Origin.Kind != OriginCollection || model.DecodeAuth(resolved.Request.Auth).Token != "{{token}}" {
+		t.Fatalf("unexpected auth %+v", resolved.Auth)
+	}
+	if resolved.Request.Timeout != 1000 || resolved.Timeout.Origin.Kind != OriginRequest {
+		t.Fatalf("unexpected timeout %+v", resolved.Timeout)
+	}
+	if resolved.Request.RetryCount != 2 || resolved.RetryCount.Origin.Kind != OriginFolder {
+		t.Fatalf("unexpected retry count %+v", resolved.RetryCount)
+	}
+
+	req.Path = "https://other.example.com/ping"
+	req.Auth = model.EncodeAuth(&model.Auth{Type: model.AuthNone})
+	resolved = mergeDefaults(req, []defaultsLayer{collection})
+	if resolved.Request.Path != req.Path || resolved.URL.Origin.Kind != OriginRequest {
+		t.Fatalf("absolute url should not be joined, got %q", resolved.Request.Path)
+	}
+	if model.DecodeAuth(resolved.Request.Auth).Type != model.AuthNone {
+		t.Fatalf("request auth none should stop inheritance")
+	}
+}
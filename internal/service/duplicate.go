@@ -0,0 +1,373 @@
+package service
+
+import (
+	"FastGo/internal/model"
+	"FastGo/pkg/uid"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 副本名称后缀
+const copySuffix = " (copy)"
+
+// ErrWorkspaceNotFound 要复制的工作区不存在
+var ErrWorkspaceNotFound = errors.New("workspace not found")
+
+// DuplicateRequest 在原位置复制请求及其响应示例，副本排在同级末尾，需要在事务中调用
+func DuplicateRequest(tx *gorm.DB, requestID string, authorID uint64) (*model.Request, error) {
+	var request model.Request
+	if err := tx.Where("request_id = ?", requestID).First(&request).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRequestNotFound
+		}
+		return nil, err
+	}
+
+	order, err := NextSortOrder(tx, &model.Request{}, request.CollectionID, request.FolderID)
+	if err != nil {
+		return nil, err
+	}
+	request.Name += copySuffix
+	request.SortOrder = order
+
+	rows, err := copyRequests(tx, []model.Request{request}, request.CollectionID, nil, authorID)
+	if err != nil {
+		return nil, err
+	}
+	return &rows[0], nil
+}
+
+// DuplicateFolder 在同一父目录下复制文件夹及其整个子树，包括子文件夹、闭包关系、请求和示例
+// 需要在事务中调用
+func DuplicateFolder(tx *gorm.DB, folderID string, authorID uint64) (*model.Folder, error) {
+	var root model.Folder
+	if err := tx.Where("folder_id = ?", folderID).First(&root).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFolderNotFound
+		}
+		return nil, err
+	}
+
+	var subtreeIDs []string
+	err := tx.Model(&model.FolderClosure{}).Where("ancestor = ?", folderID).Pluck("descendant", &subtreeIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(subtreeIDs) == 0 {
+		subtreeIDs = []string{folderID}
+	}
+	var folders []model.Folder
+	if err := tx.Where("folder_id IN (?)", subtreeIDs).Order("id").Find(&folders).Error; err != nil {
+		return nil, err
+	}
+
+	parentID, err := parentFolderID(tx, folderID)
+	if err != nil {
+		return nil, err
+	}
+	order, err := NextSortOrder(tx, &model.Folder{}, root.CollectionID, parentID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range folders {
+		if folders[i].FolderID == folderID {
+			folders[i].Name += copySuffix
+			folders[i].SortOrder = order
+		}
+	}
+
+	idMap, err := copyFolders(tx, folders, root.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 子树内部的关系按映射复制，再把副本根节点挂到原文件夹的祖先下
+	// 回收站中的子文件夹保留闭包关系但不在映射中，不复制
+	var closures []model.FolderClosure
+	if err := tx.Where("descendant IN (?)", subtreeIDs).Find(&closures).Error; err != nil {
+		return nil, err
+	}
+	var ancestors []model.FolderClosure
+	for _, c := range closures {
+		if c.Descendant == folderID && c.Depth > 0 {
+			ancestors = append(ancestors, c)
+		}
+	}
+	rows := remapClosures(closures, idMap)
+	for _, a := range ancestors {
+		for _, c := range closures {
+			descendant, ok := idMap[c.Descendant]
+			if c.Ancestor != folderID || !ok {
+				continue
+			}
+			rows = append(rows, model.FolderClosure{
+				Ancestor:   a.Ancestor,
+				Descendant: descendant,
+				Depth:      a.Depth + c.Depth,
+			})
+		}
+	}
+	if len(rows) > 0 {
+		if err := tx.CreateInBatches(&rows, 500).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	var requests []model.Request
+	if err := tx.Where("folder_id IN (?)", subtreeIDs).Order("id").Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	if _, err := copyRequests(tx, requests, root.CollectionID, idMap, authorID); err != nil {
+		return nil, err
+	}
+
+	copied := root
+	for _, f := range folders {
+		if f.FolderID == idMap[folderID] {
+			copied = f
+		}
+	}
+	return &copied, nil
+}
+
+// DuplicateCollection 在原工作区中复制集合，包括文件夹树、请求、示例、集合级环境和 proto 描述
+// 需要在事务中调用
+func DuplicateCollection(tx *gorm.DB, collectionID string, ownerID uint64) (*model.Collections, error) {
+	var collection model.Collections
+	if err := tx.Where("collection_id = ?", collectionID).First(&collection).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCollectionNotFound
+		}
+		return nil, err
+	}
+	collection.Name += copySuffix
+	copied, _, err := copyCollection(tx, collection, collection.WorkspaceID, ownerID)
+	return copied, err
+}
+
+// DuplicateWorkspace 复制工作区及其下全部集合和工作区级环境，副本归属 ownerID，需要在事务中调用
+func DuplicateWorkspace(tx *gorm.DB, workspaceID, ownerID uint64) (*model.Workspace, error) {
+	var workspace model.Workspace
+	if err := tx.Where("id = ?", workspaceID).First(&workspace).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWorkspaceNotFound
+		}
+		return nil, err
+	}
+
+	copied := workspace
+	copied.ID = 0
+	copied.Name += copySuffix
+	copied.OwnerID = ownerID
+	copied.CreatedAt, copied.UpdatedAt = time.Time{}, time.Time{}
+	if err := tx.Create(&copied).Error; err != nil {
+		return nil, err
+	}
+
+	var collections []model.Collections
+	if err := tx.Where("workspace_id = ?", workspaceID).Order("id").Find(&collections).Error; err != nil {
+		return nil, err
+	}
+	for _, c := range collections {
+		if _, _, err := copyCollection(tx, c, copied.ID, ownerID); err != nil {
+			return nil, err
+		}
+	}
+
+	var environments []model.Environment
+	err := tx.Where("workspace_id = ? AND (collection_id = '' OR collection_id IS NULL)", workspaceID).
+		Order("id").Find(&environments).Error
+	if err != nil {
+		return nil, err
+	}
+	if err := copyEnvironments(tx, environments, copied.ID, ""); err != nil {
+		return nil, err
+	}
+	return &copied, nil
+}
+
+// copyCollection 将集合及其内容复制到指定工作区，名称由调用方决定
+// 返回原文件夹、请求 ID 到副本 ID 的映射
+func copyCollection(tx *gorm.DB, collection model.Collections, workspaceID, ownerID uint64) (*model.Collections, map[string]string, error) {
+	sourceID := collection.CollectionID
+
+	copied := collection
+	copied.ID = 0
+	copied.CollectionID = uid.NewUUID()
+	copied.WorkspaceID = workspaceID
+	copied.OwnerID = ownerID
+	copied.MembersCount = 1
+	copied.CreatedAt, copied.UpdatedAt = time.Time{}, time.Time{}
+	if err := tx.Create(&copied).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var folders []model.Folder
+	if err := tx.Where("collection_id = ?", sourceID).Order("id").Find(&folders).Error; err != nil {
+		return nil, nil, err
+	}
+	idMap, err := copyFolders(tx, folders, copied.CollectionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(idMap) > 0 {
+		folderIDs := make([]string, 0, len(idMap))
+		for id := range idMap {
+			folderIDs = append(folderIDs, id)
+		}
+		var closures []model.FolderClosure
+		if err := tx.Where("descendant IN (?)", folderIDs).Find(&closures).Error; err != nil {
+			return nil, nil, err
+		}
+		if rows := remapClosures(closures, idMap); len(rows) > 0 {
+			if err := tx.CreateInBatches(&rows, 500).Error; err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	var requests []model.Request
+	if err := tx.Where("collection_id = ?", sourceID).Order("id").Find(&requests).Error; err != nil {
+		return nil, nil, err
+	}
+	requestIDs := make([]string, 0, len(requests))
+	for _, r := range requests {
+		requestIDs = append(requestIDs, r.RequestID)
+	}
+	copiedRequests, err := copyRequests(tx, requests, copied.CollectionID, idMap, ownerID)
+	if err != nil {
+		return nil, nil, err
+	}
+	links := make(map[string]string, len(idMap)+len(copiedRequests))
+	for source, target := range idMap {
+		links[source] = target
+	}
+	for i, r := range copiedRequests {
+		links[requestIDs[i]] = r.RequestID
+	}
+
+	var environments []model.Environment
+	if err := tx.Where("collection_id = ?", sourceID).Order("id").Find(&environments).Error; err != nil {
+		return nil, nil, err
+	}
+	if err := copyEnvironments(tx, environments, workspaceID, copied.CollectionID); err != nil {
+		return nil, nil, err
+	}
+
+	var schemas []model.ProtoSchema
+	if err := tx.Where("collection_id = ?", sourceID).Order("id").Find(&schemas).Error; err != nil {
+		return nil, nil, err
+	}
+	for i := range schemas {
+		schemas[i].ID = 0
+		schemas[i].SchemaID = uid.NewUUID()
+		schemas[i].CollectionID = copied.CollectionID
+		schemas[i].CreatedAt, schemas[i].UpdatedAt = time.Time{}, time.Time{}
+	}
+	if len(schemas) > 0 {
+		if err := tx.Create(&schemas).Error; err != nil {
+			return nil, nil, err
+		}
+	}
+	return &copied, links, nil
+}
+
+// copyFolders 以新的 FolderID 写入文件夹副本，返回原 FolderID 到新 FolderID 的映射
+func copyFolders(tx *gorm.DB, folders []model.Folder, collectionID string) (map[string]string, error) {
+	idMap := make(map[string]string, len(folders))
+	for i := range folders {
+		newID := uid.NewUUID()
+		idMap[folders[i].FolderID] = newID
+		folders[i].ID = 0
+		folders[i].FolderID = newID
+		folders[i].CollectionID = collectionID
+		folders[i].CreatedAt, folders[i].UpdatedAt = time.Time{}, time.Time{}
+	}
+	if len(folders) > 0 {
+		if err := tx.CreateInBatches(&folders, 500).Error; err != nil {
+			return nil, err
+		}
+	}
+	return idMap, nil
+}
+
+// remapClosures 复制两端都在映射中的闭包关系
+func remapClosures(closures []model.FolderClosure, idMap map[string]string) []model.FolderClosure {
+	rows := make([]model.FolderClosure, 0, len(closures))
+	for _, c := range closures {
+		ancestor, ok := idMap[c.Ancestor]
+		if !ok {
+			continue
+		}
+		descendant, ok := idMap[c.Descendant]
+		if !ok {
+			continue
+		}
+		rows = append(rows, model.FolderClosure{Ancestor: ancestor, Descendant: descendant, Depth: c.Depth})
+	}
+	return rows
+}
+
+// copyRequests 以新的 RequestID 写入请求副本并复制响应示例，FolderID 按 folderMap 转换
+// 副本不保留导入来源标识，避免重复导入时覆盖副本，修订历史从副本的首个修订开始
+func copyRequests(tx *gorm.DB, requests []model.Request, collectionID string, folderMap map[string]string, authorID uint64) ([]model.Request, error) {
+	if len(requests) == 0 {
+		return requests, nil
+	}
+	idMap := make(map[string]string, len(requests))
+	sourceIDs := make([]string, 0, len(requests))
+	for i := range requests {
+		r := &requests[i]
+		newID := uid.NewUUID()
+		idMap[r.RequestID] = newID
+		sourceIDs = append(sourceIDs, r.RequestID)
+		if folderID, ok := folderMap[r.FolderID]; ok {
+			r.FolderID = folderID
+		}
+		r.ID = 0
+		r.RequestID = newID
+		r.CollectionID = collectionID
+		r.SourceKey = ""
+		r.CreatedAt, r.UpdatedAt = time.Time{}, time.Time{}
+	}
+	if err := tx.CreateInBatches(&requests, 500).Error; err != nil {
+		return nil, err
+	}
+	if err := recordCreated(tx, requests, authorID); err != nil {
+		return nil, err
+	}
+
+	var examples []model.RequestExample
+	if err := tx.Where("request_id IN (?)", sourceIDs).Order("id").Find(&examples).Error; err != nil {
+		return nil, err
+	}
+	for i := range examples {
+		examples[i].ID = 0
+		examples[i].ExampleID = uid.NewUUID()
+		examples[i].RequestID = idMap[examples[i].RequestID]
+		examples[i].CreatedAt, examples[i].UpdatedAt = time.Time{}, time.Time{}
+	}
+	if len(examples) > 0 {
+		if err := tx.CreateInBatches(&examples, 500).Error; err != nil {
+			return nil, err
+		}
+	}
+	return requests, nil
+}
+
+// copyEnvironments 复制环境及其变量到指定工作区和集合
+func copyEnvironments(tx *gorm.DB, environments []model.Environment, workspaceID uint64, collectionID string) error {
+	if len(environments) == 0 {
+		return nil
+	}
+	for i := range environments {
+		environments[i].ID = 0
+		environments[i].EnvironmentID = uid.NewUUID()
+		environments[i].WorkspaceID = workspaceID
+		environments[i].CollectionID = collectionID
+		environments[i].CreatedAt, environments[i].UpdatedAt = time.Time{}, time.Time{}
+	}
+	return tx.Create(&environments).Error
+}
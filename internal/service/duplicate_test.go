@@ -0,0 +1,285 @@
+package service
+
+import (
+	"FastGo/internal/model"
+	"FastGo/internal/service/servicetest"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestRemapClosures(t *testing.T) {
+	closures := []model.FolderClosure{
+		{Ancestor: "root", Descendant: "a", Depth: 1},
+		{Ancestor: "a", Descendant: "a", Depth: 0},
+		{Ancestor: "a", Descendant: "b", Depth: 1},
+		{Ancestor: "b", Descendant: "b", Depth: 0},
+	}
+	rows := remapClosures(closures, map[string]string{"a": "a2", "b": "b2"})
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(rows))
+	}
+	for _, r := range rows {
+		if r.Ancestor == "root" || r.Ancestor == "a" || r.Descendant == "b" {
+			t.Fatalf("unexpected row %+v", r)
+		}
+	}
+	if rows[1] != (model.FolderClosure{Ancestor: "a2", Descendant: "b2", Depth: 1}) {
+		t.Fatalf("unexpected edge %+v", rows[1])
+	}
+}
+
+// seedCollection 在新工作区中创建集合，结构如下，括号内为排序值
+//
+//	a(0)
+//	  a1(0)
+//	    a1x(0)
+//	    r1(0) r2(1)
+//	  a2(1)
+//	b(1)
+//	r3(0)
+func seedCollection(t *testing.T, db *gorm.DB) *model.Workspace {
+	t.Helper()
+	workspace := &model.Workspace{Name: "Team", OwnerID: 1}
+	must(t, db.Create(workspace).Error)
+	must(t, db.Create(&model.Collections{Name: "API", OwnerID: 1, WorkspaceID: workspace.ID, CollectionID: "c"}).Error)
+
+	folder := func(name, parent string, order int) {
+		f := &model.Folder{Name: name, CollectionID: "c", FolderID: name}
+		must(t, CreateFolder(db, f, parent))
+		// CreateFolder 把 0 视为未设置，这里统一写回期望的排序值
+		must(t, db.Model(f).Update("sort_order", order).Error)
+	}
+	folder("a", "", 0)
+	folder("b", "", 1)
+	folder("a1", "a", 0)
+	folder("a2", "a", 1)
+	folder("a1x", "a1", 0)
+
+	requests := []model.Request{
+		{Name: "r1", CollectionID: "c", FolderID: "a1", SortOrder: 0, RequestID: "r1", Method: "GET", Path: "/users"},
+		{Name: "r2", CollectionID: "c", FolderID: "a1", SortOrder: 1, RequestID: "r2", Method: "POST", Path: "/users"},
+		{Name: "r3", CollectionID: "c", FolderID: "", SortOrder: 0, RequestID: "r3", Method: "GET", Path: "/health"},
+	}
+	must(t, db.Create(&requests).Error)
+	must(t, db.Create(&model.RequestExample{ExampleID: "e1", RequestID: "r1", Name: "ok", Status: 200}).Error)
+	must(t, db.Create(&[]model.Environment{
+		{EnvironmentID: "env-c", WorkspaceID: workspace.ID, CollectionID: "c", Name: "dev"},
+		{EnvironmentID: "env-w", WorkspaceID: workspace.ID, Name: "shared"},
+	}).Error)
+	return workspace
+}
+
+func must(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func findFolder(t *testing.T, db *gorm.DB, collectionID, name string) model.Folder {
+	t.Helper()
+	var f model.Folder
+	must(t, db.Where("collection_id = ? AND name = ?", collectionID, name).First(&f).Error)
+	return f
+}
+
+func findRequest(t *testing.T, db *gorm.DB, collectionID, name string) model.Request {
+	t.Helper()
+	var r model.Request
+	must(t, db.Where("collection_id = ? AND name = ?", collectionID, name).First(&r).Error)
+	return r
+}
+
+func closureDepth(t *testing.T, db *gorm.DB, ancestor, descendant string) int {
+	t.Helper()
+	var rows []model.FolderClosure
+	must(t, db.Where("ancestor = ? AND descendant = ?", ancestor, descendant).Find(&rows).Error)
+	if len(rows) != 1 {
+		t.Fatalf("expected one closure %s -> %s, got %+v", ancestor, descendant, rows)
+	}
+	return rows[0].Depth
+}
+
+func count(t *testing.T, db *gorm.DB, table interface{}, query string, args ...interface{}) int64 {
+	t.Helper()
+	var n int64
+	must(t, db.Model(table).Where(query, args...).Count(&n).Error)
+	return n
+}
+
+func TestDuplicateRequest(t *testing.T) {
+	db := servicetest.NewDB(t)
+	seedCollection(t, db)
+
+	copied, err := DuplicateRequest(db, "r1", 7)
+	must(t, err)
+	if copied.RequestID == "r1" || copied.Name != "r1"+copySuffix || copied.FolderID != "a1" || copied.SortOrder != 2 {
+		t.Fatalf("unexpected copy %+v", copied)
+	}
+	if n := count(t, db, &model.RequestExample{}, "request_id = ?", copied.RequestID); n != 1 {
+		t.Errorf("expected the example to be copied, got %d", n)
+	}
+	if n := count(t, db, &model.RequestExample{}, "request_id = ?", "r1"); n != 1 {
+		t.Errorf("expected the original example to be kept, got %d", n)
+	}
+	if n := count(t, db, &model.RequestRevision{}, "request_id = ? AND version = 1", copied.RequestID); n != 1 {
+		t.Errorf("expected a first revision for the copy, got %d", n)
+	}
+
+	if _, err := DuplicateRequest(db, "missing", 7); err != ErrRequestNotFound {
+		t.Errorf("expected ErrRequestNotFound, got %v", err)
+	}
+}
+
+func TestDuplicateFolder(t *testing.T) {
+	db := servicetest.NewDB(t)
+	seedCollection(t, db)
+
+	copied, err := DuplicateFolder(db, "a1", 7)
+	must(t, err)
+	if copied.FolderID == "a1" || copied.Name != "a1"+copySuffix || copied.SortOrder != 2 {
+		t.Fatalf("unexpected copy %+v", copied)
+	}
+	// 副本挂在原父文件夹下，子树按原有层级复制
+	if parent, err := parentFolderID(db, copied.FolderID); err != nil || parent != "a" {
+		t.Fatalf("expected copy under a, got %q %v", parent, err)
+	}
+	child := findFolder(t, db, "c", "a1x")
+	var childCopy model.Folder
+	must(t, db.Where("name = ? AND folder_id <> ?", "a1x", child.FolderID).First(&childCopy).Error)
+	if childCopy.SortOrder != 0 {
+		t.Errorf("expected child sort order to be kept, got %d", childCopy.SortOrder)
+	}
+	if d := closureDepth(t, db, copied.FolderID, childCopy.FolderID); d != 1 {
+		t.Errorf("expected depth 1, got %d", d)
+	}
+	if d := closureDepth(t, db, "a", childCopy.FolderID); d != 2 {
+		t.Errorf("expected depth 2, got %d", d)
+	}
+	if d := closureDepth(t, db, copied.FolderID, copied.FolderID); d != 0 {
+		t.Errorf("expected self closure, got %d", d)
+	}
+
+	var requests []model.Request
+	must(t, db.Where("folder_id = ?", copied.FolderID).Order("sort_order").Find(&requests).Error)
+	if len(requests) != 2 || requests[0].Name != "r1" || requests[1].Name != "r2" || requests[1].SortOrder != 1 {
+		t.Fatalf("unexpected copied requests %+v", requests)
+	}
+	if requests[0].RequestID == "r1" || requests[1].RequestID == "r2" {
+		t.Errorf("expected fresh request IDs, got %+v", requests)
+	}
+	if n := count(t, db, &model.RequestExample{}, "request_id = ?", requests[0].RequestID); n != 1 {
+		t.Errorf("expected the example to be copied, got %d", n)
+	}
+	// 原子树不受影响
+	if n := count(t, db, &model.Request{}, "folder_id = ?", "a1"); n != 2 {
+		t.Errorf("expected original requests to be kept, got %d", n)
+	}
+}
+
+// 回收站中的子文件夹保留闭包关系，复制时不能写出空的后代
+func TestDuplicateFolderSkipsTrashedSubfolders(t *testing.T) {
+	db := servicetest.NewDB(t)
+	seedCollection(t, db)
+	_, err := DeleteFolder(db, "a1x", false, 1)
+	must(t, err)
+
+	copied, err := DuplicateFolder(db, "a1", 7)
+	must(t, err)
+	if n := count(t, db, &model.FolderClosure{}, "descendant = ''"); n != 0 {
+		t.Fatalf("expected no empty closures, got %d", n)
+	}
+	if n := count(t, db, &model.Folder{}, "name = ?", "a1x"); n != 0 {
+		t.Errorf("expected the trashed folder not to be copied, got %d", n)
+	}
+	// 副本只有自身的闭包和挂到 a 下的一条闭包
+	if n := count(t, db, &model.FolderClosure{}, "descendant = ?", copied.FolderID); n != 2 {
+		t.Errorf("expected 2 closures for the copy, got %d", n)
+	}
+	if d := closureDepth(t, db, "a", copied.FolderID); d != 1 {
+		t.Errorf("expected depth 1, got %d", d)
+	}
+}
+
+func TestDuplicateCollection(t *testing.T) {
+	db := servicetest.NewDB(t)
+	workspace := seedCollection(t, db)
+
+	copied, err := DuplicateCollection(db, "c", 7)
+	must(t, err)
+	if copied.CollectionID == "c" || copied.Name != "API"+copySuffix || copied.WorkspaceID != workspace.ID || copied.OwnerID != 7 {
+		t.Fatalf("unexpected copy %+v", copied)
+	}
+	cid := copied.CollectionID
+
+	if n := count(t, db, &model.Folder{}, "collection_id = ?", cid); n != 5 {
+		t.Fatalf("expected 5 folders, got %d", n)
+	}
+	for name, order := range map[string]int{"a": 0, "b": 1, "a1": 0, "a2": 1, "a1x": 0} {
+		f := findFolder(t, db, cid, name)
+		if f.FolderID == name {
+			t.Errorf("expected a fresh ID for %s", name)
+		}
+		if f.SortOrder != order {
+			t.Errorf("expected %s sort order %d, got %d", name, order, f.SortOrder)
+		}
+	}
+	a, a1, a1x := findFolder(t, db, cid, "a"), findFolder(t, db, cid, "a1"), findFolder(t, db, cid, "a1x")
+	if d := closureDepth(t, db, a.FolderID, a1x.FolderID); d != 2 {
+		t.Errorf("expected depth 2, got %d", d)
+	}
+	if parent, err := parentFolderID(db, a1.FolderID); err != nil || parent != a.FolderID {
+		t.Errorf("expected a1 under a, got %q %v", parent, err)
+	}
+	if parent, err := parentFolderID(db, a.FolderID); err != nil || parent != "" {
+		t.Errorf("expected a at the root, got %q %v", parent, err)
+	}
+
+	r1, r3 := findRequest(t, db, cid, "r1"), findRequest(t, db, cid, "r3")
+	if r1.RequestID == "r1" || r1.FolderID != a1.FolderID {
+		t.Errorf("unexpected copied request %+v", r1)
+	}
+	if r3.FolderID != "" {
+		t.Errorf("expected r3 at the root, got %q", r3.FolderID)
+	}
+	if n := count(t, db, &model.RequestExample{}, "request_id = ?", r1.RequestID); n != 1 {
+		t.Errorf("expected the example to be copied, got %d", n)
+	}
+	if n := count(t, db, &model.Environment{}, "collection_id = ?", cid); n != 1 {
+		t.Errorf("expected the collection environment to be copied, got %d", n)
+	}
+}
+
+func TestDuplicateWorkspace(t *testing.T) {
+	db := servicetest.NewDB(t)
+	workspace := seedCollection(t, db)
+
+	copied, err := DuplicateWorkspace(db, workspace.ID, 7)
+	must(t, err)
+	if copied.ID == workspace.ID || copied.Name != "Team"+copySuffix || copied.OwnerID != 7 {
+		t.Fatalf("unexpected copy %+v", copied)
+	}
+	var collections []model.Collections
+	must(t, db.Where("workspace_id = ?", copied.ID).Find(&collections).Error)
+	if len(collections) != 1 || collections[0].CollectionID == "c" || collections[0].Name != "API" {
+		t.Fatalf("unexpected copied collections %+v", collections)
+	}
+	cid := collections[0].CollectionID
+	if n := count(t, db, &model.Folder{}, "collection_id = ?", cid); n != 5 {
+		t.Errorf("expected 5 folders, got %d", n)
+	}
+	if n := count(t, db, &model.Request{}, "collection_id = ?", cid); n != 3 {
+		t.Errorf("expected 3 requests, got %d", n)
+	}
+	if n := count(t, db, &model.Environment{}, "workspace_id = ? AND collection_id = ''", copied.ID); n != 1 {
+		t.Errorf("expected the workspace environment to be copied, got %d", n)
+	}
+	if n := count(t, db, &model.Environment{}, "workspace_id = ? AND collection_id = ?", copied.ID, cid); n != 1 {
+		t.Errorf("expected the collection environment to be copied, got %d", n)
+	}
+
+	if _, err := DuplicateWorkspace(db, 999, 7); err != ErrWorkspaceNotFound {
+		t.Errorf("expected ErrWorkspaceNotFound, got %v", err)
+	}
+}
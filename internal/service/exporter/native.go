@@ -0,0 +1,240 @@
+package exporter
+
+import (
+	"FastGo/internal/model"
+	"FastGo/internal/service"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 原生导出格式标识与版本，格式变化时递增版本号
+const (
+	NativeFormat  = "rpc-master"
+	NativeVersion = 2 // 2: 请求认证、集合和文件夹的请求默认值、标签、排序值
+)
+
+// NativeDocument 原生导出文档，可无损导回
+type NativeDocument struct {
+	Format     string           `json:"format"`
+	Version    int              `json:"version"`
+	ExportedAt time.Time        `json:"exported_at"`
+	Collection NativeCollection `json:"collection"`
+}
+
+// NativeCollection 集合
+type NativeCollection struct {
+	CollectionID string              `json:"collection_id"`
+	Name         string              `json:"name"`
+	Description  string              `json:"description"`
+	Protocol     string              `json:"protocol"`
+	Defaults     *NativeDefaults     `json:"defaults,omitempty"`
+	Tags         []string            `json:"tags,omitempty"`
+	Folders      []NativeFolder      `json:"folders"`
+	Requests     []NativeRequest     `json:"requests"`
+	Environments []NativeEnvironment `json:"environments"`
+}
+
+// NativeFolder 文件夹，数组顺序即展示顺序
+type NativeFolder struct {
+	FolderID  string          `json:"folder_id"`
+	Name      string          `json:"name"`
+	SortOrder *int            `json:"sort_order,omitempty"` // 版本 1 的文档中没有
+	Defaults  *NativeDefaults `json:"defaults,omitempty"`
+	Tags      []string        `json:"tags,omitempty"`
+	Folders   []NativeFolder  `json:"folders"`
+	Requests  []NativeRequest `json:"requests"`
+}
+
+// NativeRequest 请求
+type NativeRequest struct {
+	RequestID   string           `json:"request_id"`
+	Name        string           `json:"name"`
+	SortOrder   *int             `json:"sort_order,omitempty"` // 版本 1 的文档中没有
+	Type        string           `json:"type"`
+	Method      string           `json:"method"`
+	Path        string           `json:"path"`
+	Transport   string           `json:"transport,omitempty"`
+	Headers     []model.KeyValue `json:"headers"`
+	QueryParams []model.KeyValue `json:"query_params"`
+	Auth        *model.Auth      `json:"auth,omitempty"`
+	Body        string           `json:"body"`
+	Query       string           `json:"query,omitempty"`
+	Variables   string           `json:"variables,omitempty"`
+	Assertions  string           `json:"assertions,omitempty"`
+	Timeout     int              `json:"timeout"`
+	RetryCount  int              `json:"retry_count"`
+	Priority    int              `json:"priority"`
+	Description string           `json:"description"`
+	Tags        []string         `json:"tags,omitempty"`
+	SourceKey   string           `json:"source_key,omitempty"`
+	Examples    []NativeExample  `json:"examples"`
+}
+
+// NativeDefaults 集合和文件夹的请求默认值，未设置时省略
+type NativeDefaults struct {
+	BaseURL    string           `json:"base_url,omitempty"`
+	Headers    []model.KeyValue `json:"headers,omitempty"`
+	Auth       *model.Auth      `json:"auth,omitempty"`
+	Timeout    int              `json:"timeout,omitempty"`
+	RetryCount int              `json:"retry_count,omitempty"`
+}
+
+// NativeExample 响应示例
+type NativeExample struct {
+	ExampleID   string           `json:"example_id"`
+	Name        string           `json:"name"`
+	Status      int              `json:"status"`
+	StatusText  string           `json:"status_text"`
+	ContentType string           `json:"content_type"`
+	Headers     []model.KeyValue `json:"headers"`
+	Body        string           `json:"body"`
+}
+
+// NativeEnvironment 集合环境
+type NativeEnvironment struct {
+	EnvironmentID string           `json:"environment_id"`
+	Name          string           `json:"name"`
+	Variables     []model.KeyValue `json:"variables"`
+}
+
+// ExportData 导出一个集合所需的全部数据
+type ExportData struct {
+	Tree         *service.CollectionTree
+	Examples     map[string][]model.RequestExample // RequestID -> 示例
+	Environments []model.Environment
+}
+
+// Load 加载集合树、示例和环境
+func Load(db *gorm.DB, collection model.Collections) (*ExportData, error) {
+	trees, err := service.LoadCollectionTrees(db, []model.Collections{collection})
+	if err != nil {
+		return nil, err
+	}
+	data := &ExportData{Tree: trees[0], Examples: map[string][]model.RequestExample{}}
+
+	if ids := data.Tree.RequestIDs(); len(ids) > 0 {
+		var examples []model.RequestExample
+		if err := db.Where("request_id IN (?)", ids).Order("id").Find(&examples).Error; err != nil {
+			return nil, err
+		}
+		for _, e := range examples {
+			data.Examples[e.RequestID] = append(data.Examples[e.RequestID], e)
+		}
+	}
+
+	if err := db.Where("collection_id = ?", collection.CollectionID).Order("id").Find(&data.Environments).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Native 生成原生导出文档
+func Native(data *ExportData) *NativeDocument {
+	c := data.Tree.Collection
+	doc := &NativeDocument{
+		Format:     NativeFormat,
+		Version:    NativeVersion,
+		ExportedAt: time.Now(),
+		Collection: NativeCollection{
+			CollectionID: c.CollectionID,
+			Name:         c.Name,
+			Description:  c.Description,
+			Protocol:     model.ReturnString(c.Protocol),
+			Defaults:     nativeDefaults(c.Defaults),
+			Tags:         model.DecodeTags(c.Tags),
+			Folders:      nativeFolders(data, data.Tree.Folders),
+			Requests:     nativeRequests(data, data.Tree.Requests),
+			Environments: []NativeEnvironment{},
+		},
+	}
+	for _, env := range data.Environments {
+		doc.Collection.Environments = append(doc.Collection.Environments, NativeEnvironment{
+			EnvironmentID: env.EnvironmentID,
+			Name:          env.Name,
+			Variables:     model.DecodeKeyValues(env.Variables),
+		})
+	}
+	return doc
+}
+
+func nativeFolders(data *ExportData, nodes []*service.FolderNode) []NativeFolder {
+	folders := make([]NativeFolder, 0, len(nodes))
+	for _, n := range nodes {
+		folders = append(folders, NativeFolder{
+			FolderID:  n.Folder.FolderID,
+			Name:      n.Folder.Name,
+			SortOrder: &n.Folder.SortOrder,
+			Defaults:  nativeDefaults(n.Folder.Defaults),
+			Tags:      model.DecodeTags(n.Folder.Tags),
+			Folders:   nativeFolders(data, n.Folders),
+			Requests:  nativeRequests(data, n.Requests),
+		})
+	}
+	return folders
+}
+
+// nativeAuth 解析存储的认证设置，继承时返回 nil
+func nativeAuth(s string) *model.Auth {
+	auth := model.DecodeAuth(s)
+	if auth.Type == model.AuthInherit {
+		return nil
+	}
+	return &auth
+}
+
+// nativeDefaults 转换请求默认值，全部继承时返回 nil
+func nativeDefaults(d model.Defaults) *NativeDefaults {
+	if d == (model.Defaults{}) {
+		return nil
+	}
+	return &NativeDefaults{
+		BaseURL:    d.BaseURL,
+		Headers:    model.DecodeKeyValues(d.Headers),
+		Auth:       nativeAuth(d.Auth),
+		Timeout:    d.Timeout,
+		RetryCount: d.RetryCount,
+	}
+}
+
+func nativeRequests(data *ExportData, requests []*model.Request) []NativeRequest {
+	list := make([]NativeRequest, 0, len(requests))
+	for _, r := range requests {
+		req := NativeRequest{
+			RequestID:   r.RequestID,
+			Name:        r.Name,
+			SortOrder:   &r.SortOrder,
+			Type:        string(r.Type),
+			Method:      string(r.Method),
+			Path:        r.Path,
+			Transport:   r.Transport,
+			Headers:     model.DecodeKeyValues(r.Headers),
+			QueryParams: model.DecodeKeyValues(r.QueryParams),
+			Auth:        nativeAuth(r.Auth),
+			Body:        r.Body,
+			Query:       r.Query,
+			Variables:   r.Variables,
+			Assertions:  r.Assertions,
+			Timeout:     r.Timeout,
+			RetryCount:  r.RetryCount,
+			Priority:    r.Priority,
+			Description: r.Description,
+			Tags:        model.DecodeTags(r.Tags),
+			SourceKey:   r.SourceKey,
+			Examples:    []NativeExample{},
+		}
+		for _, e := range data.Examples[r.RequestID] {
+			req.Examples = append(req.Examples, NativeExample{
+				ExampleID:   e.ExampleID,
+				Name:        e.Name,
+				Status:      e.Status,
+				StatusText:  e.StatusText,
+				ContentType: e.ContentType,
+				Headers:     model.DecodeKeyValues(e.Headers),
+				Body:        e.Body,
+			})
+		}
+		list = append(list, req)
+	}
+	return list
+}
@@ -0,0 +1,459 @@
+package exporter
+
+import (
+	"FastGo/internal/model"
+	"FastGo/internal/service"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// OpenAPIVersion 生成文档的 OpenAPI 版本
+const OpenAPIVersion = "3.1.0"
+
+// 路径中的变量：:id、{{id}}、{id}
+var openAPIPathVarRegex = regexp.MustCompile(`^(?::([A-Za-z0-9_.-]+)|\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}|\{([A-Za-z0-9_.-]+)\})$`)
+
+// 由 OpenAPI 自身描述、不作为 header 参数输出的请求头
+var openAPIReservedHeaders = map[string]bool{"accept": true, "content-type": true, "authorization": true}
+
+// OpenAPIDocument OpenAPI 3.1 文档
+type OpenAPIDocument struct {
+	OpenAPI string                                  `json:"openapi"`
+	Info    OpenAPIInfo                             `json:"info"`
+	Servers []OpenAPIServer                         `json:"servers,omitempty"`
+	Tags    []OpenAPITag                            `json:"tags,omitempty"`
+	Paths   map[string]map[string]*OpenAPIOperation `json:"paths"`
+}
+
+// OpenAPIInfo 文档信息
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// OpenAPIServer 服务地址，取自集合环境的 baseUrl 变量
+type OpenAPIServer struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// OpenAPITag 标签，对应集合的一级文件夹
+type OpenAPITag struct {
+	Name string `json:"name"`
+}
+
+// OpenAPIOperation 接口操作
+type OpenAPIOperation struct {
+	OperationID string                      `json:"operationId"`
+	Summary     string                      `json:"summary,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	Tags        []string                    `json:"tags,omitempty"`
+	Parameters  []OpenAPIParameter          `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+}
+
+// OpenAPIParameter 参数
+type OpenAPIParameter struct {
+	Name        string                 `json:"name"`
+	In          string                 `json:"in"`
+	Required    bool                   `json:"required,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Schema      map[string]interface{} `json:"schema"`
+	Example     interface{}            `json:"example,omitempty"`
+}
+
+// OpenAPIRequestBody 请求体
+type OpenAPIRequestBody struct {
+	Content map[string]*OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIResponse 响应
+type OpenAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPIMediaType 内容类型及其结构
+type OpenAPIMediaType struct {
+	Schema  map[string]interface{} `json:"schema"`
+	Example interface{}            `json:"example,omitempty"`
+}
+
+// OpenAPI 由集合生成 OpenAPI 3.1 文档
+// 仅包含 HTTP 请求；同一路径和方法出现多次时保留先出现的请求，并返回提示
+func OpenAPI(data *ExportData) (*OpenAPIDocument, []string) {
+	c := data.Tree.Collection
+	doc := &OpenAPIDocument{
+		OpenAPI: OpenAPIVersion,
+		Info:    OpenAPIInfo{Title: c.Name, Description: c.Description, Version: "1.0.0"},
+		Paths:   map[string]map[string]*OpenAPIOperation{},
+	}
+
+	for _, env := range data.Environments {
+		for _, kv := range model.DecodeKeyValues(env.Variables) {
+			if kv.Key == "baseUrl" && kv.Value != "" {
+				doc.Servers = append(doc.Servers, OpenAPIServer{URL: kv.Value, Description: env.Name})
+			}
+		}
+	}
+
+	g := &openAPIGenerator{data: data, doc: doc, operationIDs: map[string]bool{}}
+	for _, r := range data.Tree.Requests {
+		g.add(r, "")
+	}
+	for _, f := range data.Tree.Folders {
+		doc.Tags = append(doc.Tags, OpenAPITag{Name: f.Folder.Name})
+		g.addFolder(f, f.Folder.Name)
+	}
+	return doc, g.warnings
+}
+
+type openAPIGenerator struct {
+	data         *ExportData
+	doc          *OpenAPIDocument
+	operationIDs map[string]bool
+	warnings     []string
+}
+
+func (g *openAPIGenerator) addFolder(node *service.FolderNode, tag string) {
+	for _, r := range node.Requests {
+		g.add(r, tag)
+	}
+	for _, f := range node.Folders {
+		g.addFolder(f, tag)
+	}
+}
+
+func (g *openAPIGenerator) add(r *model.Request, tag string) {
+	if r.Type != "" && r.Type != model.HTTP1 {
+		return
+	}
+	method := strings.ToLower(string(r.Method))
+	if method == "" {
+		method = "get"
+	}
+
+	path, pathParams := openAPIPath(r.Path)
+	if g.doc.Paths[path] == nil {
+		g.doc.Paths[path] = map[string]*OpenAPIOperation{}
+	}
+	if _, ok := g.doc.Paths[path][method]; ok {
+		g.warnings = append(g.warnings, fmt.Sprintf("request %q skipped: %s %s already defined", r.Name, strings.ToUpper(method), path))
+		return
+	}
+
+	op := &OpenAPIOperation{
+		OperationID: g.operationID(r.Name, method, path),
+		Summary:     r.Name,
+		Description: r.Description,
+		Responses:   map[string]*OpenAPIResponse{},
+	}
+	if tag != "" {
+		op.Tags = []string{tag}
+	}
+
+	for _, name := range pathParams {
+		op.Parameters = append(op.Parameters, OpenAPIParameter{Name: name, In: "path", Required: true, Schema: map[string]interface{}{"type": "string"}})
+	}
+	for _, kv := range model.DecodeKeyValues(r.QueryParams) {
+		op.Parameters = append(op.Parameters, openAPIParameter(kv, "query"))
+	}
+	headers := model.DecodeKeyValues(r.Headers)
+	for _, kv := range headers {
+		if !openAPIReservedHeaders[strings.ToLower(kv.Key)] {
+			op.Parameters = append(op.Parameters, openAPIParameter(kv, "header"))
+		}
+	}
+
+	if r.Body != "" {
+		contentType := headerValue(headers, "Content-Type")
+		op.RequestBody = &OpenAPIRequestBody{Content: map[string]*OpenAPIMediaType{}}
+		mediaType, media := openAPIMedia(contentType, r.Body)
+		op.RequestBody.Content[mediaType] = media
+	}
+
+	for _, e := range g.data.Examples[r.RequestID] {
+		status := "default"
+		if e.Status > 0 {
+			status = strconv.Itoa(e.Status)
+		}
+		resp, ok := op.Responses[status]
+		if !ok {
+			description := e.StatusText
+			if description == "" {
+				description = e.Name
+			}
+			resp = &OpenAPIResponse{Description: description}
+			op.Responses[status] = resp
+		}
+		if e.Body == "" {
+			continue
+		}
+		if resp.Content == nil {
+			resp.Content = map[string]*OpenAPIMediaType{}
+		}
+		contentType := e.ContentType
+		if contentType == "" {
+			contentType = headerValue(model.DecodeKeyValues(e.Headers), "Content-Type")
+		}
+		mediaType, media := openAPIMedia(contentType, e.Body)
+		if existing, ok := resp.Content[mediaType]; ok {
+			existing.Schema = mergeSchema(existing.Schema, media.Schema)
+			continue
+		}
+		resp.Content[mediaType] = media
+	}
+	if len(op.Responses) == 0 {
+		op.Responses["default"] = &OpenAPIResponse{Description: "Default response"}
+	}
+
+	g.doc.Paths[path][method] = op
+}
+
+// operationID 由请求名生成唯一的 operationId
+func (g *openAPIGenerator) operationID(name, method, path string) string {
+	base := lowerCamel(name)
+	if base == "" {
+		base = lowerCamel(method + " " + path)
+	}
+	id := base
+	for i := 2; g.operationIDs[id]; i++ {
+		id = base + strconv.Itoa(i)
+	}
+	g.operationIDs[id] = true
+	return id
+}
+
+// openAPIPath 去掉地址中的协议、主机或 {{baseUrl}} 前缀及查询串，并将路径变量转换为 {name}
+func openAPIPath(raw string) (string, []string) {
+	path := raw
+	if i := strings.Index(path, "?"); i >= 0 {
+		path = path[:i]
+	}
+	if i := strings.Index(path, "://"); i >= 0 {
+		path = path[i+3:]
+		if j := strings.Index(path, "/"); j >= 0 {
+			path = path[j:]
+		} else {
+			path = ""
+		}
+	} else if strings.HasPrefix(path, "{{") {
+		if j := strings.Index(path, "}}"); j >= 0 {
+			path = path[j+2:]
+		}
+	}
+
+	var params []string
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, seg := range segments {
+		m := openAPIPathVarRegex.FindStringSubmatch(seg)
+		if m == nil {
+			continue
+		}
+		name := m[1] + m[2] + m[3]
+		segments[i] = "{" + name + "}"
+		params = append(params, name)
+	}
+	return "/" + strings.Join(segments, "/"), params
+}
+
+func openAPIParameter(kv model.KeyValue, in string) OpenAPIParameter {
+	p := OpenAPIParameter{
+		Name:        kv.Key,
+		In:          in,
+		Required:    kv.Enabled,
+		Description: kv.Description,
+		Schema:      map[string]interface{}{"type": "string"},
+	}
+	if kv.Value != "" && !strings.Contains(kv.Value, "{{") {
+		p.Example = kv.Value
+	}
+	return p
+}
+
+// openAPIMedia 根据内容类型推断结构，JSON 内容按值推断，其余按字符串处理
+func openAPIMedia(contentType, body string) (string, *OpenAPIMediaType) {
+	mediaType := strings.TrimSpace(strings.Split(contentType, ";")[0])
+	var value interface{}
+	isJSON := json.Unmarshal([]byte(body), &value) == nil
+	if mediaType == "" {
+		mediaType = "text/plain"
+		if isJSON {
+			mediaType = "application/json"
+		}
+	}
+	if isJSON && strings.Contains(mediaType, "json") {
+		return mediaType, &OpenAPIMediaType{Schema: inferSchema(value), Example: value}
+	}
+	return mediaType, &OpenAPIMediaType{Schema: map[string]interface{}{"type": "string"}, Example: body}
+}
+
+// inferSchema 由 JSON 值推断 JSON Schema
+func inferSchema(v interface{}) map[string]interface{} {
+	switch val := v.(type) {
+	case nil:
+		return map[string]interface{}{"type": "null"}
+	case bool:
+		return map[string]interface{}{"type": "boolean"}
+	case float64:
+		if val == math.Trunc(val) {
+			return map[string]interface{}{"type": "integer"}
+		}
+		return map[string]interface{}{"type": "number"}
+	case string:
+		return map[string]interface{}{"type": "string"}
+	case []interface{}:
+		var items map[string]interface{}
+		for _, item := range val {
+			items = mergeSchema(items, inferSchema(item))
+		}
+		if items == nil {
+			items = map[string]interface{}{}
+		}
+		return map[string]interface{}{"type": "array", "items": items}
+	case map[string]interface{}:
+		properties := map[string]interface{}{}
+		required := make([]string, 0, len(val))
+		for key, item := range val {
+			properties[key] = inferSchema(item)
+			required = append(required, key)
+		}
+		sort.Strings(required)
+		schema := map[string]interface{}{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	}
+	return map[string]interface{}{}
+}
+
+// mergeSchema 合并两次推断的结构：对象合并属性并取必填交集，类型不同时合并为类型列表
+func mergeSchema(a, b map[string]interface{}) map[string]interface{} {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	ta, tb := schemaTypes(a), schemaTypes(b)
+	if len(ta) == 1 && len(tb) == 1 && ta[0] == tb[0] {
+		switch ta[0] {
+		case "object":
+			pa, _ := a["properties"].(map[string]interface{})
+			pb, _ := b["properties"].(map[string]interface{})
+			properties := map[string]interface{}{}
+			for k, v := range pa {
+				properties[k] = v
+			}
+			for k, v := range pb {
+				if existing, ok := properties[k].(map[string]interface{}); ok {
+					properties[k] = mergeSchema(existing, v.(map[string]interface{}))
+				} else {
+					properties[k] = v
+				}
+			}
+			schema := map[string]interface{}{"type": "object", "properties": properties}
+			inB := map[string]bool{}
+			for _, k := range requiredList(b) {
+				inB[k] = true
+			}
+			var required []string
+			for _, k := range requiredList(a) {
+				if inB[k] {
+					required = append(required, k)
+				}
+			}
+			if len(required) > 0 {
+				schema["required"] = required
+			}
+			return schema
+		case "array":
+			ia, _ := a["items"].(map[string]interface{})
+			ib, _ := b["items"].(map[string]interface{})
+			if len(ia) == 0 {
+				return b
+			}
+			if len(ib) == 0 {
+				return a
+			}
+			return map[string]interface{}{"type": "array", "items": mergeSchema(ia, ib)}
+		}
+		return a
+	}
+
+	// integer 与 number 合并为 number
+	types := map[string]bool{}
+	for _, t := range append(ta, tb...) {
+		types[t] = true
+	}
+	if types["integer"] && types["number"] {
+		delete(types, "integer")
+	}
+	list := make([]string, 0, len(types))
+	for t := range types {
+		list = append(list, t)
+	}
+	sort.Strings(list)
+	if len(list) == 1 {
+		return map[string]interface{}{"type": list[0]}
+	}
+	return map[string]interface{}{"type": list}
+}
+
+func schemaTypes(schema map[string]interface{}) []string {
+	switch t := schema["type"].(type) {
+	case string:
+		return []string{t}
+	case []string:
+		return t
+	}
+	return nil
+}
+
+func requiredList(schema map[string]interface{}) []string {
+	list, _ := schema["required"].([]string)
+	return list
+}
+
+func headerValue(headers []model.KeyValue, key string) string {
+	for _, h := range headers {
+		if h.Enabled && strings.EqualFold(h.Key, key) {
+			return h.Value
+		}
+	}
+	return ""
+}
+
+// lowerCamel 将请求名转换为小驼峰标识，非字母数字字符作为分隔
+func lowerCamel(s string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = b.Len() > 0
+			continue
+		}
+		if r > unicode.MaxASCII {
+			continue
+		}
+		if b.Len() == 0 {
+			b.WriteRune(unicode.ToLower(r))
+		} else if upper {
+			b.WriteRune(unicode.ToUpper(r))
+		} else {
+			b.WriteRune(r)
+		}
+		upper = false
+	}
+	return b.String()
+}
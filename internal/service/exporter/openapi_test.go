@@ -0,0 +1,51 @@
+package exporter
+
+import (
+	"FastGo/internal/model"
+	"FastGo/internal/service"
+	"testing"
+)
+
+func TestOpenAPI(t *testing.T) {
+	get := &model.Request{RequestID: "r1", Name: "Get pet", Type: model.HTTP1, Method: "GET", Path: "{{baseUrl}}/pets/:petId/photos/{{photoId}}?x=1"}
+	dup := &model.Request{RequestID: "r2", Name: "Get pet again", Type: model.HTTP1, Method: "GET", Path: "https://api.example.com/pets/{petId}/photos/{photoId}"}
+	data := &ExportData{
+		Tree: &service.CollectionTree{
+			Collection: model.Collections{Name: "Pets"},
+			Folders: []*service.FolderNode{{
+				Folder:   model.Folder{FolderID: "f1", Name: "pets"},
+				Requests: []*model.Request{get, dup},
+			}},
+		},
+		Examples: map[string][]model.RequestExample{
+			"r1": {
+				{Name: "ok", Status: 200, ContentType: "application/json", Body: `{"id":1,"name":"a","tags":["x"]}`},
+				{Name: "ok2", Status: 200, ContentType: "application/json", Body: `{"id":2.5,"owner":null}`},
+			},
+		},
+	}
+
+	doc, warnings := OpenAPI(data)
+	if len(warnings) != 1 {
+		t.Fatalf("expected one duplicate warning, got %v", warnings)
+	}
+	op := doc.Paths["/pets/{petId}/photos/{photoId}"]["get"]
+	if op == nil {
+		t.Fatalf("operation missing: %+v", doc.Paths)
+	}
+	if op.OperationID != "getPet" || len(op.Parameters) != 2 || op.Parameters[0].Name != "petId" || op.Tags[0] != "pets" {
+		t.Fatalf("unexpected operation: %+v", op)
+	}
+
+	schema := op.Responses["200"].Content["application/json"].Schema
+	props := schema["properties"].(map[string]interface{})
+	if props["id"].(map[string]interface{})["type"] != "number" {
+		t.Fatalf("integer and number should merge to number: %+v", props["id"])
+	}
+	if _, ok := props["owner"]; !ok {
+		t.Fatalf("properties from all examples should be merged: %+v", props)
+	}
+	if required := schema["required"].([]string); len(required) != 1 || required[0] != "id" {
+		t.Fatalf("required should be the intersection: %v", required)
+	}
+}
@@ -0,0 +1,209 @@
+package exporter
+
+import (
+	"FastGo/internal/model"
+	"FastGo/internal/service"
+	"net/url"
+	"strings"
+)
+
+// PostmanSchema Postman v2.1 集合格式
+const PostmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// PostmanCollection Postman v2.1 集合
+type PostmanCollection struct {
+	Info     PostmanInfo       `json:"info"`
+	Item     []PostmanItem     `json:"item"`
+	Variable []PostmanVariable `json:"variable,omitempty"`
+}
+
+// PostmanInfo 集合信息
+type PostmanInfo struct {
+	PostmanID   string `json:"_postman_id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+// PostmanItem 文件夹或请求，文件夹包含 Item，请求包含 Request
+type PostmanItem struct {
+	ID       string            `json:"id,omitempty"`
+	Name     string            `json:"name"`
+	Item     []PostmanItem     `json:"item,omitempty"`
+	Request  *PostmanRequest   `json:"request,omitempty"`
+	Response []PostmanResponse `json:"response,omitempty"`
+}
+
+// PostmanRequest 请求
+type PostmanRequest struct {
+	Method      string          `json:"method"`
+	Header      []PostmanHeader `json:"header"`
+	URL         PostmanURL      `json:"url"`
+	Body        *PostmanBody    `json:"body,omitempty"`
+	Description string          `json:"description,omitempty"`
+}
+
+// PostmanHeader 请求头或查询参数
+type PostmanHeader struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Disabled    bool   `json:"disabled,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// PostmanURL 请求地址
+type PostmanURL struct {
+	Raw      string          `json:"raw"`
+	Protocol string          `json:"protocol,omitempty"`
+	Host     []string        `json:"host,omitempty"`
+	Path     []string        `json:"path,omitempty"`
+	Query    []PostmanHeader `json:"query,omitempty"`
+}
+
+// PostmanBody 请求体，GraphQL 请求使用 graphql 模式，其余使用 raw 模式
+type PostmanBody struct {
+	Mode    string                 `json:"mode"`
+	Raw     string                 `json:"raw,omitempty"`
+	GraphQL *PostmanGraphQL        `json:"graphql,omitempty"`
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+// PostmanGraphQL GraphQL 查询及变量
+type PostmanGraphQL struct {
+	Query     string `json:"query"`
+	Variables string `json:"variables"`
+}
+
+// PostmanResponse 保存的响应示例
+type PostmanResponse struct {
+	ID              string          `json:"id,omitempty"`
+	Name            string          `json:"name"`
+	OriginalRequest *PostmanRequest `json:"originalRequest,omitempty"`
+	Status          string          `json:"status"`
+	Code            int             `json:"code"`
+	Header          []PostmanHeader `json:"header"`
+	Body            string          `json:"body"`
+}
+
+// PostmanVariable 集合变量
+type PostmanVariable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Postman 生成 Postman v2.1 集合，environment 不为空时将其变量写入集合变量
+func Postman(data *ExportData, environment *model.Environment) *PostmanCollection {
+	c := data.Tree.Collection
+	col := &PostmanCollection{
+		Info: PostmanInfo{
+			PostmanID:   c.CollectionID,
+			Name:        c.Name,
+			Description: c.Description,
+			Schema:      PostmanSchema,
+		},
+		Item: postmanItems(data, data.Tree.Folders, data.Tree.Requests),
+	}
+	if environment != nil {
+		for _, kv := range model.DecodeKeyValues(environment.Variables) {
+			if kv.Enabled {
+				col.Variable = append(col.Variable, PostmanVariable{Key: kv.Key, Value: kv.Value})
+			}
+		}
+	}
+	return col
+}
+
+func postmanItems(data *ExportData, folders []*service.FolderNode, requests []*model.Request) []PostmanItem {
+	items := make([]PostmanItem, 0, len(folders)+len(requests))
+	for _, f := range folders {
+		items = append(items, PostmanItem{
+			ID:   f.Folder.FolderID,
+			Name: f.Folder.Name,
+			Item: postmanItems(data, f.Folders, f.Requests),
+		})
+	}
+	for _, r := range requests {
+		req := postmanRequest(r)
+		item := PostmanItem{ID: r.RequestID, Name: r.Name, Request: req, Response: []PostmanResponse{}}
+		for _, e := range data.Examples[r.RequestID] {
+			item.Response = append(item.Response, PostmanResponse{
+				ID:              e.ExampleID,
+				Name:            e.Name,
+				OriginalRequest: req,
+				Status:          e.StatusText,
+				Code:            e.Status,
+				Header:          postmanHeaders(model.DecodeKeyValues(e.Headers)),
+				Body:            e.Body,
+			})
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func postmanRequest(r *model.Request) *PostmanRequest {
+	headers := model.DecodeKeyValues(r.Headers)
+	req := &PostmanRequest{
+		Method:      string(r.Method),
+		Header:      postmanHeaders(headers),
+		URL:         postmanURL(r.Path, model.DecodeKeyValues(r.QueryParams)),
+		Description: r.Description,
+	}
+	if r.Type == model.GraphQL {
+		req.Body = &PostmanBody{Mode: "graphql", GraphQL: &PostmanGraphQL{Query: r.Query, Variables: r.Variables}}
+	} else if r.Body != "" {
+		req.Body = &PostmanBody{Mode: "raw", Raw: r.Body}
+		for _, h := range headers {
+			if strings.EqualFold(h.Key, "Content-Type") && strings.Contains(h.Value, "json") {
+				req.Body.Options = map[string]interface{}{"raw": map[string]string{"language": "json"}}
+			}
+		}
+	}
+	return req
+}
+
+func postmanHeaders(kvs []model.KeyValue) []PostmanHeader {
+	headers := make([]PostmanHeader, 0, len(kvs))
+	for _, kv := range kvs {
+		headers = append(headers, PostmanHeader{Key: kv.Key, Value: kv.Value, Disabled: !kv.Enabled, Description: kv.Description})
+	}
+	return headers
+}
+
+// postmanURL 拆分地址，兼容 {{baseUrl}}/path 这类以变量开头的地址
+func postmanURL(raw string, query []model.KeyValue) PostmanURL {
+	u := PostmanURL{Query: postmanHeaders(query)}
+
+	rest := raw
+	if i := strings.Index(rest, "://"); i >= 0 {
+		u.Protocol = rest[:i]
+		rest = rest[i+3:]
+	}
+	if i := strings.Index(rest, "?"); i >= 0 {
+		rest = rest[:i]
+	}
+	parts := strings.Split(rest, "/")
+	if parts[0] != "" {
+		u.Host = strings.Split(parts[0], ".")
+		if strings.HasPrefix(parts[0], "{{") {
+			u.Host = []string{parts[0]}
+		}
+	}
+	for _, p := range parts[1:] {
+		if p != "" {
+			u.Path = append(u.Path, p)
+		}
+	}
+
+	u.Raw = raw
+	var enabled []string
+	for _, kv := range query {
+		if kv.Enabled {
+			enabled = append(enabled, url.QueryEscape(kv.Key)+"="+kv.Value)
+		}
+	}
+	if len(enabled) > 0 && !strings.Contains(raw, "?") {
+		u.Raw += "?" + strings.Join(enabled, "&")
+	}
+	return u
+}
@@ -0,0 +1,86 @@
+package exporter
+
+import (
+	"FastGo/internal/model"
+	"FastGo/internal/service"
+	"reflect"
+	"testing"
+)
+
+func TestPostman(t *testing.T) {
+	create := &model.Request{
+		RequestID:   "r1",
+		Name:        "Create pet",
+		Type:        model.HTTP1,
+		Method:      "POST",
+		Path:        "{{baseUrl}}/pets/",
+		Headers:     model.EncodeKeyValues([]model.KeyValue{{Key: "Content-Type", Value: "application/json", Enabled: true}}),
+		QueryParams: model.EncodeKeyValues([]model.KeyValue{{Key: "dry run", Value: "1", Enabled: true}, {Key: "debug", Value: "1"}}),
+		Body:        `{"name":"a"}`,
+	}
+	query := &model.Request{RequestID: "r2", Name: "Query", Type: model.GraphQL, Method: "POST", Path: "https://api.example.com/graphql", Query: "{ pets { id } }", Variables: "{}"}
+	data := &ExportData{
+		Tree: &service.CollectionTree{
+			Collection: model.Collections{CollectionID: "c1", Name: "Pets", Description: "pet store"},
+			Folders: []*service.FolderNode{{
+				Folder:   model.Folder{FolderID: "f1", Name: "pets"},
+				Requests: []*model.Request{create},
+			}},
+			Requests: []*model.Request{query},
+		},
+		Examples: map[string][]model.RequestExample{
+			"r1": {{ExampleID: "e1", Name: "created", Status: 201, StatusText: "Created", Body: `{"id":1}`}},
+		},
+	}
+	environment := &model.Environment{Variables: model.EncodeKeyValues([]model.KeyValue{
+		{Key: "baseUrl", Value: "http://localhost", Enabled: true},
+		{Key: "token", Value: "x"},
+	})}
+
+	col := Postman(data, environment)
+	if col.Info.PostmanID != "c1" || col.Info.Name != "Pets" || col.Info.Schema != PostmanSchema {
+		t.Errorf("unexpected info: %+v", col.Info)
+	}
+	if len(col.Variable) != 1 || col.Variable[0].Key != "baseUrl" {
+		t.Errorf("expected only enabled variables, got %+v", col.Variable)
+	}
+	// 文件夹在前，请求在后
+	if len(col.Item) != 2 || col.Item[0].Name != "pets" || col.Item[0].Request != nil || col.Item[1].Name != "Query" {
+		t.Fatalf("unexpected items: %+v", col.Item)
+	}
+
+	item := col.Item[0].Item[0]
+	req := item.Request
+	if req.Method != "POST" || req.Body == nil || req.Body.Mode != "raw" || req.Body.Raw != create.Body || req.Body.Options == nil {
+		t.Errorf("expected a raw JSON body, got %+v", req.Body)
+	}
+	url := req.URL
+	if url.Raw != "{{baseUrl}}/pets/?dry+run=1" || !reflect.DeepEqual(url.Host, []string{"{{baseUrl}}"}) || !reflect.DeepEqual(url.Path, []string{"pets"}) {
+		t.Errorf("unexpected url: %+v", url)
+	}
+	if len(url.Query) != 2 || url.Query[0].Disabled || !url.Query[1].Disabled {
+		t.Errorf("expected disabled query params to be kept, got %+v", url.Query)
+	}
+	if len(item.Response) != 1 {
+		t.Fatalf("expected one response, got %+v", item.Response)
+	}
+	resp := item.Response[0]
+	if resp.Name != "created" || resp.Code != 201 || resp.Status != "Created" || resp.Body != `{"id":1}` || resp.OriginalRequest != req {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	gql := col.Item[1].Request
+	if gql.Body == nil || gql.Body.Mode != "graphql" || gql.Body.GraphQL.Query != query.Query {
+		t.Errorf("expected a graphql body, got %+v", gql.Body)
+	}
+	if gql.URL.Protocol != "https" || !reflect.DeepEqual(gql.URL.Host, []string{"api", "example", "com"}) {
+		t.Errorf("unexpected url: %+v", gql.URL)
+	}
+	if col.Item[1].Response == nil {
+		t.Error("expected an empty response list rather than nil")
+	}
+
+	if col := Postman(data, nil); col.Variable != nil {
+		t.Errorf("expected no variables without an environment, got %+v", col.Variable)
+	}
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"FastGo/internal/model"
+	"FastGo/pkg/uid"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// CreateFolder 创建文件夹并写入闭包表，parentID 为空表示创建在集合根目录
+func CreateFolder(tx *gorm.DB, folder *model.Folder, parentID string) error {
+	if folder.FolderID == "" {
+		folder.FolderID = uid.NewUUID()
+	}
+	if folder.SortOrder == 0 {
+		order, err := NextSortOrder(tx, &model.Folder{}, folder.CollectionID, parentID)
+		if err != nil {
+			return err
+		}
+		folder.SortOrder = order
+	}
+	if err := tx.Create(folder).Error; err != nil {
+		return err
+	}
+
+	closures := []model.FolderClosure{{
+		Ancestor:   folder.FolderID,
+		Descendant: folder.FolderID,
+		Depth:      0,
+	}}
+
+	if parentID != "" {
+		var parentClosures []model.FolderClosure
+		if err := tx.Where("descendant = ?", parentID).Find(&parentClosures).Error; err != nil {
+			return err
+		}
+		for _, pc := range parentClosures {
+			closures = append(closures, model.FolderClosure{
+				Ancestor:   pc.Ancestor,
+				Descendant: folder.FolderID,
+				Depth:      pc.Depth + 1,
+			})
+		}
+	}
+
+	return tx.Create(&closures).Error
+}
+
+// 文件夹移动错误
+var (
+	ErrFolderNotFound     = errors.New("folder not found")
+	ErrCollectionNotFound = errors.New("collection not found")
+	ErrMoveIntoDescendant = errors.New("cannot move a folder into itself or its descendant")
+	ErrParentInCollection = errors.New("parent folder does not belong to the target collection")
+)
+
+// parentFolderID 返回文件夹的上级文件夹 ID，位于集合根目录时为空
+func parentFolderID(tx *gorm.DB, folderID string) (string, error) {
+	var parents []string
+	err := tx.Model(&model.FolderClosure{}).Where("descendant = ? AND depth = 1", folderID).
+		Pluck("ancestor", &parents).Error
+	if err != nil || len(parents) == 0 {
+		return "", err
+	}
+	return parents[0], nil
+}
+
+// MoveFolder 将文件夹及其子树移动到新的父文件夹下，parentID 为空表示移动到集合根目录
+// collectionID 为空时使用父文件夹所在集合，跨集合移动时同步更新子树中文件夹和请求的 CollectionID
+// 移动后排在同级末尾，需要在事务中调用
+// 请求快照只记录所在文件夹和集合，同一集合内移动文件夹不改变其中的请求，跨集合移动时为子树中的请求记录移动修订
+func MoveFolder(tx *gorm.DB, folderID, parentID, collectionID string, authorID uint64) error {
+	var folder model.Folder
+	if err := tx.Where("folder_id = ?", folderID).First(&folder).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrFolderNotFound
+		}
+		return err
+	}
+
+	if parentID != "" {
+		var parent model.Folder
+		if err := tx.Where("folder_id = ?", parentID).First(&parent).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrFolderNotFound
+			}
+			return err
+		}
+		if collectionID != "" && collectionID != parent.CollectionID {
+			return ErrParentInCollection
+		}
+		collectionID = parent.CollectionID
+	}
+	if collectionID == "" {
+		collectionID = folder.CollectionID
+	}
+	if collectionID != folder.CollectionID {
+		var count int64
+		if err := tx.Model(&model.Collections{}).Where("collection_id = ?", collectionID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrCollectionNotFound
+		}
+	}
+
+	// 子树内部的闭包关系保持不变
+	var subtree []model.FolderClosure
+	if err := tx.Where("ancestor = ?", folderID).Find(&subtree).Error; err != nil {
+		return err
+	}
+	subtreeIDs := make([]string, 0, len(subtree))
+	for _, c := range subtree {
+		if c.Descendant == parentID {
+			return ErrMoveIntoDescendant
+		}
+		subtreeIDs = append(subtreeIDs, c.Descendant)
+	}
+	if len(subtreeIDs) == 0 {
+		subtreeIDs = append(subtreeIDs, folderID)
+		subtree = append(subtree, model.FolderClosure{Ancestor: folderID, Descendant: folderID})
+	}
+
+	// 断开子树与原祖先的关系
+	err := tx.Where("descendant IN (?) AND ancestor NOT IN (?)", subtreeIDs, subtreeIDs).
+		Delete(&model.FolderClosure{}).Error
+	if err != nil {
+		return err
+	}
+
+	// 新父文件夹的每个祖先与子树的每个节点建立关系
+	if parentID != "" {
+		var parentClosures []model.FolderClosure
+		if err := tx.Where("descendant = ?", parentID).Find(&parentClosures).Error; err != nil {
+			return err
+		}
+		closures := make([]model.FolderClosure, 0, len(parentClosures)*len(subtree))
+		for _, pc := range parentClosures {
+			for _, sc := range subtree {
+				closures = append(closures, model.FolderClosure{
+					Ancestor:   pc.Ancestor,
+					Descendant: sc.Descendant,
+					Depth:      pc.Depth + sc.Depth + 1,
+				})
+			}
+		}
+		if len(closures) > 0 {
+			if err := tx.CreateInBatches(&closures, 500).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	// 移动后排在新父目录下同级文件夹的末尾
+	order, err := NextSortOrder(tx, &model.Folder{}, collectionID, parentID)
+	if err != nil {
+		return err
+	}
+	if err := tx.Model(&model.Folder{}).Where("folder_id = ?", folderID).Update("sort_order", order).Error; err != nil {
+		return err
+	}
+
+	// 回收站中的子文件夹和请求一起更新，恢复时仍在正确的集合中
+	if collectionID != folder.CollectionID {
+		err := tx.Unscoped().Model(&model.Folder{}).Where("folder_id IN (?)", subtreeIDs).
+			Update("collection_id", collectionID).Error
+		if err != nil {
+			return err
+		}
+		var requests []model.Request
+		if err := tx.Unscoped().Where("folder_id IN (?)", subtreeIDs).Find(&requests).Error; err != nil {
+			return err
+		}
+		err = tx.Unscoped().Model(&model.Request{}).Where("folder_id IN (?)", subtreeIDs).
+			Update("collection_id", collectionID).Error
+		if err != nil {
+			return err
+		}
+		for i := range requests {
+			requests[i].CollectionID = collectionID
+		}
+		if err := recordMoved(tx, requests, authorID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,447 @@
+package service
+
+import (
+	"FastGo/internal/model"
+	"FastGo/pkg/uid"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 分支与合并请求错误
+var (
+	ErrForkNotFound  = errors.New("fork not found")
+	ErrMergeNotFound = errors.New("merge request not found")
+	ErrMergeNotOpen  = errors.New("merge request is not open")
+)
+
+// LoadCollectionState 读取集合当前的文件夹和请求，条目 ID 为 FolderID 和 RequestID
+func LoadCollectionState(db *gorm.DB, collectionID string) (*CollectionState, error) {
+	var collections []model.Collections
+	if err := db.Where("collection_id = ?", collectionID).Find(&collections).Error; err != nil {
+		return nil, err
+	}
+	if len(collections) == 0 {
+		return nil, ErrCollectionNotFound
+	}
+	trees, err := LoadCollectionTrees(db, collections)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &CollectionState{Folders: map[string]ForkFolder{}, Requests: map[string]RequestSnapshot{}}
+	var walk func(parent string, nodes []*FolderNode, requests []*model.Request)
+	walk = func(parent string, nodes []*FolderNode, requests []*model.Request) {
+		for _, n := range nodes {
+			state.Folders[n.Folder.FolderID] = ForkFolder{
+				Name:     n.Folder.Name,
+				Parent:   parent,
+				Defaults: n.Folder.Defaults,
+				Tags:     n.Folder.Tags,
+			}
+			walk(n.Folder.FolderID, n.Folders, n.Requests)
+		}
+		for _, r := range requests {
+			s := NewSnapshot(r)
+			s.CollectionID, s.FolderID = "", parent
+			state.Requests[r.RequestID] = s
+		}
+	}
+	walk("", trees[0].Folders, trees[0].Requests)
+	return state, nil
+}
+
+// rekey 按 keyOf 转换条目 ID 及其中引用的上级文件夹 ID
+func (s *CollectionState) rekey(keyOf func(string) string) *CollectionState {
+	out := &CollectionState{
+		Folders:  make(map[string]ForkFolder, len(s.Folders)),
+		Requests: make(map[string]RequestSnapshot, len(s.Requests)),
+	}
+	for id, f := range s.Folders {
+		if f.Parent != "" {
+			f.Parent = keyOf(f.Parent)
+		}
+		out.Folders[keyOf(id)] = f
+	}
+	for id, r := range s.Requests {
+		if r.FolderID != "" {
+			r.FolderID = keyOf(r.FolderID)
+		}
+		out.Requests[keyOf(id)] = r
+	}
+	return out
+}
+
+// ForkCollection 将集合复制到指定工作区作为分支，并记录复制时的内容作为以后合并的共同版本
+// 需要在事务中调用
+func ForkCollection(tx *gorm.DB, collectionID string, workspaceID, ownerID uint64, name string) (*model.Collections, *model.CollectionFork, error) {
+	var collection model.Collections
+	if err := tx.Where("collection_id = ?", collectionID).First(&collection).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrCollectionNotFound
+		}
+		return nil, nil, err
+	}
+	base, err := LoadCollectionState(tx, collectionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if name != "" {
+		collection.Name = name
+	}
+	copied, links, err := copyCollection(tx, collection, workspaceID, ownerID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fork := &model.CollectionFork{
+		ForkID:             uid.NewUUID(),
+		SourceCollectionID: collectionID,
+		ForkCollectionID:   copied.CollectionID,
+		OwnerID:            ownerID,
+	}
+	if err := encodeForkState(fork, links, base); err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Create(fork).Error; err != nil {
+		return nil, nil, err
+	}
+	return copied, fork, nil
+}
+
+func encodeForkState(fork *model.CollectionFork, links map[string]string, base *CollectionState) error {
+	data, err := json.Marshal(links)
+	if err != nil {
+		return err
+	}
+	fork.Links = string(data)
+	data, err = json.Marshal(base)
+	if err != nil {
+		return err
+	}
+	fork.Base = string(data)
+	return nil
+}
+
+// FindFork 查找分支
+func FindFork(db *gorm.DB, forkID string) (*model.CollectionFork, error) {
+	var fork model.CollectionFork
+	if err := db.Where("fork_id = ?", forkID).First(&fork).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrForkNotFound
+		}
+		return nil, err
+	}
+	return &fork, nil
+}
+
+// FindMergeRequest 查找合并请求
+func FindMergeRequest(db *gorm.DB, mergeID string) (*model.MergeRequest, error) {
+	var mr model.MergeRequest
+	if err := db.Where("merge_id = ?", mergeID).First(&mr).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMergeNotFound
+		}
+		return nil, err
+	}
+	return &mr, nil
+}
+
+// forkStates 分支的三个版本，分支中的条目按映射转换为源集合中的 ID
+type forkStates struct {
+	fork   *model.CollectionFork
+	links  map[string]string // 源集合 ID -> 分支 ID
+	base   *CollectionState
+	ours   *CollectionState // 按源集合 ID 转换后的分支
+	raw    *CollectionState // 分支中的原始 ID
+	theirs *CollectionState
+}
+
+func loadForkStates(db *gorm.DB, fork *model.CollectionFork) (*forkStates, error) {
+	s := &forkStates{fork: fork, links: map[string]string{}, base: &CollectionState{}}
+	if fork.Links != "" {
+		if err := json.Unmarshal([]byte(fork.Links), &s.links); err != nil {
+			return nil, err
+		}
+	}
+	if fork.Base != "" {
+		if err := json.Unmarshal([]byte(fork.Base), s.base); err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	if s.theirs, err = LoadCollectionState(db, fork.SourceCollectionID); err != nil {
+		return nil, err
+	}
+	if s.raw, err = LoadCollectionState(db, fork.ForkCollectionID); err != nil {
+		return nil, err
+	}
+	s.ours = s.raw.rekey(sourceKeys(s.links))
+	return s, nil
+}
+
+// sourceKeys 将分支中的 ID 转换为源集合中的 ID，分支中新增的条目保留分支 ID
+func sourceKeys(links map[string]string) func(string) string {
+	reverse := make(map[string]string, len(links))
+	for source, fork := range links {
+		reverse[fork] = source
+	}
+	return func(id string) string {
+		if source, ok := reverse[id]; ok {
+			return source
+		}
+		return id
+	}
+}
+
+// DiffMerge 计算合并请求当前的三方差异，源集合和分支的最新修改都会反映在结果中
+func DiffMerge(db *gorm.DB, mr *model.MergeRequest) ([]MergeChange, error) {
+	fork, err := FindFork(db, mr.ForkID)
+	if err != nil {
+		return nil, err
+	}
+	states, err := loadForkStates(db, fork)
+	if err != nil {
+		return nil, err
+	}
+	return ThreeWayMerge(states.base, states.ours, states.theirs, DecodeResolutions(mr.Resolutions)), nil
+}
+
+// DecodeResolutions 解析合并请求中保存的冲突处理方式
+func DecodeResolutions(s string) map[string]string {
+	resolutions := map[string]string{}
+	if s != "" {
+		_ = json.Unmarshal([]byte(s), &resolutions)
+	}
+	return resolutions
+}
+
+// ApplyMerge 将合并请求中分支的修改写入源集合，存在未处理的冲突时不做任何修改
+// 合并后分支当前的内容成为下一次合并的共同版本，需要在事务中调用
+func ApplyMerge(tx *gorm.DB, mergeID string, userID uint64) (*model.MergeRequest, []MergeChange, error) {
+	mr, err := FindMergeRequest(tx, mergeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if mr.Status != model.MergeOpen {
+		return nil, nil, ErrMergeNotOpen
+	}
+	fork, err := FindFork(tx, mr.ForkID)
+	if err != nil {
+		return nil, nil, err
+	}
+	states, err := loadForkStates(tx, fork)
+	if err != nil {
+		return nil, nil, err
+	}
+	changes := ThreeWayMerge(states.base, states.ours, states.theirs, DecodeResolutions(mr.Resolutions))
+	for i := range changes {
+		if changes[i].Unresolved() {
+			return nil, nil, ErrUnresolvedConflicts
+		}
+	}
+
+	created, err := applyChanges(tx, fork.SourceCollectionID, states.theirs, changes, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 新建的条目与分支中的条目建立映射，重新创建的条目替换原映射
+	for key, sourceID := range created {
+		forkID := key
+		if linked, ok := states.links[key]; ok {
+			forkID = linked
+			delete(states.links, key)
+		}
+		states.links[sourceID] = forkID
+	}
+	if err := encodeForkState(fork, states.links, states.raw.rekey(sourceKeys(states.links))); err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Save(fork).Error; err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	res := tx.Model(&model.MergeRequest{}).Where("id = ? AND status = ?", mr.ID, model.MergeOpen).
+		Updates(map[string]interface{}{"status": model.MergeMerged, "merged_by": userID, "merged_at": now})
+	if res.Error != nil {
+		return nil, nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil, ErrMergeNotOpen
+	}
+	mr.Status, mr.MergedBy, mr.MergedAt = model.MergeMerged, userID, &now
+	return mr, changes, nil
+}
+
+// applyChanges 按顺序写入：新建文件夹（上级优先）、修改文件夹、新建和修改请求、删除请求、删除文件夹
+// 删除文件夹时其中剩余的内容移动到上级目录，返回新建条目的条目 ID 到源集合中新 ID 的映射
+func applyChanges(tx *gorm.DB, collectionID string, theirs *CollectionState, changes []MergeChange, userID uint64) (map[string]string, error) {
+	created := map[string]string{}
+	deleted := map[string]bool{}
+	var folderCreates, folderUpdates, requestWrites, requestDeletes, folderDeletes []*MergeChange
+	for i := range changes {
+		c := &changes[i]
+		if !c.write {
+			continue
+		}
+		switch {
+		case !c.result.IsValid():
+			deleted[c.Key] = true
+			if c.Kind == KindFolder {
+				folderDeletes = append(folderDeletes, c)
+			} else {
+				requestDeletes = append(requestDeletes, c)
+			}
+		case c.Kind == KindFolder:
+			if _, ok := theirs.Folders[c.Key]; ok {
+				folderUpdates = append(folderUpdates, c)
+			} else {
+				folderCreates = append(folderCreates, c)
+			}
+		default:
+			requestWrites = append(requestWrites, c)
+		}
+	}
+
+	// parentID 返回上级文件夹在源集合中的 ID，上级文件夹不存在时放到集合根目录
+	parentID := func(key string) string {
+		if id, ok := created[key]; ok {
+			return id
+		}
+		if _, ok := theirs.Folders[key]; ok && !deleted[key] {
+			return key
+		}
+		return ""
+	}
+	pending := map[string]bool{}
+	for _, c := range folderCreates {
+		pending[c.Key] = true
+	}
+	for len(folderCreates) > 0 {
+		var rest []*MergeChange
+		for _, c := range folderCreates {
+			f := c.result.Interface().(ForkFolder)
+			// 上级文件夹也是新建的，等上级创建后再处理
+			if pending[f.Parent] {
+				rest = append(rest, c)
+				continue
+			}
+			folder := &model.Folder{CollectionID: collectionID, Name: f.Name, Defaults: f.Defaults, Tags: f.Tags}
+			if err := CreateFolder(tx, folder, parentID(f.Parent)); err != nil {
+				return nil, err
+			}
+			created[c.Key] = folder.FolderID
+			delete(pending, c.Key)
+		}
+		if len(rest) == len(folderCreates) {
+			// 没有进展时说明剩余的上级关系成环，全部放到根目录
+			for _, c := range rest {
+				delete(pending, c.Key)
+			}
+		}
+		folderCreates = rest
+	}
+
+	for _, c := range folderUpdates {
+		f := c.result.Interface().(ForkFolder)
+		old := theirs.Folders[c.Key]
+		updates := map[string]interface{}{}
+		if f.Name != old.Name {
+			updates["name"] = f.Name
+		}
+		if d := f.Defaults; d != old.Defaults {
+			updates["default_base_url"] = d.BaseURL
+			updates["default_headers"] = d.Headers
+			updates["default_auth"] = d.Auth
+			updates["default_timeout"] = d.Timeout
+			updates["default_retry_count"] = d.RetryCount
+		}
+		if f.Tags != old.Tags {
+			updates["tags"] = f.Tags
+		}
+		if len(updates) > 0 {
+			if err := tx.Model(&model.Folder{}).Where("folder_id = ?", c.Key).Updates(updates).Error; err != nil {
+				return nil, err
+			}
+		}
+		if f.Parent != old.Parent {
+			if err := MoveFolder(tx, c.Key, parentID(f.Parent), collectionID, userID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, c := range requestWrites {
+		s := c.result.Interface().(RequestSnapshot)
+		folderID := parentID(s.FolderID)
+		old, exists := theirs.Requests[c.Key]
+		if !exists {
+			order, err := NextSortOrder(tx, &model.Request{}, collectionID, folderID)
+			if err != nil {
+				return nil, err
+			}
+			request := model.Request{
+				RequestID:    uid.NewUUID(),
+				CollectionID: collectionID,
+				FolderID:     folderID,
+				SortOrder:    order,
+			}
+			s.ApplyContent(&request)
+			if err := tx.Create(&request).Error; err != nil {
+				return nil, err
+			}
+			if _, err := RecordRevision(tx, &request, userID, model.RevisionCreate); err != nil {
+				return nil, err
+			}
+			created[c.Key] = request.RequestID
+			continue
+		}
+
+		if s.FolderID != old.FolderID {
+			if _, err := MoveRequest(tx, c.Key, folderID, collectionID); err != nil {
+				return nil, err
+			}
+		}
+		var request model.Request
+		if err := tx.Where("request_id = ?", c.Key).First(&request).Error; err != nil {
+			return nil, err
+		}
+		moved := s.FolderID != old.FolderID
+		old.FolderID, old.CollectionID = s.FolderID, s.CollectionID
+		if reflect.DeepEqual(old, s) {
+			// 只移动了位置时记录移动修订，与其他移动请求的方式一致
+			if moved {
+				if _, err := RecordRevision(tx, &request, userID, model.RevisionMove); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		s.ApplyContent(&request)
+		if err := tx.Save(&request).Error; err != nil {
+			return nil, err
+		}
+		if _, err := RecordRevision(tx, &request, userID, model.RevisionUpdate); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, c := range requestDeletes {
+		if _, err := DeleteRequest(tx, c.Key, userID); err != nil && !errors.Is(err, ErrRequestNotFound) {
+			return nil, err
+		}
+	}
+	for _, c := range folderDeletes {
+		if _, err := DeleteFolder(tx, c.Key, true, userID); err != nil && !errors.Is(err, ErrFolderNotFound) {
+			return nil, err
+		}
+	}
+	return created, nil
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"FastGo/internal/model"
+	"FastGo/internal/service/servicetest"
+	"FastGo/pkg/uid"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// forkAndMerge 复制集合 c 作为分支，执行 edit 修改分支后创建并合并合并请求
+func forkAndMerge(t *testing.T, db *gorm.DB, edit func(forkCollectionID string)) []MergeChange {
+	t.Helper()
+	workspace := seedCollection(t, db)
+	copied, fork, err := ForkCollection(db, "c", workspace.ID, 7, "API fork")
+	must(t, err)
+	edit(copied.CollectionID)
+
+	mr := &model.MergeRequest{MergeID: uid.NewUUID(), ForkID: fork.ForkID, Title: "merge", Status: model.MergeOpen, AuthorID: 7}
+	must(t, db.Create(mr).Error)
+	_, changes, err := ApplyMerge(db, mr.MergeID, 7)
+	must(t, err)
+	return changes
+}
+
+func TestApplyMergeFolderDefaultsAndTags(t *testing.T) {
+	db := servicetest.NewDB(t)
+	defaults := model.Defaults{BaseURL: "http://localhost", Timeout: 3000}
+	tags := model.EncodeTags([]string{"users"})
+
+	forkAndMerge(t, db, func(cid string) {
+		a1 := findFolder(t, db, cid, "a1")
+		must(t, db.Model(&a1).Updates(map[string]interface{}{
+			"default_base_url": defaults.BaseURL,
+			"default_timeout":  defaults.Timeout,
+			"tags":             tags,
+		}).Error)
+		created := &model.Folder{CollectionID: cid, Name: "new", Defaults: defaults, Tags: tags}
+		must(t, CreateFolder(db, created, a1.FolderID))
+	})
+
+	a1 := findFolder(t, db, "c", "a1")
+	if a1.Defaults != defaults || a1.Tags != tags {
+		t.Errorf("expected defaults and tags to be merged, got %+v %q", a1.Defaults, a1.Tags)
+	}
+	created := findFolder(t, db, "c", "new")
+	if created.Defaults != defaults || created.Tags != tags {
+		t.Errorf("expected the new folder to keep defaults and tags, got %+v %q", created.Defaults, created.Tags)
+	}
+	if parent, err := parentFolderID(db, created.FolderID); err != nil || parent != "a1" {
+		t.Errorf("expected the new folder under a1, got %q %v", parent, err)
+	}
+}
+
+func TestApplyMergeMovesAndNestedFolders(t *testing.T) {
+	db := servicetest.NewDB(t)
+	forkAndMerge(t, db, func(cid string) {
+		b := findFolder(t, db, cid, "b")
+		r3 := findRequest(t, db, cid, "r3")
+		_, err := MoveRequest(db, r3.RequestID, b.FolderID, cid)
+		must(t, err)
+
+		p := &model.Folder{CollectionID: cid, Name: "p"}
+		must(t, CreateFolder(db, p, ""))
+		must(t, CreateFolder(db, &model.Folder{CollectionID: cid, Name: "q"}, p.FolderID))
+	})
+
+	// 只移动的请求记录移动修订
+	r3 := findRequest(t, db, "c", "r3")
+	if r3.FolderID != "b" {
+		t.Fatalf("expected r3 to be moved into b, got %q", r3.FolderID)
+	}
+	var revisions []model.RequestRevision
+	must(t, db.Where("request_id = ?", "r3").Order("version").Find(&revisions).Error)
+	if len(revisions) == 0 || revisions[len(revisions)-1].Action != model.RevisionMove {
+		t.Errorf("expected a move revision, got %+v", revisions)
+	}
+
+	// 新建的上下级文件夹保持层级
+	p, q := findFolder(t, db, "c", "p"), findFolder(t, db, "c", "q")
+	if parent, err := parentFolderID(db, q.FolderID); err != nil || parent != p.FolderID {
+		t.Errorf("expected q under p, got %q %v", parent, err)
+	}
+}
@@ -0,0 +1,408 @@
+package importer
+
+import (
+	"FastGo/internal/model"
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cast"
+)
+
+// ErrInvalidBruno 不是有效的 Bruno 集合压缩包
+var ErrInvalidBruno = errors.New("invalid Bruno archive, bruno.json not found")
+
+// 单个压缩包内文件的大小上限，防止解压炸弹
+const maxBrunoFileSize = 5 << 20
+
+// bruBlock .bru 文件中的一个块，字典块解析为 Pairs，文本块保留原文
+type bruBlock struct {
+	Name  string
+	Pairs []model.KeyValue
+	Text  string
+}
+
+// bruFile 解析后的 .bru 文件
+type bruFile struct {
+	blocks []*bruBlock
+}
+
+// block 返回指定名称的块
+func (f *bruFile) block(name string) *bruBlock {
+	for _, b := range f.blocks {
+		if b.Name == name {
+			return b
+		}
+	}
+	return nil
+}
+
+// value 返回字典块中某个键的值
+func (f *bruFile) value(block, key string) string {
+	if b := f.block(block); b != nil {
+		for _, kv := range b.Pairs {
+			if kv.Key == key {
+				return kv.Value
+			}
+		}
+	}
+	return ""
+}
+
+// parseBru 解析 .bru 文本
+// 顶层块形如 `name {` ... `}` 或 `name [` ... `]`，块内缩进两个空格，
+// 字典块每行为 `key: value`，以 ~ 开头的键表示禁用
+func parseBru(data string) *bruFile {
+	file := &bruFile{}
+	lines := strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		if line == "" || strings.HasPrefix(line, " ") {
+			continue
+		}
+
+		var closer string
+		switch {
+		case strings.HasSuffix(line, "{"):
+			closer = "}"
+		case strings.HasSuffix(line, "["):
+			closer = "]"
+		default:
+			continue
+		}
+		block := &bruBlock{Name: strings.TrimSpace(line[:len(line)-1])}
+
+		var body []string
+		for i++; i < len(lines); i++ {
+			if strings.TrimRight(lines[i], " \t") == closer {
+				break
+			}
+			body = append(body, strings.TrimPrefix(lines[i], "  "))
+		}
+		block.Text = strings.TrimSpace(strings.Join(body, "\n"))
+
+		if !isBruTextBlock(block.Name) {
+			for _, l := range body {
+				l = strings.TrimSpace(l)
+				if l == "" {
+					continue
+				}
+				kv := model.KeyValue{Enabled: true}
+				if strings.HasPrefix(l, "~") {
+					kv.Enabled = false
+					l = l[1:]
+				}
+				if closer == "]" {
+					kv.Key = strings.TrimSuffix(l, ",")
+				} else if idx := strings.Index(l, ":"); idx >= 0 {
+					kv.Key = strings.TrimSpace(l[:idx])
+					kv.Value = strings.TrimSpace(l[idx+1:])
+				} else {
+					kv.Key = l
+				}
+				block.Pairs = append(block.Pairs, kv)
+			}
+		}
+		file.blocks = append(file.blocks, block)
+	}
+	return file
+}
+
+// isBruTextBlock 请求体、文档和脚本块按原文保存
+func isBruTextBlock(name string) bool {
+	if strings.HasPrefix(name, "body:") {
+		return name != "body:form-urlencoded" && name != "body:multipart-form"
+	}
+	switch name {
+	case "docs", "script:pre-request", "script:post-response", "tests":
+		return true
+	}
+	return false
+}
+
+// brunoEntry 压缩包中的 .bru 文件
+type brunoEntry struct {
+	dir  string
+	name string
+	file *bruFile
+}
+
+// ParseBruno 解析 Bruno 集合目录的 zip 压缩包，每个包含 bruno.json 的目录对应一个集合
+func ParseBruno(data []byte) (*Workspace, []string, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, nil, ErrInvalidBruno
+	}
+
+	files := map[string]string{}
+	var roots []string
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() || f.UncompressedSize64 > maxBrunoFileSize {
+			continue
+		}
+		name := path.Clean(f.Name)
+		if base := path.Base(name); base != "bruno.json" && !strings.HasSuffix(base, ".bru") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, nil, err
+		}
+		content, err := io.ReadAll(io.LimitReader(rc, maxBrunoFileSize))
+		rc.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+		files[name] = string(content)
+		if path.Base(name) == "bruno.json" {
+			roots = append(roots, path.Dir(name))
+		}
+	}
+	if len(roots) == 0 {
+		return nil, nil, ErrInvalidBruno
+	}
+	sort.Strings(roots)
+
+	warnings := map[string]bool{}
+	ws := &Workspace{Name: "Bruno Import"}
+	for _, root := range roots {
+		ws.Collections = append(ws.Collections, brunoCollection(root, files, warnings))
+	}
+	if len(ws.Collections) == 1 {
+		ws.Name = ws.Collections[0].Name
+	}
+
+	list := make([]string, 0, len(warnings))
+	for w := range warnings {
+		list = append(list, w)
+	}
+	sort.Strings(list)
+	return ws, list, nil
+}
+
+// brunoCollection 转换单个 Bruno 集合
+func brunoCollection(root string, files map[string]string, warnings map[string]bool) *Collection {
+	var meta struct {
+		Name string `json:"name"`
+	}
+	_ = json.Unmarshal([]byte(files[path.Join(root, "bruno.json")]), &meta)
+	col := &Collection{Name: meta.Name, Protocol: model.HTTP}
+	if col.Name == "" {
+		col.Name = path.Base(root)
+	}
+
+	// 按目录归集请求，folder.bru 提供文件夹名称和顺序
+	entries := map[string][]*brunoEntry{}
+	folderMeta := map[string]*bruFile{}
+	dirs := map[string]bool{}
+	for name, content := range files {
+		rel, ok := relativeTo(root, name)
+		if !ok || !strings.HasSuffix(rel, ".bru") {
+			continue
+		}
+		dir := path.Dir(rel)
+		if dir == "." {
+			dir = ""
+		}
+		file := parseBru(content)
+
+		switch {
+		case dir == "environments":
+			col.Environments = append(col.Environments, &Environment{
+				Name:      strings.TrimSuffix(path.Base(rel), ".bru"),
+				Variables: brunoEnvironment(file),
+			})
+		case path.Base(rel) == "folder.bru":
+			folderMeta[dir] = file
+		case path.Base(rel) == "collection.bru":
+		default:
+			entries[dir] = append(entries[dir], &brunoEntry{dir: dir, name: rel, file: file})
+			for d := dir; d != ""; d = parentDir(d) {
+				dirs[d] = true
+			}
+		}
+	}
+	sort.Slice(col.Environments, func(i, j int) bool { return col.Environments[i].Name < col.Environments[j].Name })
+
+	col.Folders, col.Requests = brunoItems("", entries, folderMeta, dirs, warnings)
+	return col
+}
+
+// brunoItems 递归构建某个目录下的文件夹和请求
+func brunoItems(dir string, entries map[string][]*brunoEntry, folderMeta map[string]*bruFile, dirs map[string]bool, warnings map[string]bool) ([]*Folder, []*Request) {
+	var subdirs []string
+	for d := range dirs {
+		if parentDir(d) == dir {
+			subdirs = append(subdirs, d)
+		}
+	}
+	sort.Slice(subdirs, func(i, j int) bool {
+		si, sj := brunoSeq(folderMeta[subdirs[i]]), brunoSeq(folderMeta[subdirs[j]])
+		if si != sj {
+			return si < sj
+		}
+		return subdirs[i] < subdirs[j]
+	})
+
+	var folders []*Folder
+	for _, d := range subdirs {
+		f := &Folder{Name: path.Base(d)}
+		if m := folderMeta[d]; m != nil && m.value("meta", "name") != "" {
+			f.Name = m.value("meta", "name")
+		}
+		f.Folders, f.Requests = brunoItems(d, entries, folderMeta, dirs, warnings)
+		folders = append(folders, f)
+	}
+
+	list := entries[dir]
+	sort.Slice(list, func(i, j int) bool {
+		si, sj := brunoSeq(list[i].file), brunoSeq(list[j].file)
+		if si != sj {
+			return si < sj
+		}
+		return list[i].name < list[j].name
+	})
+	var requests []*Request
+	for _, e := range list {
+		if req := brunoRequest(e, warnings); req != nil {
+			requests = append(requests, req)
+		}
+	}
+	return folders, requests
+}
+
+// brunoRequest 转换单个 .bru 请求文件
+func brunoRequest(e *brunoEntry, warnings map[string]bool) *Request {
+	f := e.file
+	var method string
+	for _, m := range []string{"get", "post", "put", "delete", "patch", "options", "head"} {
+		if f.block(m) != nil {
+			method = m
+			break
+		}
+	}
+	if method == "" {
+		warnings["skipped file without http method: "+e.name] = true
+		return nil
+	}
+
+	req := &Request{
+		SourceKey:   "bruno:" + e.name,
+		Name:        f.value("meta", "name"),
+		Type:        model.HTTP1,
+		Method:      model.RequestMethod(strings.ToUpper(method)),
+		Path:        f.value(method, "url"),
+		Description: blockText(f.block("docs")),
+	}
+	if req.Name == "" {
+		req.Name = strings.TrimSuffix(path.Base(e.name), ".bru")
+	}
+	if b := f.block("headers"); b != nil {
+		req.Headers = b.Pairs
+	}
+	if b := f.block("params:query"); b != nil {
+		req.QueryParams = b.Pairs
+	}
+	// 查询参数已单独保存，URL 中只保留路径
+	if i := strings.Index(req.Path, "?"); i >= 0 && len(req.QueryParams) > 0 {
+		req.Path = req.Path[:i]
+	}
+
+	bodyType := f.value(method, "body")
+	contentType := ""
+	switch bodyType {
+	case "json":
+		req.Body, contentType = blockText(f.block("body:json")), "application/json"
+	case "xml":
+		req.Body, contentType = blockText(f.block("body:xml")), "application/xml"
+	case "text":
+		req.Body, contentType = blockText(f.block("body:text")), "text/plain"
+	case "graphql":
+		req.Type, req.Method = model.GraphQL, model.POST
+		req.Query, req.Variables = blockText(f.block("body:graphql")), blockText(f.block("body:graphql:vars"))
+		contentType = "application/json"
+	case "form-urlencoded", "multipart-form":
+		values := url.Values{}
+		if b := f.block("body:" + bodyType); b != nil {
+			for _, kv := range b.Pairs {
+				if kv.Enabled {
+					values.Add(kv.Key, kv.Value)
+				}
+			}
+		}
+		req.Body, contentType = values.Encode(), "application/x-www-form-urlencoded"
+	case "", "none":
+	default:
+		warnings["unsupported body type: "+bodyType] = true
+	}
+	if contentType != "" && !hasHeader(req.Headers, "Content-Type") {
+		req.Headers = append(req.Headers, model.KeyValue{Key: "Content-Type", Value: contentType, Enabled: true})
+	}
+
+	switch auth := f.value(method, "auth"); auth {
+	case "bearer":
+		req.Headers = append(req.Headers, model.KeyValue{Key: "Authorization", Value: "Bearer " + f.value("auth:bearer", "token"), Enabled: true})
+	case "basic":
+		credentials := f.value("auth:basic", "username") + ":" + f.value("auth:basic", "password")
+		req.Headers = append(req.Headers, model.KeyValue{Key: "Authorization", Value: "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials)), Enabled: true})
+	case "", "none", "inherit":
+	default:
+		warnings["unsupported authentication type: "+auth] = true
+	}
+	return req
+}
+
+// brunoEnvironment 转换环境文件，密钥变量只保留名称
+func brunoEnvironment(f *bruFile) []model.KeyValue {
+	var vars []model.KeyValue
+	if b := f.block("vars"); b != nil {
+		vars = append(vars, b.Pairs...)
+	}
+	if b := f.block("vars:secret"); b != nil {
+		for _, kv := range b.Pairs {
+			vars = append(vars, model.KeyValue{Key: kv.Key, Enabled: kv.Enabled, Description: "secret"})
+		}
+	}
+	return vars
+}
+
+func brunoSeq(f *bruFile) int {
+	if f == nil {
+		return 0
+	}
+	return cast.ToInt(f.value("meta", "seq"))
+}
+
+func blockText(b *bruBlock) string {
+	if b == nil {
+		return ""
+	}
+	return b.Text
+}
+
+// relativeTo 返回 name 相对于 root 的路径
+func relativeTo(root, name string) (string, bool) {
+	if root == "." {
+		return name, true
+	}
+	if !strings.HasPrefix(name, root+"/") {
+		return "", false
+	}
+	return strings.TrimPrefix(name, root+"/"), true
+}
+
+func parentDir(dir string) string {
+	parent := path.Dir(dir)
+	if parent == "." {
+		return ""
+	}
+	return parent
+}
@@ -0,0 +1,145 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+)
+
+const sampleBru = `meta {
+  name: Create user
+  type: http
+  seq: 2
+}
+
+post {
+  url: {{baseUrl}}/users?debug=1
+  body: json
+  auth: bearer
+}
+
+params:query {
+  debug: 1
+  ~verbose: true
+}
+
+headers {
+  X-Trace: abc
+}
+
+auth:bearer {
+  token: {{token}}
+}
+
+body:json {
+  {
+    "name": "a"
+  }
+}
+
+docs {
+  Creates a user.
+}
+`
+
+func TestParseBru(t *testing.T) {
+	f := parseBru(sampleBru)
+	if f.value("meta", "name") != "Create user" || f.value("post", "url") != "{{baseUrl}}/users?debug=1" {
+		t.Errorf("unexpected dictionary values: %+v", f.block("meta"))
+	}
+	if b := f.block("params:query"); b == nil || len(b.Pairs) != 2 || !b.Pairs[0].Enabled || b.Pairs[1].Enabled || b.Pairs[1].Key != "verbose" {
+		t.Errorf("unexpected query block: %+v", b)
+	}
+	// 文本块保留原文，去掉一层缩进
+	if text := blockText(f.block("body:json")); text != "{\n  \"name\": \"a\"\n}" {
+		t.Errorf("unexpected body text: %q", text)
+	}
+	if f.block("missing") != nil || f.value("missing", "name") != "" {
+		t.Error("expected missing blocks to be empty")
+	}
+}
+
+// brunoArchive 将文件打包为 zip
+func brunoArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseBruno(t *testing.T) {
+	data := brunoArchive(t, map[string]string{
+		"shop/bruno.json":             `{"version": "1", "name": "Shop"}`,
+		"shop/health.bru":             "meta {\n  name: Health\n  seq: 1\n}\n\nget {\n  url: /health\n}\n",
+		"shop/users/folder.bru":       "meta {\n  name: User APIs\n}\n",
+		"shop/users/create.bru":       sampleBru,
+		"shop/users/list.bru":         "meta {\n  name: List users\n  seq: 1\n}\n\nget {\n  url: /users\n}\n",
+		"shop/users/notes.bru":        "meta {\n  name: Notes\n}\n",
+		"shop/environments/local.bru": "vars {\n  baseUrl: http://localhost\n}\n\nvars:secret [\n  token\n]\n",
+		"shop/README.md":              "ignored",
+	})
+	ws, warnings, err := ParseBruno(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ws.Name != "Shop" || len(ws.Collections) != 1 {
+		t.Fatalf("unexpected workspace: %+v", ws)
+	}
+	if len(warnings) != 1 || warnings[0] != "skipped file without http method: users/notes.bru" {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+
+	col := ws.Collections[0]
+	if len(col.Requests) != 1 || col.Requests[0].Name != "Health" {
+		t.Errorf("unexpected root requests: %+v", col.Requests)
+	}
+	if len(col.Folders) != 1 || col.Folders[0].Name != "User APIs" {
+		t.Fatalf("expected folder name from folder.bru, got %+v", col.Folders)
+	}
+	requests := col.Folders[0].Requests
+	if len(requests) != 2 || requests[0].Name != "List users" || requests[1].Name != "Create user" {
+		t.Fatalf("expected requests ordered by seq, got %+v", requests)
+	}
+
+	r := requests[1]
+	if r.Method != "POST" || r.Path != "{{baseUrl}}/users" || r.Body != blockText(parseBru(sampleBru).block("body:json")) {
+		t.Errorf("unexpected request: %+v", r)
+	}
+	if r.Description != "Creates a user." || r.SourceKey != "bruno:users/create.bru" {
+		t.Errorf("unexpected request metadata: %+v", r)
+	}
+	want := []string{"X-Trace: abc", "Content-Type: application/json", "Authorization: Bearer {{token}}"}
+	if len(r.Headers) != len(want) {
+		t.Fatalf("unexpected headers: %+v", r.Headers)
+	}
+	for i, h := range r.Headers {
+		if h.Key+": "+h.Value != want[i] {
+			t.Errorf("header %d: expected %q, got %q", i, want[i], h.Key+": "+h.Value)
+		}
+	}
+
+	// 密钥变量只保留名称
+	if len(col.Environments) != 1 {
+		t.Fatalf("unexpected environments: %+v", col.Environments)
+	}
+	vars := col.Environments[0].Variables
+	if col.Environments[0].Name != "local" || len(vars) != 2 || vars[0].Value != "http://localhost" || vars[1].Key != "token" || vars[1].Value != "" {
+		t.Errorf("unexpected environment: %+v", col.Environments[0])
+	}
+
+	if _, _, err := ParseBruno(brunoArchive(t, map[string]string{"a.bru": "get {\n}\n"})); err != ErrInvalidBruno {
+		t.Errorf("expected ErrInvalidBruno, got %v", err)
+	}
+}
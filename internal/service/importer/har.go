@@ -0,0 +1,291 @@
+package importer
+
+import (
+	"FastGo/internal/model"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidHAR 不是有效的 HAR 文件
+var ErrInvalidHAR = errors.New("invalid HAR file")
+
+// HAR HAR 文件中与导入相关的部分
+type HAR struct {
+	Log struct {
+		Entries []HAREntry `json:"entries"`
+	} `json:"log"`
+}
+
+// HAREntry 一次抓取到的请求和响应
+type HAREntry struct {
+	StartedDateTime string  `json:"startedDateTime"`
+	Time            float64 `json:"time"`
+	Request         struct {
+		Method      string       `json:"method"`
+		URL         string       `json:"url"`
+		Headers     []harNameVal `json:"headers"`
+		QueryString []harNameVal `json:"queryString"`
+		PostData    *struct {
+			MimeType string       `json:"mimeType"`
+			Text     string       `json:"text"`
+			Params   []harNameVal `json:"params"`
+		} `json:"postData"`
+	} `json:"request"`
+	Response struct {
+		Status     int          `json:"status"`
+		StatusText string       `json:"statusText"`
+		Headers    []harNameVal `json:"headers"`
+		Content    struct {
+			Size     int64  `json:"size"`
+			MimeType string `json:"mimeType"`
+			Text     string `json:"text"`
+			Encoding string `json:"encoding"`
+		} `json:"content"`
+	} `json:"response"`
+}
+
+type harNameVal struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HARFilter 选择要导入的条目，各条件之间为且关系，空条件表示不过滤
+type HARFilter struct {
+	Domains      []string
+	ContentTypes []string
+	Entries      []int
+}
+
+// HAREntrySummary 条目摘要，用于导入前预览
+type HAREntrySummary struct {
+	Index       int     `json:"index"`
+	Method      string  `json:"method"`
+	URL         string  `json:"url"`
+	Domain      string  `json:"domain"`
+	Status      int     `json:"status"`
+	ContentType string  `json:"content_type"`
+	Size        int64   `json:"size"`
+	Time        float64 `json:"time"`
+	StartedAt   string  `json:"started_at"`
+}
+
+// HARFacet 按域名或内容类型的统计
+type HARFacet struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// HARPreview HAR 文件预览
+type HARPreview struct {
+	Entries      []HAREntrySummary `json:"entries"`
+	Domains      []HARFacet        `json:"domains"`
+	ContentTypes []HARFacet        `json:"content_types"`
+}
+
+// 导入时丢弃的请求头，HTTP/2 伪头和由客户端自动计算的头
+var harSkippedHeaders = map[string]bool{
+	"content-length": true,
+	"host":           true,
+}
+
+// ParseHAR 解析 HAR 文件
+func ParseHAR(data []byte) (*HAR, error) {
+	var har HAR
+	if err := json.Unmarshal(data, &har); err != nil {
+		return nil, ErrInvalidHAR
+	}
+	return &har, nil
+}
+
+// Preview 返回条目摘要及域名、内容类型统计
+func (h *HAR) Preview() *HARPreview {
+	preview := &HARPreview{Entries: make([]HAREntrySummary, 0, len(h.Log.Entries))}
+	domains := map[string]int{}
+	contentTypes := map[string]int{}
+
+	for i, e := range h.Log.Entries {
+		summary := HAREntrySummary{
+			Index:       i,
+			Method:      e.Request.Method,
+			URL:         e.Request.URL,
+			Domain:      e.domain(),
+			Status:      e.Response.Status,
+			ContentType: e.contentType(),
+			Size:        e.Response.Content.Size,
+			Time:        e.Time,
+			StartedAt:   e.StartedDateTime,
+		}
+		preview.Entries = append(preview.Entries, summary)
+		domains[summary.Domain]++
+		contentTypes[summary.ContentType]++
+	}
+
+	preview.Domains = facets(domains)
+	preview.ContentTypes = facets(contentTypes)
+	return preview
+}
+
+// Collection 将过滤后的条目转换为一个文件夹下的请求
+func (h *HAR) Collection(filter HARFilter, folderName string) *Collection {
+	selected := map[int]bool{}
+	for _, i := range filter.Entries {
+		selected[i] = true
+	}
+
+	folder := &Folder{Name: folderName}
+	for i, e := range h.Log.Entries {
+		if len(selected) > 0 && !selected[i] {
+			continue
+		}
+		if !matchDomain(e.domain(), filter.Domains) || !matchContentType(e.contentType(), filter.ContentTypes) {
+			continue
+		}
+		folder.Requests = append(folder.Requests, e.request())
+	}
+	return &Collection{Name: folderName, Protocol: model.HTTP, Folders: []*Folder{folder}}
+}
+
+// domain 返回请求的主机名
+func (e *HAREntry) domain() string {
+	u, err := url.Parse(e.Request.URL)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// contentType 返回去掉参数后的响应内容类型
+func (e *HAREntry) contentType() string {
+	mime := e.Response.Content.MimeType
+	if i := strings.Index(mime, ";"); i >= 0 {
+		mime = mime[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(mime))
+}
+
+// request 将条目转换为请求，响应保存为示例
+func (e *HAREntry) request() *Request {
+	rawURL := e.Request.URL
+	path := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		u.RawQuery = ""
+		u.Fragment = ""
+		path = u.String()
+	}
+
+	req := &Request{
+		Name:   e.Request.Method + " " + requestName(rawURL),
+		Type:   model.HTTP1,
+		Method: model.RequestMethod(strings.ToUpper(e.Request.Method)),
+		Path:   path,
+	}
+	for _, q := range e.Request.QueryString {
+		req.QueryParams = append(req.QueryParams, model.KeyValue{Key: q.Name, Value: q.Value, Enabled: true})
+	}
+	req.Headers = harHeaders(e.Request.Headers)
+
+	if pd := e.Request.PostData; pd != nil {
+		if pd.Text != "" {
+			req.Body = pd.Text
+		} else if len(pd.Params) > 0 {
+			values := url.Values{}
+			for _, p := range pd.Params {
+				values.Add(p.Name, p.Value)
+			}
+			req.Body = values.Encode()
+		}
+	}
+
+	body := e.Response.Content.Text
+	if e.Response.Content.Encoding == "base64" && isTextContent(e.contentType()) {
+		if decoded, err := base64.StdEncoding.DecodeString(body); err == nil {
+			body = string(decoded)
+		}
+	}
+	req.Examples = []*Example{{
+		Name:        fmt.Sprintf("%d %s", e.Response.Status, e.Response.StatusText),
+		Status:      e.Response.Status,
+		StatusText:  e.Response.StatusText,
+		ContentType: e.Response.Content.MimeType,
+		Headers:     harHeaders(e.Response.Headers),
+		Body:        body,
+	}}
+	return req
+}
+
+// harHeaders 转换请求头并丢弃伪头
+func harHeaders(headers []harNameVal) []model.KeyValue {
+	var kvs []model.KeyValue
+	for _, h := range headers {
+		if strings.HasPrefix(h.Name, ":") || harSkippedHeaders[strings.ToLower(h.Name)] {
+			continue
+		}
+		kvs = append(kvs, model.KeyValue{Key: h.Name, Value: h.Value, Enabled: true})
+	}
+	return kvs
+}
+
+// requestName 使用 URL 路径作为请求名称
+func requestName(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Path == "" {
+		return rawURL
+	}
+	return u.Path
+}
+
+// matchDomain 域名完全匹配或为其子域名
+func matchDomain(domain string, domains []string) bool {
+	if len(domains) == 0 {
+		return true
+	}
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimSpace(d))
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchContentType 内容类型完全匹配，或按 "image/" 这样的前缀匹配
+func matchContentType(contentType string, types []string) bool {
+	if len(types) == 0 {
+		return true
+	}
+	for _, t := range types {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if contentType == t || (strings.HasSuffix(t, "/") && strings.HasPrefix(contentType, t)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTextContent 判断响应内容是否可以按文本保存
+func isTextContent(contentType string) bool {
+	return strings.HasPrefix(contentType, "text/") ||
+		strings.Contains(contentType, "json") ||
+		strings.Contains(contentType, "xml") ||
+		strings.Contains(contentType, "javascript")
+}
+
+// facets 按数量倒序返回统计结果
+func facets(counts map[string]int) []HARFacet {
+	list := make([]HARFacet, 0, len(counts))
+	for v, n := range counts {
+		list = append(list, HARFacet{Value: v, Count: n})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Value < list[j].Value
+	})
+	return list
+}
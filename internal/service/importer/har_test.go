@@ -0,0 +1,119 @@
+package importer
+
+import "testing"
+
+const sampleHAR = `{
+  "log": {
+    "entries": [
+      {
+        "startedDateTime": "2024-05-01T10:00:00Z",
+        "time": 12.5,
+        "request": {
+          "method": "get",
+          "url": "https://api.example.com/users?page=2#top",
+          "headers": [
+            {"name": ":authority", "value": "api.example.com"},
+            {"name": "Host", "value": "api.example.com"},
+            {"name": "Accept", "value": "application/json"}
+          ],
+          "queryString": [{"name": "page", "value": "2"}]
+        },
+        "response": {
+          "status": 200,
+          "statusText": "OK",
+          "headers": [{"name": "Content-Type", "value": "application/json"}],
+          "content": {"size": 13, "mimeType": "application/json; charset=utf-8", "text": "W3siaWQiOjF9XQ==", "encoding": "base64"}
+        }
+      },
+      {
+        "request": {
+          "method": "POST",
+          "url": "https://auth.example.com/login",
+          "postData": {"mimeType": "application/x-www-form-urlencoded", "params": [{"name": "user", "value": "a b"}]}
+        },
+        "response": {"status": 204, "statusText": "No Content", "content": {"mimeType": "text/plain"}}
+      },
+      {
+        "request": {"method": "GET", "url": "https://cdn.other.com/logo.png"},
+        "response": {"status": 200, "content": {"size": 2048, "mimeType": "image/png", "text": "iVBORw0KGgo=", "encoding": "base64"}}
+      }
+    ]
+  }
+}`
+
+func TestHARPreview(t *testing.T) {
+	har, err := ParseHAR([]byte(sampleHAR))
+	if err != nil {
+		t.Fatal(err)
+	}
+	preview := har.Preview()
+	if len(preview.Entries) != 3 || preview.Entries[0].Domain != "api.example.com" || preview.Entries[0].ContentType != "application/json" {
+		t.Fatalf("unexpected entries: %+v", preview.Entries)
+	}
+	if len(preview.Domains) != 3 || len(preview.ContentTypes) != 3 {
+		t.Errorf("unexpected facets: %+v %+v", preview.Domains, preview.ContentTypes)
+	}
+
+	if _, err := ParseHAR([]byte("not json")); err != ErrInvalidHAR {
+		t.Errorf("expected ErrInvalidHAR, got %v", err)
+	}
+}
+
+func TestHARCollectionFilters(t *testing.T) {
+	har, err := ParseHAR([]byte(sampleHAR))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 子域名按后缀匹配，按前缀过滤内容类型
+	col := har.Collection(HARFilter{Domains: []string{"example.com"}}, "capture")
+	if len(col.Folders) != 1 || col.Folders[0].Name != "capture" || len(col.Folders[0].Requests) != 2 {
+		t.Fatalf("expected 2 requests from example.com, got %+v", col.Folders)
+	}
+	col = har.Collection(HARFilter{ContentTypes: []string{"image/"}}, "capture")
+	if requests := col.Folders[0].Requests; len(requests) != 1 || requests[0].Path != "https://cdn.other.com/logo.png" {
+		t.Fatalf("expected only the image request, got %+v", requests)
+	}
+	col = har.Collection(HARFilter{Domains: []string{"example.com"}, Entries: []int{1, 2}}, "capture")
+	if requests := col.Folders[0].Requests; len(requests) != 1 || requests[0].Path != "https://auth.example.com/login" {
+		t.Fatalf("expected only the login request, got %+v", requests)
+	}
+}
+
+func TestHARRequestAndExample(t *testing.T) {
+	har, err := ParseHAR([]byte(sampleHAR))
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests := har.Collection(HARFilter{}, "capture").Folders[0].Requests
+
+	r := requests[0]
+	if r.Name != "get /users" || r.Method != "GET" || r.Path != "https://api.example.com/users" {
+		t.Fatalf("unexpected request: %+v", r)
+	}
+	if len(r.QueryParams) != 1 || r.QueryParams[0].Key != "page" || r.QueryParams[0].Value != "2" {
+		t.Errorf("unexpected query params: %+v", r.QueryParams)
+	}
+	// 伪头和 Host 被丢弃
+	if len(r.Headers) != 1 || r.Headers[0].Key != "Accept" {
+		t.Errorf("unexpected headers: %+v", r.Headers)
+	}
+	if len(r.Examples) != 1 {
+		t.Fatalf("expected one example, got %+v", r.Examples)
+	}
+	e := r.Examples[0]
+	if e.Name != "200 OK" || e.Status != 200 || e.ContentType != "application/json; charset=utf-8" || e.Body != `[{"id":1}]` {
+		t.Errorf("unexpected example: %+v", e)
+	}
+	if len(e.Headers) != 1 || e.Headers[0].Key != "Content-Type" {
+		t.Errorf("unexpected example headers: %+v", e.Headers)
+	}
+
+	if body := requests[1].Body; body != "user=a+b" {
+		t.Errorf("expected form params as body, got %q", body)
+	}
+	// 二进制响应保持 base64
+	if body := requests[2].Examples[0].Body; body != "iVBORw0KGgo=" {
+		t.Errorf("expected binary body to stay encoded, got %q", body)
+	}
+}
@@ -0,0 +1,334 @@
+package importer
+
+import (
+	"FastGo/internal/model"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidInsomnia 不是 Insomnia v4 导出文件
+var ErrInvalidInsomnia = errors.New("invalid Insomnia export, expected export format 4")
+
+// Insomnia 模板变量 {{ _.name }}，转换为 {{name}}
+var insomniaVarRegex = regexp.MustCompile(`\{\{\s*_\.([\w.-]+)\s*\}\}`)
+
+type insomniaExport struct {
+	Type         string             `json:"_type"`
+	ExportFormat int                `json:"__export_format"`
+	Resources    []insomniaResource `json:"resources"`
+}
+
+type insomniaResource struct {
+	ID          string                 `json:"_id"`
+	Type        string                 `json:"_type"`
+	ParentID    string                 `json:"parentId"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	MetaSortKey float64                `json:"metaSortKey"`
+	Method      string                 `json:"method"`
+	URL         string                 `json:"url"`
+	Body        insomniaBody           `json:"body"`
+	Parameters  []insomniaPair         `json:"parameters"`
+	Headers     []insomniaPair         `json:"headers"`
+	Auth        map[string]interface{} `json:"authentication"`
+	Data        map[string]interface{} `json:"data"`
+	ProtoMethod string                 `json:"protoMethodName"`
+}
+
+type insomniaBody struct {
+	MimeType string         `json:"mimeType"`
+	Text     string         `json:"text"`
+	Params   []insomniaPair `json:"params"`
+}
+
+type insomniaPair struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled"`
+}
+
+// ParseInsomnia 解析 Insomnia v4 导出文件（JSON 或 YAML）
+// 每个 Insomnia 工作区对应一个集合，请求组对应文件夹，环境对应集合环境
+func ParseInsomnia(data []byte) (*Workspace, []string, error) {
+	var export insomniaExport
+	if err := decodeJSONOrYAML(data, &export); err != nil {
+		return nil, nil, ErrInvalidInsomnia
+	}
+	if export.ExportFormat != 4 {
+		return nil, nil, ErrInvalidInsomnia
+	}
+
+	children := map[string][]*insomniaResource{}
+	var workspaces []*insomniaResource
+	for i := range export.Resources {
+		r := &export.Resources[i]
+		if r.Type == "workspace" {
+			workspaces = append(workspaces, r)
+			continue
+		}
+		children[r.ParentID] = append(children[r.ParentID], r)
+	}
+	for _, list := range children {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].MetaSortKey < list[j].MetaSortKey })
+	}
+
+	warnings := map[string]bool{}
+	ws := &Workspace{Name: "Insomnia Import"}
+	for _, w := range workspaces {
+		col := &Collection{Name: w.Name, Description: w.Description, Protocol: model.HTTP}
+		col.Folders, col.Requests = insomniaItems(w.ID, children, warnings)
+		col.Environments = insomniaEnvironments(w.ID, children)
+		ws.Collections = append(ws.Collections, col)
+	}
+	if len(workspaces) == 1 {
+		ws.Name = workspaces[0].Name
+	}
+
+	list := make([]string, 0, len(warnings))
+	for w := range warnings {
+		list = append(list, w)
+	}
+	sort.Strings(list)
+	return ws, list, nil
+}
+
+// insomniaItems 递归转换某个父节点下的请求组和请求
+func insomniaItems(parentID string, children map[string][]*insomniaResource, warnings map[string]bool) ([]*Folder, []*Request) {
+	var folders []*Folder
+	var requests []*Request
+	for _, r := range children[parentID] {
+		switch r.Type {
+		case "request_group":
+			f := &Folder{Name: r.Name}
+			f.Folders, f.Requests = insomniaItems(r.ID, children, warnings)
+			folders = append(folders, f)
+		case "request":
+			requests = append(requests, insomniaRequest(r, warnings))
+		case "grpc_request":
+			requests = append(requests, &Request{
+				SourceKey:   "insomnia:" + r.ID,
+				Name:        r.Name,
+				Type:        model.GRPC1,
+				Method:      model.POST,
+				Path:        insomniaVars(r.URL) + "/" + strings.TrimPrefix(r.ProtoMethod, "/"),
+				Body:        insomniaVars(r.Body.Text),
+				Description: r.Description,
+			})
+		case "websocket_request":
+			requests = append(requests, &Request{
+				SourceKey:   "insomnia:" + r.ID,
+				Name:        r.Name,
+				Type:        model.WebSocket,
+				Method:      model.GET,
+				Path:        insomniaVars(r.URL),
+				Headers:     insomniaPairs(r.Headers),
+				Description: r.Description,
+			})
+		case "environment", "cookie_jar", "api_spec", "unit_test_suite", "unit_test":
+		default:
+			warnings["unsupported resource type: "+r.Type] = true
+		}
+	}
+	return folders, requests
+}
+
+// insomniaRequest 转换 HTTP 请求，认证信息转换为请求头
+func insomniaRequest(r *insomniaResource, warnings map[string]bool) *Request {
+	req := &Request{
+		SourceKey:   "insomnia:" + r.ID,
+		Name:        r.Name,
+		Type:        model.HTTP1,
+		Method:      model.RequestMethod(strings.ToUpper(r.Method)),
+		Path:        insomniaVars(r.URL),
+		Headers:     insomniaPairs(r.Headers),
+		QueryParams: insomniaPairs(r.Parameters),
+		Description: r.Description,
+	}
+
+	switch {
+	case r.Body.MimeType == "application/graphql":
+		// GraphQL 请求体为 {"query": ..., "variables": ...}
+		var gql struct {
+			Query     string          `json:"query"`
+			Variables json.RawMessage `json:"variables"`
+		}
+		if err := json.Unmarshal([]byte(r.Body.Text), &gql); err != nil {
+			warnings["invalid graphql body: "+r.Name] = true
+			break
+		}
+		req.Type, req.Query = model.GraphQL, insomniaVars(gql.Query)
+		if len(gql.Variables) > 0 && string(gql.Variables) != "null" {
+			req.Variables = insomniaVars(string(gql.Variables))
+		}
+		r.Body.MimeType = "application/json"
+	case r.Body.Text != "":
+		req.Body = insomniaVars(r.Body.Text)
+	case len(r.Body.Params) > 0:
+		values := url.Values{}
+		for _, p := range r.Body.Params {
+			if !p.Disabled {
+				values.Add(p.Name, insomniaVars(p.Value))
+			}
+		}
+		req.Body = values.Encode()
+	}
+	if r.Body.MimeType != "" && !hasHeader(req.Headers, "Content-Type") {
+		req.Headers = append(req.Headers, model.KeyValue{Key: "Content-Type", Value: r.Body.MimeType, Enabled: true})
+	}
+
+	if header, ok := authHeader(r.Auth); ok {
+		req.Headers = append(req.Headers, header)
+	} else if t := asString(r.Auth["type"]); t != "" && t != "none" {
+		warnings["unsupported authentication type: "+t] = true
+	}
+	return req
+}
+
+// insomniaEnvironments 基础环境与子环境合并后分别生成环境
+func insomniaEnvironments(workspaceID string, children map[string][]*insomniaResource) []*Environment {
+	var envs []*Environment
+	for _, base := range children[workspaceID] {
+		if base.Type != "environment" {
+			continue
+		}
+		baseVars := flattenVariables("", base.Data)
+		envs = append(envs, &Environment{Name: base.Name, Variables: baseVars})
+
+		for _, sub := range children[base.ID] {
+			if sub.Type != "environment" {
+				continue
+			}
+			envs = append(envs, &Environment{
+				Name:      sub.Name,
+				Variables: mergeVariables(baseVars, flattenVariables("", sub.Data)),
+			})
+		}
+	}
+	return envs
+}
+
+// authHeader 将常见认证方式转换为请求头
+func authHeader(auth map[string]interface{}) (model.KeyValue, bool) {
+	if asBool(auth["disabled"]) {
+		return model.KeyValue{}, false
+	}
+	switch asString(auth["type"]) {
+	case "bearer":
+		prefix := asString(auth["prefix"])
+		if prefix == "" {
+			prefix = "Bearer"
+		}
+		return model.KeyValue{Key: "Authorization", Value: prefix + " " + insomniaVars(asString(auth["token"])), Enabled: true}, true
+	case "basic":
+		credentials := insomniaVars(asString(auth["username"])) + ":" + insomniaVars(asString(auth["password"]))
+		return model.KeyValue{Key: "Authorization", Value: "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials)), Enabled: true}, true
+	case "apikey":
+		if asString(auth["addTo"]) == "queryParams" {
+			return model.KeyValue{}, false
+		}
+		return model.KeyValue{Key: asString(auth["key"]), Value: insomniaVars(asString(auth["value"])), Enabled: true}, true
+	}
+	return model.KeyValue{}, false
+}
+
+func insomniaPairs(pairs []insomniaPair) []model.KeyValue {
+	kvs := make([]model.KeyValue, 0, len(pairs))
+	for _, p := range pairs {
+		if p.Name == "" {
+			continue
+		}
+		kvs = append(kvs, model.KeyValue{Key: p.Name, Value: insomniaVars(p.Value), Enabled: !p.Disabled})
+	}
+	return kvs
+}
+
+func insomniaVars(s string) string {
+	return insomniaVarRegex.ReplaceAllString(s, "{{$1}}")
+}
+
+// flattenVariables 将嵌套的环境数据展开为点分隔的变量
+func flattenVariables(prefix string, data map[string]interface{}) []model.KeyValue {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var vars []model.KeyValue
+	for _, k := range keys {
+		name := k
+		if prefix != "" {
+			name = prefix + "." + k
+		}
+		switch v := data[k].(type) {
+		case map[string]interface{}:
+			vars = append(vars, flattenVariables(name, v)...)
+		case string:
+			vars = append(vars, model.KeyValue{Key: name, Value: v, Enabled: true})
+		case nil:
+			vars = append(vars, model.KeyValue{Key: name, Enabled: true})
+		default:
+			value := fmt.Sprint(v)
+			if encoded, err := json.Marshal(v); err == nil {
+				value = string(encoded)
+			}
+			vars = append(vars, model.KeyValue{Key: name, Value: value, Enabled: true})
+		}
+	}
+	return vars
+}
+
+// mergeVariables 用 override 中的同名变量覆盖 base
+func mergeVariables(base, override []model.KeyValue) []model.KeyValue {
+	merged := make([]model.KeyValue, 0, len(base)+len(override))
+	index := map[string]int{}
+	for _, kv := range base {
+		index[kv.Key] = len(merged)
+		merged = append(merged, kv)
+	}
+	for _, kv := range override {
+		if i, ok := index[kv.Key]; ok {
+			merged[i] = kv
+			continue
+		}
+		index[kv.Key] = len(merged)
+		merged = append(merged, kv)
+	}
+	return merged
+}
+
+func hasHeader(headers []model.KeyValue, name string) bool {
+	for _, h := range headers {
+		if strings.EqualFold(h.Key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeJSONOrYAML 将 JSON 或 YAML 文档解码到结构体
+func decodeJSONOrYAML(data []byte, v interface{}) error {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		return json.Unmarshal(trimmed, v)
+	}
+
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	encoded, err := json.Marshal(normalizeYAML(raw))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded, v)
+}
@@ -0,0 +1,109 @@
+package importer
+
+import "testing"
+
+const sampleInsomnia = `{
+  "_type": "export",
+  "__export_format": 4,
+  "resources": [
+    {"_id": "req_nested", "_type": "request", "parentId": "fld_users", "name": "Get user", "method": "get",
+     "url": "{{ _.base_url }}/users/1",
+     "authentication": {"type": "bearer", "token": "{{ _.token }}"}},
+    {"_id": "fld_users", "_type": "request_group", "parentId": "fld_api", "name": "Users", "metaSortKey": 1},
+    {"_id": "fld_api", "_type": "request_group", "parentId": "wrk_1", "name": "API", "metaSortKey": 2},
+    {"_id": "req_root", "_type": "request", "parentId": "wrk_1", "name": "Health", "method": "GET", "url": "/health", "metaSortKey": 1},
+    {"_id": "req_login", "_type": "request", "parentId": "fld_api", "name": "Login", "method": "POST", "url": "/login",
+     "body": {"mimeType": "application/x-www-form-urlencoded", "params": [{"name": "user", "value": "a"}, {"name": "debug", "value": "1", "disabled": true}]}},
+    {"_id": "wrk_1", "_type": "workspace", "name": "Shop"},
+    {"_id": "env_base", "_type": "environment", "parentId": "wrk_1", "name": "Base",
+     "data": {"base_url": "http://localhost", "db": {"port": 5432}}},
+    {"_id": "env_prod", "_type": "environment", "parentId": "env_base", "name": "Prod",
+     "data": {"base_url": "https://api.shop.com"}},
+    {"_id": "spc_1", "_type": "mock_server", "parentId": "wrk_1"}
+  ]
+}`
+
+func TestInsomniaParentResolution(t *testing.T) {
+	ws, warnings, err := ParseInsomnia([]byte(sampleInsomnia))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ws.Name != "Shop" || len(ws.Collections) != 1 {
+		t.Fatalf("unexpected workspace: %+v", ws)
+	}
+	if len(warnings) != 1 || warnings[0] != "unsupported resource type: mock_server" {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+
+	// 资源顺序与父节点无关，按 parentId 归入对应的请求组
+	col := ws.Collections[0]
+	if len(col.Requests) != 1 || col.Requests[0].Name != "Health" {
+		t.Fatalf("unexpected root requests: %+v", col.Requests)
+	}
+	if len(col.Folders) != 1 || col.Folders[0].Name != "API" {
+		t.Fatalf("unexpected root folders: %+v", col.Folders)
+	}
+	api := col.Folders[0]
+	if len(api.Requests) != 1 || api.Requests[0].Name != "Login" {
+		t.Errorf("unexpected requests in API: %+v", api.Requests)
+	}
+	if len(api.Folders) != 1 || api.Folders[0].Name != "Users" {
+		t.Fatalf("unexpected folders in API: %+v", api.Folders)
+	}
+	users := api.Folders[0]
+	if len(users.Requests) != 1 || users.Requests[0].SourceKey != "insomnia:req_nested" {
+		t.Fatalf("unexpected requests in Users: %+v", users.Requests)
+	}
+}
+
+func TestInsomniaRequest(t *testing.T) {
+	ws, _, err := ParseInsomnia([]byte(sampleInsomnia))
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := ws.Collections[0].Folders[0]
+
+	r := api.Folders[0].Requests[0]
+	if r.Method != "GET" || r.Path != "{{base_url}}/users/1" {
+		t.Errorf("unexpected request: %+v", r)
+	}
+	if len(r.Headers) != 1 || r.Headers[0].Key != "Authorization" || r.Headers[0].Value != "Bearer {{token}}" {
+		t.Errorf("expected bearer auth as header, got %+v", r.Headers)
+	}
+
+	login := api.Requests[0]
+	if login.Body != "user=a" {
+		t.Errorf("expected enabled form params as body, got %q", login.Body)
+	}
+	if len(login.Headers) != 1 || login.Headers[0].Value != "application/x-www-form-urlencoded" {
+		t.Errorf("expected content type header, got %+v", login.Headers)
+	}
+}
+
+func TestInsomniaEnvironments(t *testing.T) {
+	ws, _, err := ParseInsomnia([]byte(sampleInsomnia))
+	if err != nil {
+		t.Fatal(err)
+	}
+	envs := ws.Collections[0].Environments
+	if len(envs) != 2 || envs[0].Name != "Base" || envs[1].Name != "Prod" {
+		t.Fatalf("unexpected environments: %+v", envs)
+	}
+	// 嵌套数据展开为点分隔变量，子环境覆盖基础环境的同名变量
+	base := envs[0].Variables
+	if len(base) != 2 || base[0].Key != "base_url" || base[1].Key != "db.port" || base[1].Value != "5432" {
+		t.Errorf("unexpected base variables: %+v", base)
+	}
+	prod := envs[1].Variables
+	if len(prod) != 2 || prod[0].Value != "https://api.shop.com" || prod[1].Key != "db.port" {
+		t.Errorf("unexpected merged variables: %+v", prod)
+	}
+}
+
+func TestInsomniaInvalid(t *testing.T) {
+	for _, data := range []string{"not an export: [", `{"_type": "export", "__export_format": 3, "resources": []}`} {
+		if _, _, err := ParseInsomnia([]byte(data)); err != ErrInvalidInsomnia {
+			t.Errorf("expected ErrInvalidInsomnia for %q, got %v", data, err)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package importer
+
+import (
+	"FastGo/internal/model"
+	"FastGo/internal/service/exporter"
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalidNative 不是原生导出文档或版本过新
+var ErrInvalidNative = errors.New("invalid native export document")
+
+// ParseNative 解析原生导出文档，保留全部标识、顺序和示例
+func ParseNative(data []byte) (*Collection, error) {
+	var doc exporter.NativeDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, ErrInvalidNative
+	}
+	if doc.Format != exporter.NativeFormat || doc.Version < 1 || doc.Version > exporter.NativeVersion {
+		return nil, ErrInvalidNative
+	}
+
+	c := doc.Collection
+	col := &Collection{
+		CollectionID: c.CollectionID,
+		Name:         c.Name,
+		Description:  c.Description,
+		Protocol:     model.FromString(c.Protocol),
+		Defaults:     nativeDefaults(c.Defaults),
+		Tags:         model.NormalizeTags(c.Tags),
+		Folders:      nativeFolders(c.Folders),
+		Requests:     nativeRequests(c.Requests),
+	}
+	for _, env := range c.Environments {
+		col.Environments = append(col.Environments, &Environment{
+			EnvironmentID: env.EnvironmentID,
+			Name:          env.Name,
+			Variables:     env.Variables,
+		})
+	}
+	return col, nil
+}
+
+func nativeFolders(list []exporter.NativeFolder) []*Folder {
+	folders := make([]*Folder, 0, len(list))
+	for _, f := range list {
+		folders = append(folders, &Folder{
+			FolderID:  f.FolderID,
+			Name:      f.Name,
+			SortOrder: f.SortOrder,
+			Defaults:  nativeDefaults(f.Defaults),
+			Tags:      model.NormalizeTags(f.Tags),
+			Folders:   nativeFolders(f.Folders),
+			Requests:  nativeRequests(f.Requests),
+		})
+	}
+	return folders
+}
+
+// nativeDefaults 转换为存储用的请求默认值，文档中没有时返回 nil
+func nativeDefaults(d *exporter.NativeDefaults) *model.Defaults {
+	if d == nil {
+		return nil
+	}
+	return &model.Defaults{
+		BaseURL:    d.BaseURL,
+		Headers:    model.EncodeKeyValues(d.Headers),
+		Auth:       model.EncodeAuth(d.Auth),
+		Timeout:    d.Timeout,
+		RetryCount: d.RetryCount,
+	}
+}
+
+func nativeRequests(list []exporter.NativeRequest) []*Request {
+	requests := make([]*Request, 0, len(list))
+	for _, r := range list {
+		req := &Request{
+			RequestID:   r.RequestID,
+			SortOrder:   r.SortOrder,
+			SourceKey:   r.SourceKey,
+			Name:        r.Name,
+			Type:        model.RequestType(r.Type),
+			Method:      model.RequestMethod(r.Method),
+			Path:        r.Path,
+			Transport:   r.Transport,
+			Headers:     r.Headers,
+			QueryParams: r.QueryParams,
+			Auth:        r.Auth,
+			Body:        r.Body,
+			Query:       r.Query,
+			Variables:   r.Variables,
+			Assertions:  r.Assertions,
+			Timeout:     r.Timeout,
+			RetryCount:  r.RetryCount,
+			Priority:    r.Priority,
+			Description: r.Description,
+			Tags:        r.Tags,
+		}
+		for _, e := range r.Examples {
+			req.Examples = append(req.Examples, &Example{
+				ExampleID:   e.ExampleID,
+				Name:        e.Name,
+				Status:      e.Status,
+				StatusText:  e.StatusText,
+				ContentType: e.ContentType,
+				Headers:     e.Headers,
+				Body:        e.Body,
+			})
+		}
+		requests = append(requests, req)
+	}
+	return requests
+}
@@ -0,0 +1,88 @@
+package importer
+
+import (
+	"FastGo/internal/model"
+	"FastGo/internal/service"
+	"FastGo/internal/service/exporter"
+	"encoding/json"
+	"testing"
+)
+
+func TestParseNativeRoundTrip(t *testing.T) {
+	headers := model.EncodeKeyValues([]model.KeyValue{{Key: "Accept", Value: "application/json", Enabled: true}})
+	first := &model.Request{RequestID: "r1", Name: "List", Type: model.HTTP1, Method: "GET", Path: "{{baseUrl}}/pets", Headers: headers, Timeout: 30,
+		Auth: model.EncodeAuth(&model.Auth{Type: model.AuthBearer, Token: "{{token}}"}), Tags: model.EncodeTags([]string{"smoke", "read"})}
+	defaults := model.Defaults{BaseURL: "http://localhost", Headers: headers, Timeout: 5000}
+	second := &model.Request{RequestID: "r2", Name: "Create", SortOrder: 3, Type: model.HTTP1, Method: "POST", Path: "{{baseUrl}}/pets", Body: `{"name":"x"}`}
+	data := &exporter.ExportData{
+		Tree: &service.CollectionTree{
+			Collection: model.Collections{CollectionID: "c1", Name: "Pets", Protocol: model.FromString("http"), Defaults: defaults},
+			Folders: []*service.FolderNode{{
+				Folder:   model.Folder{FolderID: "f1", Name: "pets", Tags: model.EncodeTags([]string{"pets"}), Defaults: model.Defaults{Auth: model.EncodeAuth(&model.Auth{Type: model.AuthNone})}},
+				Folders:  []*service.FolderNode{{Folder: model.Folder{FolderID: "f2", Name: "nested"}}},
+				Requests: []*model.Request{first, second},
+			}},
+		},
+		Examples: map[string][]model.RequestExample{
+			"r1": {{ExampleID: "e1", RequestID: "r1", Name: "ok", Status: 200, Body: "[]"}},
+		},
+		Environments: []model.Environment{{EnvironmentID: "env1", Name: "dev", Variables: model.EncodeKeyValues([]model.KeyValue{{Key: "baseUrl", Value: "http://localhost", Enabled: true}})}},
+	}
+
+	raw, err := json.Marshal(exporter.Native(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	col, err := ParseNative(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if col.CollectionID != "c1" || len(col.Folders) != 1 || col.Folders[0].FolderID != "f1" {
+		t.Fatalf("unexpected collection: %+v", col)
+	}
+	folder := col.Folders[0]
+	if len(folder.Folders) != 1 || folder.Folders[0].FolderID != "f2" {
+		t.Fatalf("nested folder lost: %+v", folder.Folders)
+	}
+	if len(folder.Requests) != 2 || folder.Requests[0].RequestID != "r1" || folder.Requests[1].RequestID != "r2" {
+		t.Fatalf("request order lost: %+v", folder.Requests)
+	}
+	r := folder.Requests[0]
+	if len(r.Headers) != 1 || r.Headers[0].Key != "Accept" || r.Timeout != 30 {
+		t.Fatalf("request fields lost: %+v", r)
+	}
+	if r.SortOrder == nil || *r.SortOrder != 0 || folder.Requests[1].SortOrder == nil || *folder.Requests[1].SortOrder != 3 {
+		t.Fatalf("sort order lost: %+v", folder.Requests)
+	}
+	if r.Auth == nil || r.Auth.Type != model.AuthBearer || r.Auth.Token != "{{token}}" || folder.Requests[1].Auth != nil {
+		t.Fatalf("request auth lost: %+v", r.Auth)
+	}
+	if len(r.Tags) != 2 || r.Tags[0] != "read" || r.Tags[1] != "smoke" || len(folder.Tags) != 1 || folder.Tags[0] != "pets" {
+		t.Fatalf("tags lost: %+v %+v", r.Tags, folder.Tags)
+	}
+	if col.Tags == nil || len(col.Tags) != 0 {
+		t.Fatalf("expected empty collection tags to replace existing ones, got %#v", col.Tags)
+	}
+	if col.Defaults == nil || *col.Defaults != defaults {
+		t.Fatalf("collection defaults lost: %+v", col.Defaults)
+	}
+	if folder.Defaults == nil || model.DecodeAuth(folder.Defaults.Auth).Type != model.AuthNone || folder.Folders[0].Defaults != nil {
+		t.Fatalf("folder defaults lost: %+v", folder.Defaults)
+	}
+	if len(r.Examples) != 1 || r.Examples[0].ExampleID != "e1" || r.Examples[0].Status != 200 {
+		t.Fatalf("examples lost: %+v", r.Examples)
+	}
+	if len(col.Environments) != 1 || col.Environments[0].EnvironmentID != "env1" || col.Environments[0].Variables[0].Value != "http://localhost" {
+		t.Fatalf("environments lost: %+v", col.Environments)
+	}
+
+	v1, err := ParseNative([]byte(`{"format":"rpc-master","version":1,"collection":{"name":"Pets","requests":[{"request_id":"r1","name":"List"}]}}`))
+	if err != nil || len(v1.Requests) != 1 || v1.Requests[0].SortOrder != nil {
+		t.Fatalf("expected version 1 document without sort order, got %+v %v", v1, err)
+	}
+
+	if _, err := ParseNative([]byte(`{"format":"other","version":1}`)); err != ErrInvalidNative {
+		t.Fatalf("expected ErrInvalidNative, got %v", err)
+	}
+}
@@ -0,0 +1,668 @@
+package importer
+
+import (
+	"FastGo/internal/model"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnsupportedSpec 不是 OpenAPI 3 或 Swagger 2 文档
+var ErrUnsupportedSpec = errors.New("unsupported spec, expected OpenAPI 3.x or Swagger 2.0")
+
+// 生成示例时的最大嵌套深度
+const maxSampleDepth = 8
+
+// 按常见顺序遍历一个路径下的操作
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+var pathParamRegex = regexp.MustCompile(`\{([^}/]+)\}`)
+
+// openAPISpec 解析中的文档
+type openAPISpec struct {
+	root     map[string]interface{}
+	swagger2 bool
+	warnings map[string]bool
+}
+
+// ParseOpenAPI 解析 OpenAPI 3 或 Swagger 2 文档，支持 YAML 和 JSON
+// 每个标签生成一个文件夹，每个操作生成一个请求，servers 转为环境变量
+func ParseOpenAPI(data []byte) (*Collection, []string, error) {
+	root, pathOrder, err := decodeSpec(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	spec := &openAPISpec{root: root, warnings: map[string]bool{}}
+	switch {
+	case strings.HasPrefix(asString(root["openapi"]), "3."):
+	case asString(root["swagger"]) == "2.0":
+		spec.swagger2 = true
+	default:
+		return nil, nil, ErrUnsupportedSpec
+	}
+
+	info := asMap(root["info"])
+	col := &Collection{
+		Name:        asString(info["title"]),
+		Description: asString(info["description"]),
+		Protocol:    model.HTTP,
+	}
+	if col.Name == "" {
+		col.Name = "Imported API"
+	}
+	col.Environments = spec.environments()
+
+	// 按文档 tags 声明顺序创建文件夹，未声明的标签按出现顺序追加
+	folders := map[string]*Folder{}
+	for _, t := range asSlice(root["tags"]) {
+		name := asString(asMap(t)["name"])
+		if name != "" && folders[name] == nil {
+			folders[name] = &Folder{Name: name}
+			col.Folders = append(col.Folders, folders[name])
+		}
+	}
+
+	paths := asMap(root["paths"])
+	for _, p := range pathOrder {
+		item := spec.resolve(paths[p])
+		for _, method := range openAPIMethods {
+			op := asMap(item[method])
+			if op == nil {
+				continue
+			}
+			req := spec.request(p, method, item, op)
+
+			tags := asSlice(op["tags"])
+			if len(tags) == 0 {
+				col.Requests = append(col.Requests, req)
+				continue
+			}
+			tag := asString(tags[0])
+			if folders[tag] == nil {
+				folders[tag] = &Folder{Name: tag}
+				col.Folders = append(col.Folders, folders[tag])
+			}
+			folders[tag].Requests = append(folders[tag].Requests, req)
+		}
+	}
+
+	warnings := make([]string, 0, len(spec.warnings))
+	for w := range spec.warnings {
+		warnings = append(warnings, w)
+	}
+	sort.Strings(warnings)
+	return col, warnings, nil
+}
+
+// decodeSpec 解码文档，同时返回 paths 的声明顺序
+func decodeSpec(data []byte) (map[string]interface{}, []string, error) {
+	var root map[string]interface{}
+	var order []string
+
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		if err := json.Unmarshal(trimmed, &root); err != nil {
+			return nil, nil, err
+		}
+		order = jsonObjectKeys(trimmed, "paths")
+	} else {
+		var node yaml.Node
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return nil, nil, err
+		}
+		var raw interface{}
+		if err := node.Decode(&raw); err != nil {
+			return nil, nil, err
+		}
+		root = asMap(normalizeYAML(raw))
+		order = yamlObjectKeys(&node, "paths")
+	}
+	if root == nil {
+		return nil, nil, ErrUnsupportedSpec
+	}
+
+	// 兜底：顺序提取失败时按字母序
+	if len(order) != len(asMap(root["paths"])) {
+		order = order[:0]
+		for p := range asMap(root["paths"]) {
+			order = append(order, p)
+		}
+		sort.Strings(order)
+	}
+	return root, order, nil
+}
+
+// yamlObjectKeys 按声明顺序返回顶层对象 field 的键
+func yamlObjectKeys(node *yaml.Node, field string) []string {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != field || node.Content[i+1].Kind != yaml.MappingNode {
+			continue
+		}
+		value := node.Content[i+1]
+		keys := make([]string, 0, len(value.Content)/2)
+		for j := 0; j+1 < len(value.Content); j += 2 {
+			keys = append(keys, value.Content[j].Value)
+		}
+		return keys
+	}
+	return nil
+}
+
+// jsonObjectKeys 按声明顺序返回 JSON 顶层对象 field 的键
+func jsonObjectKeys(data []byte, field string) []string {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil
+		}
+		if key, _ := tok.(string); key != field {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil
+			}
+			continue
+		}
+
+		if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+			return nil
+		}
+		var keys []string
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil
+			}
+			key, _ := tok.(string)
+			keys = append(keys, key)
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil
+			}
+		}
+		return keys
+	}
+	return nil
+}
+
+// normalizeYAML 将 YAML 中非字符串键的映射（如响应码 200）统一转换为字符串键
+func normalizeYAML(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			t[k] = normalizeYAML(val)
+		}
+		return t
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			m[fmt.Sprint(k)] = normalizeYAML(val)
+		}
+		return m
+	case []interface{}:
+		for i, val := range t {
+			t[i] = normalizeYAML(val)
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+// resolve 解析本地 $ref 引用
+func (s *openAPISpec) resolve(v interface{}) map[string]interface{} {
+	m, _ := s.deref(v)
+	return m
+}
+
+// deref 解析本地 $ref 引用，同时返回引用路径
+func (s *openAPISpec) deref(v interface{}) (map[string]interface{}, string) {
+	m := asMap(v)
+	ref := ""
+	for i := 0; i < 16 && m != nil; i++ {
+		r := asString(m["$ref"])
+		if r == "" {
+			return m, ref
+		}
+		if !strings.HasPrefix(r, "#/") {
+			s.warnings["external reference not supported: "+r] = true
+			return nil, r
+		}
+		ref = r
+		var cur interface{} = s.root
+		for _, part := range strings.Split(strings.TrimPrefix(r, "#/"), "/") {
+			part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+			cur = asMap(cur)[part]
+		}
+		m = asMap(cur)
+		if m == nil {
+			s.warnings["unresolved reference: "+r] = true
+		}
+	}
+	return m, ref
+}
+
+// environments 将 servers（或 Swagger 2 的 host/basePath）转换为环境
+func (s *openAPISpec) environments() []*Environment {
+	if s.swagger2 {
+		host := asString(s.root["host"])
+		if host == "" {
+			return nil
+		}
+		scheme := "https"
+		if schemes := asSlice(s.root["schemes"]); len(schemes) > 0 {
+			scheme = asString(schemes[0])
+		}
+		baseURL := scheme + "://" + host + asString(s.root["basePath"])
+		return []*Environment{{
+			Name:      host,
+			Variables: []model.KeyValue{{Key: "baseUrl", Value: baseURL, Enabled: true}},
+		}}
+	}
+
+	var envs []*Environment
+	seen := map[string]int{}
+	for _, sv := range asSlice(s.root["servers"]) {
+		server := asMap(sv)
+		serverURL := asString(server["url"])
+		if serverURL == "" {
+			continue
+		}
+
+		name := asString(server["description"])
+		if name == "" {
+			name = serverURL
+		}
+		if seen[name]++; seen[name] > 1 {
+			name = fmt.Sprintf("%s (%d)", name, seen[name])
+		}
+
+		vars := []model.KeyValue{{
+			Key:     "baseUrl",
+			Value:   pathParamRegex.ReplaceAllString(serverURL, "{{$1}}"),
+			Enabled: true,
+		}}
+		serverVars := asMap(server["variables"])
+		names := make([]string, 0, len(serverVars))
+		for k := range serverVars {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		for _, k := range names {
+			v := asMap(serverVars[k])
+			vars = append(vars, model.KeyValue{
+				Key:         k,
+				Value:       fmt.Sprint(v["default"]),
+				Enabled:     true,
+				Description: asString(v["description"]),
+			})
+		}
+		envs = append(envs, &Environment{Name: name, Variables: vars})
+	}
+	return envs
+}
+
+// request 将一个操作转换为请求
+func (s *openAPISpec) request(path, method string, item, op map[string]interface{}) *Request {
+	upper := strings.ToUpper(method)
+	req := &Request{
+		Type:   model.HTTP1,
+		Method: model.RequestMethod(upper),
+		Path:   "{{baseUrl}}" + pathParamRegex.ReplaceAllString(path, ":$1"),
+	}
+
+	if id := asString(op["operationId"]); id != "" {
+		req.SourceKey = "openapi:" + id
+	} else {
+		req.SourceKey = "openapi:" + upper + " " + path
+	}
+
+	req.Name = asString(op["summary"])
+	if req.Name == "" {
+		req.Name = asString(op["operationId"])
+	}
+	if req.Name == "" {
+		req.Name = upper + " " + path
+	}
+	req.Description = asString(op["description"])
+
+	// 操作级参数覆盖路径级同名参数
+	params := []map[string]interface{}{}
+	index := map[string]int{}
+	for _, list := range [][]interface{}{asSlice(item["parameters"]), asSlice(op["parameters"])} {
+		for _, raw := range list {
+			p := s.resolve(raw)
+			if p == nil {
+				continue
+			}
+			key := asString(p["in"]) + ":" + asString(p["name"])
+			if i, ok := index[key]; ok {
+				params[i] = p
+				continue
+			}
+			index[key] = len(params)
+			params = append(params, p)
+		}
+	}
+
+	var formParams []map[string]interface{}
+	for _, p := range params {
+		name := asString(p["name"])
+		kv := model.KeyValue{
+			Key:         name,
+			Value:       s.placeholder(p),
+			Enabled:     asBool(p["required"]),
+			Description: asString(p["description"]),
+		}
+		switch asString(p["in"]) {
+		case "query":
+			req.QueryParams = append(req.QueryParams, kv)
+		case "header":
+			kv.Enabled = true
+			req.Headers = append(req.Headers, kv)
+		case "cookie":
+			kv.Key = "Cookie"
+			kv.Value = name + "=" + kv.Value
+			req.Headers = append(req.Headers, kv)
+		case "body":
+			req.Body = s.sampleBody("application/json", s.sample(p["schema"], 0, map[string]bool{}))
+			req.Headers = append(req.Headers, s.contentType(op, "application/json"))
+		case "formData":
+			formParams = append(formParams, p)
+		}
+	}
+
+	if len(formParams) > 0 {
+		values := url.Values{}
+		for _, p := range formParams {
+			values.Add(asString(p["name"]), s.placeholder(p))
+		}
+		req.Body = values.Encode()
+		req.Headers = append(req.Headers, s.contentType(op, "application/x-www-form-urlencoded"))
+	}
+
+	if !s.swagger2 {
+		if body := s.resolve(op["requestBody"]); body != nil {
+			mediaType, media := pickMediaType(asMap(body["content"]))
+			if media != nil {
+				var sample interface{}
+				if ex, ok := media["example"]; ok {
+					sample = ex
+				} else if exs := asMap(media["examples"]); len(exs) > 0 {
+					sample = firstExample(s, exs)
+				} else {
+					sample = s.sample(media["schema"], 0, map[string]bool{})
+				}
+				req.Body = s.sampleBody(mediaType, sample)
+				req.Headers = append(req.Headers, model.KeyValue{Key: "Content-Type", Value: mediaType, Enabled: true})
+			}
+		}
+	}
+
+	return req
+}
+
+// contentType 生成 Swagger 2 的 Content-Type 请求头
+func (s *openAPISpec) contentType(op map[string]interface{}, fallback string) model.KeyValue {
+	consumes := asSlice(op["consumes"])
+	if len(consumes) == 0 {
+		consumes = asSlice(s.root["consumes"])
+	}
+	value := fallback
+	if len(consumes) > 0 {
+		value = asString(consumes[0])
+	}
+	return model.KeyValue{Key: "Content-Type", Value: value, Enabled: true}
+}
+
+// placeholder 生成参数占位值，优先使用示例和默认值
+func (s *openAPISpec) placeholder(p map[string]interface{}) string {
+	schema := s.resolve(p["schema"])
+	if schema == nil {
+		// Swagger 2 的非 body 参数直接在参数上声明类型
+		schema = p
+	}
+	for _, v := range []interface{}{p["example"], schema["example"], schema["default"]} {
+		if v != nil {
+			return fmt.Sprint(v)
+		}
+	}
+	if enum := asSlice(schema["enum"]); len(enum) > 0 {
+		return fmt.Sprint(enum[0])
+	}
+	typ := schemaType(schema)
+	if typ == "" {
+		typ = "string"
+	}
+	return "<" + typ + ">"
+}
+
+// sampleBody 将示例对象序列化为请求体
+func (s *openAPISpec) sampleBody(mediaType string, sample interface{}) string {
+	if sample == nil {
+		return ""
+	}
+	if str, ok := sample.(string); ok && !strings.Contains(mediaType, "json") {
+		return str
+	}
+	if strings.Contains(mediaType, "form") {
+		values := url.Values{}
+		for k, v := range asMap(sample) {
+			values.Set(k, fmt.Sprint(v))
+		}
+		return values.Encode()
+	}
+	data, err := json.MarshalIndent(sample, "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// sample 根据 schema 生成示例值，refs 记录当前路径上的引用以避免循环
+func (s *openAPISpec) sample(v interface{}, depth int, refs map[string]bool) interface{} {
+	schema, ref := s.deref(v)
+	if schema == nil || depth > maxSampleDepth {
+		return nil
+	}
+	if ref != "" {
+		if refs[ref] {
+			return nil
+		}
+		refs[ref] = true
+		defer delete(refs, ref)
+	}
+
+	if ex, ok := schema["example"]; ok {
+		return ex
+	}
+	if exs := asSlice(schema["examples"]); len(exs) > 0 {
+		return exs[0]
+	}
+	if def, ok := schema["default"]; ok {
+		return def
+	}
+	if enum := asSlice(schema["enum"]); len(enum) > 0 {
+		return enum[0]
+	}
+	if c, ok := schema["const"]; ok {
+		return c
+	}
+
+	if all := asSlice(schema["allOf"]); len(all) > 0 {
+		merged := map[string]interface{}{}
+		for _, sub := range all {
+			for k, val := range asMap(s.sample(sub, depth+1, refs)) {
+				merged[k] = val
+			}
+		}
+		for k, val := range asMap(s.objectSample(schema, depth, refs)) {
+			merged[k] = val
+		}
+		return merged
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if subs := asSlice(schema[key]); len(subs) > 0 {
+			return s.sample(subs[0], depth+1, refs)
+		}
+	}
+
+	switch schemaType(schema) {
+	case "object":
+		return s.objectSample(schema, depth, refs)
+	case "array":
+		item := s.sample(schema["items"], depth+1, refs)
+		if item == nil {
+			return []interface{}{}
+		}
+		return []interface{}{item}
+	case "integer":
+		return 0
+	case "number":
+		return 0.0
+	case "boolean":
+		return false
+	case "null":
+		return nil
+	case "string":
+		return stringSample(asString(schema["format"]))
+	}
+	if schema["properties"] != nil || schema["additionalProperties"] != nil {
+		return s.objectSample(schema, depth, refs)
+	}
+	return nil
+}
+
+// objectSample 生成对象示例
+func (s *openAPISpec) objectSample(schema map[string]interface{}, depth int, refs map[string]bool) interface{} {
+	obj := map[string]interface{}{}
+	for name, prop := range asMap(schema["properties"]) {
+		obj[name] = s.sample(prop, depth+1, refs)
+	}
+	if extra := asMap(schema["additionalProperties"]); extra != nil && len(obj) == 0 {
+		obj["key"] = s.sample(extra, depth+1, refs)
+	}
+	return obj
+}
+
+// schemaType 返回 schema 的类型，兼容 3.1 的类型数组
+func schemaType(schema map[string]interface{}) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, v := range t {
+			if str := asString(v); str != "" && str != "null" {
+				return str
+			}
+		}
+	}
+	return ""
+}
+
+// stringSample 根据字符串格式生成示例
+func stringSample(format string) string {
+	switch format {
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	case "date":
+		return "2024-01-01"
+	case "time":
+		return "00:00:00"
+	case "email":
+		return "user@example.com"
+	case "uuid":
+		return "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+	case "uri", "url":
+		return "https://example.com"
+	case "hostname":
+		return "example.com"
+	case "ipv4":
+		return "127.0.0.1"
+	case "ipv6":
+		return "::1"
+	case "byte":
+		return "U3dhZ2dlciByb2Nrcw=="
+	case "binary":
+		return ""
+	case "password":
+		return "********"
+	default:
+		return "string"
+	}
+}
+
+// pickMediaType 优先选择 JSON，其次表单，最后取字母序第一个
+func pickMediaType(content map[string]interface{}) (string, map[string]interface{}) {
+	if len(content) == 0 {
+		return "", nil
+	}
+	types := make([]string, 0, len(content))
+	for t := range content {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	preferred := []func(string) bool{
+		func(t string) bool { return t == "application/json" },
+		func(t string) bool { return strings.HasSuffix(t, "+json") || strings.Contains(t, "json") },
+		func(t string) bool { return t == "application/x-www-form-urlencoded" },
+		func(t string) bool { return t == "multipart/form-data" },
+		func(t string) bool { return true },
+	}
+	for _, match := range preferred {
+		for _, t := range types {
+			if match(t) {
+				return t, asMap(content[t])
+			}
+		}
+	}
+	return "", nil
+}
+
+// firstExample 返回 examples 中按名称排序的第一个示例值
+func firstExample(s *openAPISpec, examples map[string]interface{}) interface{} {
+	names := make([]string, 0, len(examples))
+	for name := range examples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return s.resolve(examples[names[0]])["value"]
+}
+
+func asMap(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+func asSlice(v interface{}) []interface{} {
+	s, _ := v.([]interface{})
+	return s
+}
+
+func asString(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+func asBool(v interface{}) bool {
+	b, _ := v.(bool)
+	return b
+}
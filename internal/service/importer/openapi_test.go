@@ -0,0 +1,132 @@
+package importer
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const petstoreYAML = `
+openapi: 3.0.0
+info:
+  title: Petstore
+servers:
+  - url: https://{env}.example.com/v1
+    description: Production
+    variables:
+      env:
+        default: api
+tags:
+  - name: pets
+paths:
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        required: true
+        schema:
+          type: integer
+    get:
+      operationId: getPet
+      summary: Get a pet
+      tags: [pets]
+      parameters:
+        - name: verbose
+          in: query
+          schema:
+            type: boolean
+  /pets:
+    post:
+      operationId: createPet
+      tags: [pets]
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        201:
+          description: created
+  /health:
+    get:
+      responses:
+        200:
+          description: ok
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+        born:
+          type: string
+          format: date
+        parent:
+          $ref: '#/components/schemas/Pet'
+`
+
+func TestParseOpenAPI(t *testing.T) {
+	col, warnings, err := ParseOpenAPI([]byte(petstoreYAML))
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("不应有警告: %v", warnings)
+	}
+	if col.Name != "Petstore" {
+		t.Errorf("集合名称不正确: %s", col.Name)
+	}
+
+	if len(col.Folders) != 1 || len(col.Folders[0].Requests) != 2 {
+		t.Fatalf("应生成一个包含两个请求的 pets 文件夹")
+	}
+	get := col.Folders[0].Requests[0]
+	if get.Path != "{{baseUrl}}/pets/:petId" || get.SourceKey != "openapi:getPet" {
+		t.Errorf("路径或来源标识不正确: %s %s", get.Path, get.SourceKey)
+	}
+	if len(get.QueryParams) != 1 || get.QueryParams[0].Value != "<boolean>" {
+		t.Errorf("查询参数占位不正确: %+v", get.QueryParams)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(col.Folders[0].Requests[1].Body), &body); err != nil {
+		t.Fatalf("请求体不是 JSON: %v", err)
+	}
+	if body["born"] != "2024-01-01" || body["parent"] != nil {
+		t.Errorf("请求体示例不正确: %v", body)
+	}
+
+	if len(col.Requests) != 1 || col.Requests[0].SourceKey != "openapi:GET /health" {
+		t.Errorf("无标签操作应放在集合根目录: %+v", col.Requests)
+	}
+
+	if len(col.Environments) != 1 || col.Environments[0].Variables[0].Value != "https://{{env}}.example.com/v1" {
+		t.Errorf("servers 转换不正确: %+v", col.Environments)
+	}
+}
+
+func TestParseSwagger2JSON(t *testing.T) {
+	spec := `{
+		"swagger": "2.0",
+		"info": {"title": "Legacy"},
+		"host": "legacy.example.com",
+		"basePath": "/api",
+		"paths": {
+			"/b": {"post": {"parameters": [{"name": "q", "in": "formData", "type": "string"}]}},
+			"/a": {"get": {}}
+		}
+	}`
+	col, _, err := ParseOpenAPI([]byte(spec))
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if len(col.Requests) != 2 || col.Requests[0].Path != "{{baseUrl}}/b" {
+		t.Fatalf("应按声明顺序生成请求: %+v", col.Requests)
+	}
+	if col.Requests[0].Body != "q=%3Cstring%3E" {
+		t.Errorf("表单请求体不正确: %s", col.Requests[0].Body)
+	}
+	if col.Environments[0].Variables[0].Value != "https://legacy.example.com/api" {
+		t.Errorf("baseUrl 不正确: %+v", col.Environments[0].Variables)
+	}
+}
@@ -0,0 +1,573 @@
+package importer
+
+import (
+	"FastGo/internal/model"
+	"FastGo/internal/service"
+	"FastGo/pkg/uid"
+	"errors"
+
+	"github.com/spf13/cast"
+	"gorm.io/gorm"
+)
+
+// ErrCollectionNotFound 目标集合不存在
+var ErrCollectionNotFound = errors.New("collection not found")
+
+// saver 在一个事务内把导入结构写入数据库
+type saver struct {
+	tx        *gorm.DB
+	opts      Options
+	report    *Report
+	existing  map[string]*model.Request // SourceKey -> 已有请求
+	requests  map[string]*model.Request // RequestID -> 已有请求
+	folders   map[string]string         // 父文件夹ID + "/" + 名称 -> 文件夹ID
+	folderIDs map[string]bool           // 集合中已有的文件夹ID
+	taken     map[string]bool           // 已被其他集合占用、导入时不能沿用的标识
+}
+
+// Save 将导入结构写入数据库，返回导入报告
+func Save(db *gorm.DB, col *Collection, opts Options) (*Report, error) {
+	var report *Report
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		report, err = saveCollection(tx, col, opts)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// SaveWorkspace 将多个集合写入同一工作区，未指定工作区时按导入名称新建
+func SaveWorkspace(db *gorm.DB, ws *Workspace, opts Options) (*WorkspaceReport, error) {
+	report := &WorkspaceReport{Collections: []*Report{}, Warnings: []string{}}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if opts.WorkspaceID == 0 {
+			workspace := model.Workspace{
+				Name:    ws.Name,
+				OwnerID: opts.OwnerID,
+			}
+			if err := tx.Create(&workspace).Error; err != nil {
+				return err
+			}
+			opts.WorkspaceID = workspace.ID
+		}
+		report.WorkspaceID = cast.ToString(opts.WorkspaceID)
+
+		for _, col := range ws.Collections {
+			r, err := saveCollection(tx, col, opts)
+			if err != nil {
+				return err
+			}
+			report.Collections = append(report.Collections, r)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// saveCollection 在事务内写入单个集合
+func saveCollection(tx *gorm.DB, col *Collection, opts Options) (*Report, error) {
+	report := &Report{Items: []ReportItem{}, Warnings: []string{}}
+
+	collection, err := resolveCollection(tx, col, opts)
+	if err != nil {
+		return nil, err
+	}
+	report.CollectionID = collection.CollectionID
+	if col.Defaults != nil && opts.Conflict == ConflictOverwrite && collection.Defaults != *col.Defaults {
+		err := tx.Model(collection).
+			Select("default_base_url", "default_headers", "default_auth", "default_timeout", "default_retry_count").
+			Updates(&model.Collections{Defaults: *col.Defaults}).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	if tags := model.EncodeTags(col.Tags); col.Tags != nil && opts.Conflict == ConflictOverwrite && collection.Tags != tags {
+		if err := tx.Model(collection).Update("tags", tags).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	s := &saver{
+		tx:        tx,
+		opts:      opts,
+		report:    report,
+		existing:  map[string]*model.Request{},
+		requests:  map[string]*model.Request{},
+		folders:   map[string]string{},
+		folderIDs: map[string]bool{},
+		taken:     map[string]bool{},
+	}
+	if err := s.loadExisting(collection.CollectionID); err != nil {
+		return nil, err
+	}
+	if err := s.loadTaken(collection.CollectionID, col); err != nil {
+		return nil, err
+	}
+
+	if err := s.saveItems(collection.CollectionID, "", col.Folders, col.Requests); err != nil {
+		return nil, err
+	}
+	if err := s.saveEnvironments(collection, col.Environments); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// resolveCollection 查找目标集合：依次尝试指定集合、导入文件中的集合标识、工作区内同名集合，都没有时新建
+func resolveCollection(tx *gorm.DB, col *Collection, opts Options) (*model.Collections, error) {
+	var collection model.Collections
+	if opts.CollectionID != "" {
+		err := tx.Where("collection_id = ? AND workspace_id = ?", opts.CollectionID, opts.WorkspaceID).
+			First(&collection).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCollectionNotFound
+		}
+		return &collection, err
+	}
+
+	collectionID := uid.NewUUID()
+	if col.CollectionID != "" && opts.Conflict != ConflictDuplicate {
+		// 回收站中的集合仍占用标识，永久删除时会按标识删除，因此同样视为已占用
+		var existing []model.Collections
+		if err := tx.Unscoped().Where("collection_id = ?", col.CollectionID).Find(&existing).Error; err != nil {
+			return nil, err
+		}
+		for i := range existing {
+			if existing[i].WorkspaceID == opts.WorkspaceID && !existing[i].DeletedAt.Valid {
+				return &existing[i], nil
+			}
+		}
+		// 标识已被其他工作区或回收站中的集合使用时视为复制，重新生成
+		if len(existing) == 0 {
+			collectionID = col.CollectionID
+		}
+	}
+
+	if opts.Conflict != ConflictDuplicate {
+		err := tx.Where("workspace_id = ? AND name = ?", opts.WorkspaceID, col.Name).First(&collection).Error
+		if err == nil {
+			return &collection, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	protocol := col.Protocol
+	if protocol == 0 {
+		protocol = model.HTTP
+	}
+	collection = model.Collections{
+		Name:         col.Name,
+		OwnerID:      opts.OwnerID,
+		Protocol:     protocol,
+		WorkspaceID:  opts.WorkspaceID,
+		Description:  col.Description,
+		CollectionID: collectionID,
+	}
+	if col.Defaults != nil {
+		collection.Defaults = *col.Defaults
+	}
+	collection.Tags = model.EncodeTags(col.Tags)
+	if err := tx.Create(&collection).Error; err != nil {
+		return nil, err
+	}
+	return &collection, nil
+}
+
+// loadExisting 加载集合中已有的请求和文件夹，用于匹配重复导入，回收站中的记录不参与匹配
+func (s *saver) loadExisting(collectionID string) error {
+	var requests []model.Request
+	if err := s.tx.Where("collection_id = ?", collectionID).Find(&requests).Error; err != nil {
+		return err
+	}
+	for i := range requests {
+		s.requests[requests[i].RequestID] = &requests[i]
+		if requests[i].SourceKey != "" {
+			s.existing[requests[i].SourceKey] = &requests[i]
+		}
+	}
+
+	var folders []model.Folder
+	if err := s.tx.Where("collection_id = ?", collectionID).Find(&folders).Error; err != nil {
+		return err
+	}
+	if len(folders) == 0 {
+		return nil
+	}
+
+	folderIDs := make([]string, 0, len(folders))
+	for _, f := range folders {
+		folderIDs = append(folderIDs, f.FolderID)
+		s.folderIDs[f.FolderID] = true
+	}
+	var closures []model.FolderClosure
+	if err := s.tx.Where("descendant IN (?) AND depth = 1", folderIDs).Find(&closures).Error; err != nil {
+		return err
+	}
+	parents := make(map[string]string, len(closures))
+	for _, c := range closures {
+		parents[c.Descendant] = c.Ancestor
+	}
+	for _, f := range folders {
+		s.folders[parents[f.FolderID]+"/"+f.Name] = f.FolderID
+	}
+	return nil
+}
+
+// loadTaken 找出导入文件中已被其他集合或回收站中的记录使用的标识，这些对象导入时需要重新生成标识
+// 回收站中的记录在永久删除时按标识删除，沿用其标识会导致导入的记录一起被删除
+func (s *saver) loadTaken(collectionID string, col *Collection) error {
+	var folderIDs, requestIDs, exampleIDs, environmentIDs []string
+	var walk func(folders []*Folder, requests []*Request)
+	walk = func(folders []*Folder, requests []*Request) {
+		for _, f := range folders {
+			if f.FolderID != "" {
+				folderIDs = append(folderIDs, f.FolderID)
+			}
+			walk(f.Folders, f.Requests)
+		}
+		for _, r := range requests {
+			if r.RequestID != "" {
+				requestIDs = append(requestIDs, r.RequestID)
+			}
+			for _, e := range r.Examples {
+				if e.ExampleID != "" {
+					exampleIDs = append(exampleIDs, e.ExampleID)
+				}
+			}
+		}
+	}
+	walk(col.Folders, col.Requests)
+	for _, env := range col.Environments {
+		if env.EnvironmentID != "" {
+			environmentIDs = append(environmentIDs, env.EnvironmentID)
+		}
+	}
+
+	checks := []struct {
+		ids   []string
+		model interface{}
+		query string
+		args  []interface{}
+		field string
+	}{
+		{folderIDs, &model.Folder{}, "folder_id IN (?) AND (collection_id <> ? OR deleted_at IS NOT NULL)", []interface{}{folderIDs, collectionID}, "folder_id"},
+		{requestIDs, &model.Request{}, "request_id IN (?) AND (collection_id <> ? OR deleted_at IS NOT NULL)", []interface{}{requestIDs, collectionID}, "request_id"},
+		{environmentIDs, &model.Environment{}, "environment_id IN (?) AND collection_id <> ?", []interface{}{environmentIDs, collectionID}, "environment_id"},
+		// 示例随请求整体替换，只要标识已存在于本集合之外的请求就需要重新生成
+		{exampleIDs, &model.RequestExample{}, "example_id IN (?) AND request_id NOT IN (?)", []interface{}{exampleIDs, s.requestIDList()}, "example_id"},
+	}
+	for _, c := range checks {
+		if len(c.ids) == 0 {
+			continue
+		}
+		var taken []string
+		if err := s.tx.Unscoped().Model(c.model).Where(c.query, c.args...).Pluck(c.field, &taken).Error; err != nil {
+			return err
+		}
+		for _, id := range taken {
+			s.taken[id] = true
+		}
+	}
+	return nil
+}
+
+// requestIDList 返回集合中已有请求的标识，集合为空时返回占位值以保证 NOT IN 语句有效
+func (s *saver) requestIDList() []string {
+	ids := make([]string, 0, len(s.requests))
+	for id := range s.requests {
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		ids = append(ids, "")
+	}
+	return ids
+}
+
+// keepID 导入文件中的标识未被占用时沿用，否则生成新标识
+func (s *saver) keepID(id string) string {
+	if id == "" || s.taken[id] {
+		return uid.NewUUID()
+	}
+	return id
+}
+
+// saveItems 递归写入文件夹和请求
+func (s *saver) saveItems(collectionID, parentID string, folders []*Folder, requests []*Request) error {
+	for _, f := range folders {
+		folderID, err := s.ensureFolder(collectionID, parentID, f)
+		if err != nil {
+			return err
+		}
+		if err := s.saveItems(collectionID, folderID, f.Folders, f.Requests); err != nil {
+			return err
+		}
+	}
+	for _, r := range requests {
+		if err := s.saveRequest(collectionID, parentID, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureFolder 优先按标识、其次按同一父目录下的名称复用文件夹，不存在时创建
+func (s *saver) ensureFolder(collectionID, parentID string, f *Folder) (string, error) {
+	if f.FolderID != "" && s.folderIDs[f.FolderID] {
+		if s.opts.Conflict == ConflictOverwrite {
+			updates := map[string]interface{}{"name": f.Name}
+			if d := f.Defaults; d != nil {
+				updates["default_base_url"] = d.BaseURL
+				updates["default_headers"] = d.Headers
+				updates["default_auth"] = d.Auth
+				updates["default_timeout"] = d.Timeout
+				updates["default_retry_count"] = d.RetryCount
+			}
+			if f.Tags != nil {
+				updates["tags"] = model.EncodeTags(f.Tags)
+			}
+			if f.SortOrder != nil {
+				updates["sort_order"] = *f.SortOrder
+			}
+			if err := s.tx.Model(&model.Folder{}).Where("folder_id = ?", f.FolderID).Updates(updates).Error; err != nil {
+				return "", err
+			}
+		}
+		return f.FolderID, nil
+	}
+
+	key := parentID + "/" + f.Name
+	if id, ok := s.folders[key]; ok && f.FolderID == "" {
+		return id, nil
+	}
+
+	folder := model.Folder{
+		CollectionID: collectionID,
+		Name:         f.Name,
+		FolderID:     s.keepID(f.FolderID),
+	}
+	if f.Defaults != nil {
+		folder.Defaults = *f.Defaults
+	}
+	folder.Tags = model.EncodeTags(f.Tags)
+	if err := service.CreateFolder(s.tx, &folder, parentID); err != nil {
+		return "", err
+	}
+	// CreateFolder 把 0 视为未指定，导入的排序值在创建后写回
+	if f.SortOrder != nil && *f.SortOrder != folder.SortOrder {
+		if err := s.tx.Model(&folder).Update("sort_order", *f.SortOrder).Error; err != nil {
+			return "", err
+		}
+	}
+	s.folders[key] = folder.FolderID
+	s.folderIDs[folder.FolderID] = true
+	s.report.FoldersCreated++
+	s.report.add("folder", f.Name, folder.FolderID, ActionCreated)
+	return folder.FolderID, nil
+}
+
+// saveRequest 按冲突策略写入单个请求，优先按标识匹配，其次按来源标识
+func (s *saver) saveRequest(collectionID, folderID string, r *Request) error {
+	requestType := r.Type
+	if requestType == "" {
+		requestType = model.HTTP1
+	}
+	row := model.Request{
+		Name:         r.Name,
+		CollectionID: collectionID,
+		FolderID:     folderID,
+		Method:       r.Method,
+		Path:         r.Path,
+		Type:         requestType,
+		Transport:    r.Transport,
+		Headers:      model.EncodeKeyValues(r.Headers),
+		Body:         r.Body,
+		Query:        r.Query,
+		Variables:    r.Variables,
+		Assertions:   r.Assertions,
+		QueryParams:  model.EncodeKeyValues(r.QueryParams),
+		Auth:         model.EncodeAuth(r.Auth),
+		Timeout:      r.Timeout,
+		RetryCount:   r.RetryCount,
+		Priority:     r.Priority,
+		Description:  r.Description,
+		Tags:         model.EncodeTags(r.Tags),
+		SourceKey:    r.SourceKey,
+	}
+
+	existing := s.requests[r.RequestID]
+	if existing == nil && r.SourceKey != "" {
+		existing = s.existing[r.SourceKey]
+	}
+
+	if existing != nil {
+		switch s.opts.Conflict {
+		case ConflictSkip:
+			s.report.add("request", r.Name, existing.RequestID, ActionSkipped)
+			return nil
+		case ConflictDuplicate:
+			// 副本不参与后续的重复导入匹配
+			row.SourceKey = ""
+			row.RequestID = uid.NewUUID()
+		default:
+			// 未提供排序值时保持原位，移动到其他文件夹时排在同级末尾
+			sortOrder := existing.SortOrder
+			if r.SortOrder != nil {
+				sortOrder = *r.SortOrder
+			} else if existing.FolderID != row.FolderID {
+				order, err := service.NextSortOrder(s.tx, &model.Request{}, collectionID, folderID)
+				if err != nil {
+					return err
+				}
+				sortOrder = order
+			}
+			err := s.tx.Model(&model.Request{}).Where("id = ?", existing.ID).Updates(map[string]interface{}{
+				"name":         row.Name,
+				"folder_id":    row.FolderID,
+				"method":       row.Method,
+				"path":         row.Path,
+				"type":         row.Type,
+				"transport":    row.Transport,
+				"headers":      row.Headers,
+				"body":         row.Body,
+				"query":        row.Query,
+				"variables":    row.Variables,
+				"assertions":   row.Assertions,
+				"query_params": row.QueryParams,
+				"auth":         row.Auth,
+				"timeout":      row.Timeout,
+				"retry_count":  row.RetryCount,
+				"priority":     row.Priority,
+				"description":  row.Description,
+				"tags":         row.Tags,
+				"sort_order":   sortOrder,
+			}).Error
+			if err != nil {
+				return err
+			}
+			var updated model.Request
+			if err := s.tx.Where("id = ?", existing.ID).First(&updated).Error; err != nil {
+				return err
+			}
+			if _, err := service.RecordRevision(s.tx, &updated, s.opts.OwnerID, model.RevisionUpdate); err != nil {
+				return err
+			}
+			if err := s.saveExamples(existing.RequestID, r.Examples); err != nil {
+				return err
+			}
+			s.report.add("request", r.Name, existing.RequestID, ActionUpdated)
+			return nil
+		}
+	}
+
+	if row.RequestID == "" {
+		row.RequestID = s.keepID(r.RequestID)
+	}
+	if r.SortOrder != nil {
+		row.SortOrder = *r.SortOrder
+	} else {
+		order, err := service.NextSortOrder(s.tx, &model.Request{}, collectionID, folderID)
+		if err != nil {
+			return err
+		}
+		row.SortOrder = order
+	}
+	if err := s.tx.Create(&row).Error; err != nil {
+		return err
+	}
+	if _, err := service.RecordRevision(s.tx, &row, s.opts.OwnerID, model.RevisionCreate); err != nil {
+		return err
+	}
+	s.requests[row.RequestID] = &row
+	if row.SourceKey != "" {
+		s.existing[row.SourceKey] = &row
+	}
+	if err := s.saveExamples(row.RequestID, r.Examples); err != nil {
+		return err
+	}
+	s.report.add("request", r.Name, row.RequestID, ActionCreated)
+	return nil
+}
+
+// saveExamples 用导入的示例替换请求已有的示例，未提供示例时保持不变
+func (s *saver) saveExamples(requestID string, examples []*Example) error {
+	if len(examples) == 0 {
+		return nil
+	}
+	if err := s.tx.Where("request_id = ?", requestID).Delete(&model.RequestExample{}).Error; err != nil {
+		return err
+	}
+
+	rows := make([]model.RequestExample, 0, len(examples))
+	for _, e := range examples {
+		rows = append(rows, model.RequestExample{
+			ExampleID:   s.keepID(e.ExampleID),
+			RequestID:   requestID,
+			Name:        e.Name,
+			Status:      e.Status,
+			StatusText:  e.StatusText,
+			ContentType: e.ContentType,
+			Headers:     model.EncodeKeyValues(e.Headers),
+			Body:        e.Body,
+		})
+	}
+	return s.tx.Create(&rows).Error
+}
+
+// saveEnvironments 写入集合级环境变量，按标识或同名匹配已有环境并按冲突策略处理
+func (s *saver) saveEnvironments(collection *model.Collections, envs []*Environment) error {
+	for _, env := range envs {
+		var existing model.Environment
+		err := gorm.ErrRecordNotFound
+		if env.EnvironmentID != "" {
+			err = s.tx.Where("collection_id = ? AND environment_id = ?", collection.CollectionID, env.EnvironmentID).First(&existing).Error
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = s.tx.Where("collection_id = ? AND name = ?", collection.CollectionID, env.Name).First(&existing).Error
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		variables := model.EncodeKeyValues(env.Variables)
+		if err == nil && s.opts.Conflict != ConflictDuplicate {
+			if s.opts.Conflict == ConflictSkip {
+				s.report.add("environment", env.Name, existing.EnvironmentID, ActionSkipped)
+				continue
+			}
+			updates := map[string]interface{}{"name": env.Name, "variables": variables}
+			if err := s.tx.Model(&existing).Updates(updates).Error; err != nil {
+				return err
+			}
+			s.report.Environments++
+			s.report.add("environment", env.Name, existing.EnvironmentID, ActionUpdated)
+			continue
+		}
+
+		environmentID := s.keepID(env.EnvironmentID)
+		if err == nil {
+			environmentID = uid.NewUUID()
+		}
+		row := model.Environment{
+			EnvironmentID: environmentID,
+			WorkspaceID:   collection.WorkspaceID,
+			CollectionID:  collection.CollectionID,
+			Name:          env.Name,
+			Variables:     variables,
+		}
+		if err := s.tx.Create(&row).Error; err != nil {
+			return err
+		}
+		s.report.Environments++
+		s.report.add("environment", env.Name, row.EnvironmentID, ActionCreated)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package importer
+
+import (
+	"FastGo/internal/model"
+	"FastGo/internal/service"
+	"FastGo/internal/service/servicetest"
+	"testing"
+	"time"
+)
+
+func nativeCollection() *Collection {
+	return &Collection{
+		CollectionID: "c1",
+		Name:         "Pets",
+		Folders: []*Folder{{
+			FolderID: "f1",
+			Name:     "pets",
+			Requests: []*Request{{
+				RequestID: "r1",
+				Name:      "List",
+				Method:    "GET",
+				Path:      "/pets",
+				Examples:  []*Example{{ExampleID: "e1", Name: "ok", Status: 200}},
+			}},
+		}},
+		Environments: []*Environment{{EnvironmentID: "env1", Name: "dev"}},
+	}
+}
+
+// 回收站中的记录仍占用标识，重新导入时需要生成新标识，永久删除回收站时不能删掉导入的记录
+func TestSaveAfterTrashKeepsIDsUnique(t *testing.T) {
+	db := servicetest.NewDB(t)
+	workspace := model.Workspace{Name: "Team", OwnerID: 1}
+	if err := db.Create(&workspace).Error; err != nil {
+		t.Fatal(err)
+	}
+	opts := Options{WorkspaceID: workspace.ID, OwnerID: 1, Conflict: ConflictOverwrite}
+	if _, err := Save(db, nativeCollection(), opts); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.DeleteCollections(db, []string{"c1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Save(db, nativeCollection(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cid := report.CollectionID
+	if cid == "c1" {
+		t.Fatal("expected a fresh collection ID")
+	}
+	var folder model.Folder
+	var request model.Request
+	if err := db.Where("collection_id = ?", cid).First(&folder).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Where("collection_id = ?", cid).First(&request).Error; err != nil {
+		t.Fatal(err)
+	}
+	if folder.FolderID == "f1" || request.RequestID == "r1" || request.FolderID != folder.FolderID {
+		t.Fatalf("expected fresh IDs, got folder %q request %q in %q", folder.FolderID, request.RequestID, request.FolderID)
+	}
+
+	if _, err := service.PurgeTrash(db, time.Now().Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	counts := []struct {
+		name  string
+		table interface{}
+		query string
+		arg   string
+	}{
+		{"collection", &model.Collections{}, "collection_id = ?", cid},
+		{"folder", &model.Folder{}, "folder_id = ?", folder.FolderID},
+		{"closure", &model.FolderClosure{}, "descendant = ?", folder.FolderID},
+		{"request", &model.Request{}, "request_id = ?", request.RequestID},
+		{"example", &model.RequestExample{}, "request_id = ?", request.RequestID},
+		{"environment", &model.Environment{}, "collection_id = ?", cid},
+	}
+	for _, c := range counts {
+		var n int64
+		if err := db.Model(c.table).Where(c.query, c.arg).Count(&n).Error; err != nil {
+			t.Fatal(err)
+		}
+		if n != 1 {
+			t.Errorf("expected 1 %s after purge, got %d", c.name, n)
+		}
+	}
+	var trashed int64
+	if err := db.Unscoped().Model(&model.Request{}).Where("request_id = ?", "r1").Count(&trashed).Error; err != nil {
+		t.Fatal(err)
+	}
+	if trashed != 0 {
+		t.Errorf("expected the trashed request to be purged, got %d", trashed)
+	}
+}
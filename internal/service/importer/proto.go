@@ -0,0 +1,68 @@
+package importer
+
+import (
+	"FastGo/internal/model"
+	"FastGo/internal/service/protoschema"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// FromProto 为 proto 中的服务生成请求骨架：每个服务一个文件夹，每个方法一个 gRPC 请求
+// services 为空时生成全部服务；target 为空时使用 {{grpcHost}} 变量
+func FromProto(files *protoregistry.Files, target string, services []string) (*Collection, error) {
+	if target == "" {
+		target = "{{grpcHost}}"
+	}
+	wanted := map[string]bool{}
+	for _, s := range services {
+		wanted[s] = true
+	}
+
+	col := &Collection{Protocol: model.GRPC}
+	for _, svc := range protoschema.Services(files) {
+		if len(wanted) > 0 && !wanted[svc.Name] {
+			continue
+		}
+		sd, err := protoschema.FindService(files, svc.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		folder := &Folder{Name: svc.Name}
+		methods := sd.Methods()
+		for i := 0; i < methods.Len(); i++ {
+			md := methods.Get(i)
+			m := svc.Methods[i]
+
+			description := []string{m.Input + " → " + m.Output}
+			switch {
+			case m.ClientStreaming && m.ServerStreaming:
+				description = append(description, "bidirectional streaming")
+			case m.ClientStreaming:
+				description = append(description, "client streaming")
+			case m.ServerStreaming:
+				description = append(description, "server streaming")
+			}
+			for _, hint := range protoschema.OneofHints(md.Input()) {
+				description = append(description, "oneof "+hint)
+			}
+
+			folder.Requests = append(folder.Requests, &Request{
+				SourceKey:   "proto:" + m.FullName,
+				Name:        m.Name,
+				Type:        model.GRPC1,
+				Method:      model.POST,
+				Path:        strings.TrimSuffix(target, "/") + "/" + m.FullName,
+				Body:        protoschema.Skeleton(md.Input()),
+				Description: strings.Join(description, "\n"),
+			})
+		}
+		col.Folders = append(col.Folders, folder)
+	}
+
+	if len(col.Folders) == 0 {
+		return nil, protoschema.ErrNoServices
+	}
+	return col, nil
+}
@@ -0,0 +1,153 @@
+package importer
+
+import "FastGo/internal/model"
+
+// Workspace 导入得到的工作区，包含一个或多个集合
+type Workspace struct {
+	Name        string
+	Collections []*Collection
+}
+
+// Collection 导入得到的集合结构，与具体来源格式无关
+type Collection struct {
+	CollectionID string // 原生格式导入时保留的标识，其他格式为空
+	Name         string
+	Description  string
+	Protocol     model.CollectionType
+	Defaults     *model.Defaults // 请求默认值，原生格式导入时保留，为空表示不修改
+	Tags         []string        // 标签，原生格式导入时保留，nil 表示不修改
+	Folders      []*Folder
+	Requests     []*Request
+	Environments []*Environment
+}
+
+// Folder 导入得到的文件夹，可嵌套
+type Folder struct {
+	FolderID  string // 原生格式导入时保留的标识
+	Name      string
+	SortOrder *int            // 同级排序值，原生格式导入时保留，为空时排在同级末尾
+	Defaults  *model.Defaults // 请求默认值，原生格式导入时保留，为空表示不修改
+	Tags      []string        // 标签，原生格式导入时保留，nil 表示不修改
+	Folders   []*Folder
+	Requests  []*Request
+}
+
+// Request 导入得到的请求
+type Request struct {
+	RequestID   string // 原生格式导入时保留的标识
+	SourceKey   string // 来源中的唯一标识，重复导入时用于匹配
+	Name        string
+	SortOrder   *int // 同级排序值，原生格式导入时保留，为空时排在同级末尾
+	Type        model.RequestType
+	Method      model.RequestMethod
+	Path        string
+	Transport   string // gRPC 传输方式
+	Headers     []model.KeyValue
+	QueryParams []model.KeyValue
+	Auth        *model.Auth // 认证设置，为空表示继承
+	Body        string
+	Query       string // GraphQL 查询语句
+	Variables   string // GraphQL 变量
+	Assertions  string // 断言列表 JSON
+	Timeout     int
+	RetryCount  int
+	Priority    int
+	Description string
+	Tags        []string
+	Examples    []*Example
+}
+
+// Example 导入得到的响应示例
+type Example struct {
+	ExampleID   string // 原生格式导入时保留的标识
+	Name        string
+	Status      int
+	StatusText  string
+	ContentType string
+	Headers     []model.KeyValue
+	Body        string
+}
+
+// Environment 导入得到的环境变量
+type Environment struct {
+	EnvironmentID string // 原生格式导入时保留的标识
+	Name          string
+	Variables     []model.KeyValue
+}
+
+// ConflictStrategy 重复导入时的冲突处理策略
+type ConflictStrategy string
+
+const (
+	ConflictOverwrite ConflictStrategy = "overwrite" // 覆盖已有请求
+	ConflictSkip      ConflictStrategy = "skip"      // 保留已有请求
+	ConflictDuplicate ConflictStrategy = "duplicate" // 另存为新请求
+)
+
+// ParseConflictStrategy 解析冲突策略，默认覆盖
+func ParseConflictStrategy(s string) ConflictStrategy {
+	switch ConflictStrategy(s) {
+	case ConflictSkip, ConflictDuplicate:
+		return ConflictStrategy(s)
+	default:
+		return ConflictOverwrite
+	}
+}
+
+// Options 导入选项
+type Options struct {
+	WorkspaceID  uint64
+	OwnerID      uint64
+	CollectionID string // 目标集合，为空时按名称匹配工作区内的集合，仍找不到则新建
+	Conflict     ConflictStrategy
+}
+
+// 导入明细中的动作
+const (
+	ActionCreated = "created"
+	ActionUpdated = "updated"
+	ActionSkipped = "skipped"
+)
+
+// ReportItem 导入明细
+type ReportItem struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	ID     string `json:"id"`
+	Action string `json:"action"`
+}
+
+// WorkspaceReport 多集合导入的结果报告
+type WorkspaceReport struct {
+	WorkspaceID string    `json:"workspace_id"`
+	Collections []*Report `json:"collections"`
+	Warnings    []string  `json:"warnings"`
+}
+
+// Report 导入结果报告
+type Report struct {
+	CollectionID   string       `json:"collection_id"`
+	Created        int          `json:"created"`
+	Updated        int          `json:"updated"`
+	Skipped        int          `json:"skipped"`
+	FoldersCreated int          `json:"folders_created"`
+	Environments   int          `json:"environments"`
+	Items          []ReportItem `json:"items"`
+	Warnings       []string     `json:"warnings"`
+}
+
+// add 记录一条明细，计数只统计请求
+func (r *Report) add(kind, name, id, action string) {
+	r.Items = append(r.Items, ReportItem{Kind: kind, Name: name, ID: id, Action: action})
+	if kind != "request" {
+		return
+	}
+	switch action {
+	case ActionCreated:
+		r.Created++
+	case ActionUpdated:
+		r.Updated++
+	case ActionSkipped:
+		r.Skipped++
+	}
+}
@@ -0,0 +1,238 @@
+package invoke
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 断言运算符
+const (
+	OpEqual       = "eq"
+	OpNotEqual    = "neq"
+	OpExists      = "exists"
+	OpNotExists   = "not_exists"
+	OpContains    = "contains"
+	OpGreater     = "gt"
+	OpGreaterOrEq = "gte"
+	OpLess        = "lt"
+	OpLessOrEq    = "lte"
+	OpMatches     = "matches"
+)
+
+// Assertion 对发送结果的断言
+// Target 为点分路径，可用的根节点：status、duration、headers、body，
+// GraphQL 请求另有 data、errors，JSON-RPC 请求另有 result、error（第一个调用）和 responses（全部调用），
+// gRPC 请求另有 trailers、details（状态详情）
+type Assertion struct {
+	Target   string      `json:"target"`
+	Operator string      `json:"operator"`
+	Value    interface{} `json:"value,omitempty"`
+}
+
+// AssertionResult 断言结果
+type AssertionResult struct {
+	Assertion
+	Passed  bool        `json:"passed"`
+	Actual  interface{} `json:"actual,omitempty"`
+	Message string      `json:"message,omitempty"`
+}
+
+// ParseAssertions 解析请求中保存的断言列表
+func ParseAssertions(s string) ([]Assertion, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var assertions []Assertion
+	if err := json.Unmarshal([]byte(s), &assertions); err != nil {
+		return nil, fmt.Errorf("invalid assertions: %w", err)
+	}
+	return assertions, nil
+}
+
+// Assert 对结果逐条求值
+func Assert(result *Result, assertions []Assertion) []AssertionResult {
+	if len(assertions) == 0 {
+		return nil
+	}
+	doc := assertionDocument(result)
+	results := make([]AssertionResult, 0, len(assertions))
+	for _, a := range assertions {
+		actual, found := lookup(doc, a.Target)
+		r := AssertionResult{Assertion: a, Actual: actual}
+		r.Passed, r.Message = evaluate(a, actual, found)
+		results = append(results, r)
+	}
+	return results
+}
+
+// assertionDocument 将结果转换为可按路径查询的文档
+func assertionDocument(result *Result) map[string]interface{} {
+	headers := map[string]interface{}{}
+	for _, h := range result.Headers {
+		headers[strings.ToLower(h.Key)] = h.Value
+	}
+	doc := map[string]interface{}{
+		"status":   float64(result.StatusCode),
+		"duration": float64(result.Duration),
+		"headers":  headers,
+		"body":     decodeJSON([]byte(result.Body), result.Body),
+	}
+	if gql := result.GraphQL; gql != nil {
+		doc["data"] = decodeJSON(gql.Data, nil)
+		doc["errors"] = toGeneric(gql.Errors)
+	}
+	if rpc := result.GRPC; rpc != nil {
+		trailers := map[string]interface{}{}
+		for _, t := range rpc.Trailers {
+			trailers[strings.ToLower(t.Key)] = t.Value
+		}
+		doc["trailers"] = trailers
+		doc["details"] = toGeneric(rpc.Details)
+	}
+	if rpc := result.JSONRPC; rpc != nil {
+		responses := toGeneric(rpc.Responses)
+		doc["responses"] = responses
+		if list, ok := responses.([]interface{}); ok && len(list) > 0 {
+			if first, ok := list[0].(map[string]interface{}); ok {
+				doc["result"] = first["result"]
+				doc["error"] = first["error"]
+			}
+		}
+	}
+	return doc
+}
+
+func decodeJSON(data []byte, fallback interface{}) interface{} {
+	var v interface{}
+	if len(data) == 0 || json.Unmarshal(data, &v) != nil {
+		return fallback
+	}
+	return v
+}
+
+func toGeneric(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return decodeJSON(data, nil)
+}
+
+// lookup 按点分路径取值，数组使用数字下标，如 responses.1.error.code
+func lookup(doc map[string]interface{}, path string) (interface{}, bool) {
+	var cur interface{} = doc
+	for _, part := range strings.Split(path, ".") {
+		switch node := cur.(type) {
+		case map[string]interface{}:
+			v, ok := node[part]
+			if !ok {
+				// 响应头不区分大小写
+				v, ok = node[strings.ToLower(part)]
+			}
+			if !ok {
+				return nil, false
+			}
+			cur = v
+		case []interface{}:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			cur = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return cur, cur != nil
+}
+
+func evaluate(a Assertion, actual interface{}, found bool) (bool, string) {
+	switch a.Operator {
+	case OpExists:
+		return found, ""
+	case OpNotExists:
+		return !found, ""
+	}
+	if !found && a.Operator != OpNotEqual {
+		return false, "target not found"
+	}
+
+	switch a.Operator {
+	case OpEqual:
+		return equal(actual, a.Value), ""
+	case OpNotEqual:
+		return !equal(actual, a.Value), ""
+	case OpContains:
+		switch v := actual.(type) {
+		case string:
+			return strings.Contains(v, fmt.Sprint(a.Value)), ""
+		case []interface{}:
+			for _, item := range v {
+				if equal(item, a.Value) {
+					return true, ""
+				}
+			}
+			return false, ""
+		case map[string]interface{}:
+			_, ok := v[fmt.Sprint(a.Value)]
+			return ok, ""
+		}
+		return false, "contains requires a string, array or object"
+	case OpGreater, OpGreaterOrEq, OpLess, OpLessOrEq:
+		x, ok1 := toNumber(actual)
+		y, ok2 := toNumber(a.Value)
+		if !ok1 || !ok2 {
+			return false, "comparison requires numbers"
+		}
+		switch a.Operator {
+		case OpGreater:
+			return x > y, ""
+		case OpGreaterOrEq:
+			return x >= y, ""
+		case OpLess:
+			return x < y, ""
+		}
+		return x <= y, ""
+	case OpMatches:
+		re, err := regexp.Compile(fmt.Sprint(a.Value))
+		if err != nil {
+			return false, "invalid pattern: " + err.Error()
+		}
+		s, ok := actual.(string)
+		if !ok {
+			s = fmt.Sprint(actual)
+		}
+		return re.MatchString(s), ""
+	}
+	return false, "unknown operator " + a.Operator
+}
+
+// equal 比较 JSON 值，数字与数字字符串视为相等
+func equal(actual, expected interface{}) bool {
+	if x, ok := toNumber(actual); ok {
+		if y, ok := toNumber(expected); ok {
+			return x == y
+		}
+	}
+	return reflect.DeepEqual(toGeneric(actual), toGeneric(expected))
+}
+
+func toNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,47 @@
+package invoke
+
+import (
+	"FastGo/internal/model"
+	"encoding/base64"
+	"strings"
+)
+
+// applyAuth 替换认证设置中的变量，并写入请求头或查询参数
+// 已有启用的同名请求头或查询参数时以请求中的为准，处理后清空 Auth
+func applyAuth(req *model.Request, vars map[string]string) {
+	auth := model.DecodeAuth(req.Auth)
+	req.Auth = ""
+
+	var kv model.KeyValue
+	switch auth.Type {
+	case model.AuthBearer:
+		kv = model.KeyValue{Key: "Authorization", Value: "Bearer " + ExpandString(auth.Token, vars)}
+	case model.AuthBasic:
+		credentials := ExpandString(auth.Username, vars) + ":" + ExpandString(auth.Password, vars)
+		kv = model.KeyValue{Key: "Authorization", Value: "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))}
+	case model.AuthAPIKey:
+		kv = model.KeyValue{Key: ExpandString(auth.Key, vars), Value: ExpandString(auth.Value, vars)}
+	default:
+		return
+	}
+	if kv.Key == "" {
+		return
+	}
+	kv.Enabled = true
+
+	if auth.Type == model.AuthAPIKey && strings.EqualFold(auth.In, "query") {
+		params := model.DecodeKeyValues(req.QueryParams)
+		for _, p := range params {
+			if p.Enabled && p.Key == kv.Key {
+				return
+			}
+		}
+		req.QueryParams = model.EncodeKeyValues(append(params, kv))
+		return
+	}
+	headers := model.DecodeKeyValues(req.Headers)
+	if hasEnabledHeader(headers, kv.Key) {
+		return
+	}
+	req.Headers = model.EncodeKeyValues(append(headers, kv))
+}
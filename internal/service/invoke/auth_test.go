@@ -0,0 +1,30 @@
+package invoke
+
+import (
+	"FastGo/internal/model"
+	"testing"
+)
+
+func TestExpandAuth(t *testing.T) {
+	req := model.Request{Auth: model.EncodeAuth(&model.Auth{Type: model.AuthBasic, Username: "{{user}}", Password: "secret"})}
+	sent := Expand(req, map[string]string{"user": "alice"})
+	headers := model.DecodeKeyValues(sent.Headers)
+	if len(headers) != 1 || headers[0].Value != "Basic YWxpY2U6c2VjcmV0" || sent.Auth != "" {
+		t.Fatalf("unexpected headers %+v", headers)
+	}
+
+	req = model.Request{
+		Headers: model.EncodeKeyValues([]model.KeyValue{{Key: "authorization", Value: "Bearer explicit", Enabled: true}}),
+		Auth:    model.EncodeAuth(&model.Auth{Type: model.AuthBearer, Token: "inherited"}),
+	}
+	headers = model.DecodeKeyValues(Expand(req, nil).Headers)
+	if len(headers) != 1 || headers[0].Value != "Bearer explicit" {
+		t.Fatalf("explicit header should win, got %+v", headers)
+	}
+
+	req = model.Request{Auth: model.EncodeAuth(&model.Auth{Type: model.AuthAPIKey, Key: "api_key", Value: "k", In: "query"})}
+	params := model.DecodeKeyValues(Expand(req, nil).QueryParams)
+	if len(params) != 1 || params[0].Key != "api_key" {
+		t.Fatalf("unexpected query params %+v", params)
+	}
+}
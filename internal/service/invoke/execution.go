@@ -0,0 +1,37 @@
+package invoke
+
+import (
+	"FastGo/internal/model"
+	"FastGo/pkg/uid"
+	"encoding/json"
+)
+
+// NewExecution 生成发送记录，transcript 为流式请求的消息列表，可为空
+func NewExecution(req *model.Request, userID uint64, result *Result, err error, transcript interface{}) *model.Execution {
+	execution := &model.Execution{
+		ExecutionID: uid.NewUUID(),
+		RequestID:   req.RequestID,
+		UserID:      userID,
+		Type:        req.Type,
+		Success:     err == nil,
+	}
+	if snapshot, e := json.Marshal(req); e == nil {
+		execution.Request = string(snapshot)
+	}
+	if result != nil {
+		execution.StatusCode = result.StatusCode
+		execution.Duration = result.Duration
+		if data, e := json.Marshal(result); e == nil {
+			execution.Response = string(data)
+		}
+	}
+	if err != nil {
+		execution.Error = err.Error()
+	}
+	if transcript != nil {
+		if data, e := json.Marshal(transcript); e == nil {
+			execution.Transcript = string(data)
+		}
+	}
+	return execution
+}
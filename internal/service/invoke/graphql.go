@@ -0,0 +1,171 @@
+package invoke
+
+import (
+	"FastGo/internal/model"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2"
+)
+
+// ErrQueryValidation 查询未通过 schema 校验，未发送
+var ErrQueryValidation = errors.New("graphql query validation failed")
+
+// GraphQLResult GraphQL 响应，errors 与 data 分开展示
+type GraphQLResult struct {
+	Data       json.RawMessage        `json:"data,omitempty"`
+	Errors     []GraphQLError         `json:"errors,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+	Validated  bool                   `json:"validated"` // 发送前是否已按 schema 校验
+}
+
+// GraphQLError GraphQL 错误，校验错误与服务端错误格式一致
+type GraphQLError struct {
+	Message    string                 `json:"message"`
+	Locations  []GraphQLLocation      `json:"locations,omitempty"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// GraphQLLocation 错误位置
+type GraphQLLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// GraphQLInvoker 发送 GraphQL 请求，发送前按缓存的内省结果校验查询
+type GraphQLInvoker struct {
+	Client  *http.Client
+	Schemas *SchemaCache
+}
+
+func (i *GraphQLInvoker) Invoke(ctx context.Context, req *model.Request) (*Result, error) {
+	endpoint := requestURL(req)
+	headers := graphQLHeaders(req)
+
+	payload := map[string]interface{}{"query": req.Query}
+	if strings.TrimSpace(req.Variables) != "" {
+		var variables map[string]interface{}
+		if err := json.Unmarshal([]byte(req.Variables), &variables); err != nil {
+			return nil, fmt.Errorf("variables must be a JSON object: %w", err)
+		}
+		payload["variables"] = variables
+	}
+
+	gql := &GraphQLResult{}
+	var warnings []string
+	if introspection, err := i.Schema(ctx, endpoint, headers, false); err != nil {
+		warnings = append(warnings, "schema introspection failed, query not validated: "+err.Error())
+	} else if errs, err := validateQuery(introspection, req.Query); err != nil {
+		warnings = append(warnings, "cached schema is invalid, query not validated: "+err.Error())
+	} else if len(errs) > 0 {
+		gql.Errors = errs
+		return &Result{Status: "validation failed", GraphQL: gql}, ErrQueryValidation
+	} else {
+		gql.Validated = true
+	}
+
+	body, _ := json.Marshal(payload)
+	result, err := doHTTP(ctx, i.Client, http.MethodPost, endpoint, headers, string(body))
+	if err != nil {
+		return nil, err
+	}
+	result.Warnings = warnings
+	result.GraphQL = gql
+
+	var resp struct {
+		Data       json.RawMessage        `json:"data"`
+		Errors     []GraphQLError         `json:"errors"`
+		Extensions map[string]interface{} `json:"extensions"`
+	}
+	if err := json.Unmarshal([]byte(result.Body), &resp); err != nil {
+		result.Warnings = append(result.Warnings, "response is not a GraphQL JSON document")
+		return result, nil
+	}
+	gql.Data, gql.Errors, gql.Extensions = resp.Data, resp.Errors, resp.Extensions
+	return result, nil
+}
+
+// Schema 返回地址的内省结果，refresh 为 true 或未缓存时重新内省
+func (i *GraphQLInvoker) Schema(ctx context.Context, endpoint string, headers []model.KeyValue, refresh bool) (json.RawMessage, error) {
+	if !refresh {
+		if cached, ok := i.Schemas.Get(ctx, endpoint); ok {
+			return cached, nil
+		}
+	}
+
+	body, _ := json.Marshal(map[string]string{"query": IntrospectionQuery})
+	result, err := doHTTP(ctx, i.Client, http.MethodPost, endpoint, headers, string(body))
+	if err != nil {
+		return nil, err
+	}
+	if result.StatusCode >= 300 {
+		return nil, fmt.Errorf("introspection returned %s", result.Status)
+	}
+
+	var resp struct {
+		Data struct {
+			Schema json.RawMessage `json:"__schema"`
+		} `json:"data"`
+		Errors []GraphQLError `json:"errors"`
+	}
+	if err := json.Unmarshal([]byte(result.Body), &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Errors) > 0 {
+		return nil, errors.New(resp.Errors[0].Message)
+	}
+	if len(resp.Data.Schema) == 0 || string(resp.Data.Schema) == "null" {
+		return nil, errors.New("introspection returned no schema")
+	}
+	if _, err := ParseIntrospection(resp.Data.Schema); err != nil {
+		return nil, err
+	}
+
+	// 缓存不可用时不影响本次发送
+	_ = i.Schemas.Set(ctx, endpoint, resp.Data.Schema)
+	return resp.Data.Schema, nil
+}
+
+// GraphQLEndpoint 返回请求变量替换后的地址和请求头，用于内省
+func GraphQLEndpoint(req model.Request, vars map[string]string) (string, []model.KeyValue) {
+	req = Expand(req, vars)
+	return requestURL(&req), graphQLHeaders(&req)
+}
+
+// validateQuery 按内省结果校验查询，返回校验错误
+func validateQuery(introspection json.RawMessage, query string) ([]GraphQLError, error) {
+	schema, err := ParseIntrospection(introspection)
+	if err != nil {
+		return nil, err
+	}
+	_, list := gqlparser.LoadQuery(schema, query)
+	if len(list) == 0 {
+		return nil, nil
+	}
+	errs := make([]GraphQLError, 0, len(list))
+	for _, e := range list {
+		ge := GraphQLError{Message: e.Message, Extensions: map[string]interface{}{"source": "validation"}}
+		for _, l := range e.Locations {
+			ge.Locations = append(ge.Locations, GraphQLLocation{Line: l.Line, Column: l.Column})
+		}
+		if e.Rule != "" {
+			ge.Extensions["rule"] = e.Rule
+		}
+		errs = append(errs, ge)
+	}
+	return errs, nil
+}
+
+// graphQLHeaders 补充 JSON 内容类型
+func graphQLHeaders(req *model.Request) []model.KeyValue {
+	headers := model.DecodeKeyValues(req.Headers)
+	if hasEnabledHeader(headers, "Content-Type") {
+		return headers
+	}
+	return append(headers, model.KeyValue{Key: "Content-Type", Value: "application/json", Enabled: true})
+}
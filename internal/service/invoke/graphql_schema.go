@@ -0,0 +1,241 @@
+package invoke
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// 内省结果缓存时间
+const schemaCacheTTL = time.Hour
+
+// IntrospectionQuery 标准内省查询，不含描述信息
+const IntrospectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types { ...FullType }
+    directives { name locations args { ...InputValue } }
+  }
+}
+fragment FullType on __Type {
+  kind name
+  fields(includeDeprecated: true) { name args { ...InputValue } type { ...TypeRef } }
+  inputFields { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) { name }
+  possibleTypes { ...TypeRef }
+}
+fragment InputValue on __InputValue { name type { ...TypeRef } defaultValue }
+fragment TypeRef on __Type {
+  kind name
+  ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } } } } }
+}`
+
+// SchemaCache 按地址缓存内省结果
+type SchemaCache struct {
+	rdb *redis.Client
+}
+
+// NewSchemaCache 创建内省缓存，rdb 为空时不缓存
+func NewSchemaCache(rdb *redis.Client) *SchemaCache {
+	return &SchemaCache{rdb: rdb}
+}
+
+func schemaCacheKey(endpoint string) string {
+	sum := sha1.Sum([]byte(endpoint))
+	return "graphql:schema:" + hex.EncodeToString(sum[:])
+}
+
+// Get 读取缓存的内省结果
+func (c *SchemaCache) Get(ctx context.Context, endpoint string) (json.RawMessage, bool) {
+	if c == nil || c.rdb == nil {
+		return nil, false
+	}
+	data, err := c.rdb.Get(ctx, schemaCacheKey(endpoint)).Bytes()
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// Set 缓存内省结果
+func (c *SchemaCache) Set(ctx context.Context, endpoint string, introspection json.RawMessage) error {
+	if c == nil || c.rdb == nil {
+		return nil
+	}
+	return c.rdb.Set(ctx, schemaCacheKey(endpoint), []byte(introspection), schemaCacheTTL).Err()
+}
+
+// Delete 清除缓存
+func (c *SchemaCache) Delete(ctx context.Context, endpoint string) error {
+	if c == nil || c.rdb == nil {
+		return nil
+	}
+	return c.rdb.Del(ctx, schemaCacheKey(endpoint)).Err()
+}
+
+type introspectionTypeRef struct {
+	Kind   string                `json:"kind"`
+	Name   string                `json:"name"`
+	OfType *introspectionTypeRef `json:"ofType"`
+}
+
+type introspectionInputValue struct {
+	Name         string               `json:"name"`
+	Type         introspectionTypeRef `json:"type"`
+	DefaultValue *string              `json:"defaultValue"`
+}
+
+type introspectionType struct {
+	Kind          string                    `json:"kind"`
+	Name          string                    `json:"name"`
+	Fields        []introspectionField      `json:"fields"`
+	InputFields   []introspectionInputValue `json:"inputFields"`
+	Interfaces    []introspectionTypeRef    `json:"interfaces"`
+	EnumValues    []struct{ Name string }   `json:"enumValues"`
+	PossibleTypes []introspectionTypeRef    `json:"possibleTypes"`
+}
+
+type introspectionField struct {
+	Name string                    `json:"name"`
+	Args []introspectionInputValue `json:"args"`
+	Type introspectionTypeRef      `json:"type"`
+}
+
+type introspectionSchema struct {
+	QueryType        *struct{ Name string } `json:"queryType"`
+	MutationType     *struct{ Name string } `json:"mutationType"`
+	SubscriptionType *struct{ Name string } `json:"subscriptionType"`
+	Types            []introspectionType    `json:"types"`
+	Directives       []struct {
+		Name      string                    `json:"name"`
+		Locations []string                  `json:"locations"`
+		Args      []introspectionInputValue `json:"args"`
+	} `json:"directives"`
+}
+
+// ParseIntrospection 将内省结果（__schema 对象）转换为可用于校验的 schema
+func ParseIntrospection(introspection json.RawMessage) (*ast.Schema, error) {
+	var s introspectionSchema
+	if err := json.Unmarshal(introspection, &s); err != nil {
+		return nil, err
+	}
+	if s.QueryType == nil {
+		return nil, errors.New("introspection result has no query type")
+	}
+
+	// 内置标量和指令由解析器预置，不能重复定义
+	prelude, err := gqlparser.LoadSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	b.WriteString("schema {\n  query: " + s.QueryType.Name + "\n")
+	if s.MutationType != nil {
+		b.WriteString("  mutation: " + s.MutationType.Name + "\n")
+	}
+	if s.SubscriptionType != nil {
+		b.WriteString("  subscription: " + s.SubscriptionType.Name + "\n")
+	}
+	b.WriteString("}\n")
+
+	for _, t := range s.Types {
+		if strings.HasPrefix(t.Name, "__") || prelude.Types[t.Name] != nil {
+			continue
+		}
+		switch t.Kind {
+		case "SCALAR":
+			fmt.Fprintf(&b, "scalar %s\n", t.Name)
+		case "OBJECT", "INTERFACE":
+			keyword := "type"
+			if t.Kind == "INTERFACE" {
+				keyword = "interface"
+			}
+			fmt.Fprintf(&b, "%s %s", keyword, t.Name)
+			if len(t.Interfaces) > 0 {
+				names := make([]string, 0, len(t.Interfaces))
+				for _, i := range t.Interfaces {
+					names = append(names, i.Name)
+				}
+				b.WriteString(" implements " + strings.Join(names, " & "))
+			}
+			b.WriteString(" {\n")
+			for _, f := range t.Fields {
+				fmt.Fprintf(&b, "  %s%s: %s\n", f.Name, sdlArgs(f.Args), sdlTypeRef(f.Type))
+			}
+			b.WriteString("}\n")
+		case "UNION":
+			names := make([]string, 0, len(t.PossibleTypes))
+			for _, p := range t.PossibleTypes {
+				names = append(names, p.Name)
+			}
+			fmt.Fprintf(&b, "union %s = %s\n", t.Name, strings.Join(names, " | "))
+		case "ENUM":
+			fmt.Fprintf(&b, "enum %s {\n", t.Name)
+			for _, v := range t.EnumValues {
+				b.WriteString("  " + v.Name + "\n")
+			}
+			b.WriteString("}\n")
+		case "INPUT_OBJECT":
+			fmt.Fprintf(&b, "input %s {\n", t.Name)
+			for _, f := range t.InputFields {
+				b.WriteString("  " + sdlInputValue(f) + "\n")
+			}
+			b.WriteString("}\n")
+		}
+	}
+
+	for _, d := range s.Directives {
+		if prelude.Directives[d.Name] != nil {
+			continue
+		}
+		fmt.Fprintf(&b, "directive @%s%s on %s\n", d.Name, sdlArgs(d.Args), strings.Join(d.Locations, " | "))
+	}
+
+	return gqlparser.LoadSchema(&ast.Source{Name: "introspection", Input: b.String()})
+}
+
+func sdlArgs(args []introspectionInputValue) string {
+	if len(args) == 0 {
+		return ""
+	}
+	list := make([]string, 0, len(args))
+	for _, a := range args {
+		list = append(list, sdlInputValue(a))
+	}
+	return "(" + strings.Join(list, ", ") + ")"
+}
+
+func sdlInputValue(v introspectionInputValue) string {
+	s := v.Name + ": " + sdlTypeRef(v.Type)
+	if v.DefaultValue != nil {
+		s += " = " + *v.DefaultValue
+	}
+	return s
+}
+
+func sdlTypeRef(t introspectionTypeRef) string {
+	switch t.Kind {
+	case "NON_NULL":
+		if t.OfType != nil {
+			return sdlTypeRef(*t.OfType) + "!"
+		}
+	case "LIST":
+		if t.OfType != nil {
+			return "[" + sdlTypeRef(*t.OfType) + "]"
+		}
+	}
+	return t.Name
+}
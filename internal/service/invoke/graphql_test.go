@@ -0,0 +1,79 @@
+package invoke
+
+import (
+	"FastGo/internal/model"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testIntrospection = `{
+  "queryType": {"name": "Query"},
+  "mutationType": null,
+  "subscriptionType": null,
+  "types": [
+    {"kind": "OBJECT", "name": "Query", "fields": [
+      {"name": "hello", "args": [{"name": "name", "type": {"kind": "NON_NULL", "name": null, "ofType": {"kind": "SCALAR", "name": "String"}}, "defaultValue": null}],
+       "type": {"kind": "SCALAR", "name": "String"}},
+      {"name": "pets", "args": [], "type": {"kind": "LIST", "name": null, "ofType": {"kind": "OBJECT", "name": "Pet"}}}
+    ], "interfaces": []},
+    {"kind": "OBJECT", "name": "Pet", "fields": [
+      {"name": "kind", "args": [], "type": {"kind": "ENUM", "name": "Kind"}}
+    ], "interfaces": []},
+    {"kind": "ENUM", "name": "Kind", "enumValues": [{"name": "CAT"}, {"name": "DOG"}]},
+    {"kind": "SCALAR", "name": "String"},
+    {"kind": "OBJECT", "name": "__Schema", "fields": []}
+  ],
+  "directives": [{"name": "include", "locations": ["FIELD"], "args": []}]
+}`
+
+func TestGraphQLInvoke(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		if strings.Contains(payload.Query, "__schema") {
+			w.Write([]byte(`{"data":{"__schema":` + testIntrospection + `}}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"hello":null},"errors":[{"message":"boom","path":["hello"]}]}`))
+	}))
+	defer server.Close()
+
+	invoker := &GraphQLInvoker{Schemas: NewSchemaCache(nil)}
+	req := &model.Request{Type: model.GraphQL, Path: server.URL, Query: `query($n: String!) { hello(name: $n) }`, Variables: `{"n":"x"}`}
+	result, err := invoker.Invoke(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.GraphQL.Validated || string(result.GraphQL.Data) != `{"hello":null}` {
+		t.Fatalf("unexpected data: %+v", result.GraphQL)
+	}
+	if len(result.GraphQL.Errors) != 1 || result.GraphQL.Errors[0].Message != "boom" {
+		t.Fatalf("errors should be separated from data: %+v", result.GraphQL.Errors)
+	}
+
+	req.Query = `{ pets { name } }`
+	result, err = invoker.Invoke(context.Background(), req)
+	if err != ErrQueryValidation || len(result.GraphQL.Errors) == 0 {
+		t.Fatalf("expected validation failure, got %v %+v", err, result)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	req := model.Request{
+		Path:    "{{baseUrl}}/pets/{{ id }}",
+		Headers: model.EncodeKeyValues([]model.KeyValue{{Key: "Authorization", Value: "Bearer {{token}}", Enabled: true}}),
+	}
+	out := Expand(req, map[string]string{"baseUrl": "http://x", "id": "1"})
+	if out.Path != "http://x/pets/1" {
+		t.Fatalf("unexpected path: %s", out.Path)
+	}
+	if h := model.DecodeKeyValues(out.Headers); h[0].Value != "Bearer {{token}}" {
+		t.Fatalf("undefined variables should be kept: %s", h[0].Value)
+	}
+}
@@ -0,0 +1,333 @@
+package invoke
+
+import (
+	"FastGo/internal/model"
+	"FastGo/internal/service/protoschema"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"gorm.io/gorm"
+)
+
+// gRPC 传输方式
+const (
+	TransportGRPC        = "grpc"          // 原生 gRPC（HTTP/2）
+	TransportGRPCWeb     = "grpc-web"      // gRPC-Web 二进制
+	TransportGRPCWebText = "grpc-web-text" // gRPC-Web base64 文本
+	TransportConnectJSON = "connect-json"  // Connect 协议 JSON 编码
+	TransportConnectPB   = "connect-proto" // Connect 协议 protobuf 编码
+)
+
+// ErrMethodNotFound 已保存的 proto 描述和服务反射中都找不到方法
+var ErrMethodNotFound = errors.New("grpc method not found, upload protos or enable server reflection")
+
+// GRPCResult gRPC 调用结果，Result.StatusCode 为 gRPC 状态码
+type GRPCResult struct {
+	Transport string            `json:"transport"`
+	Method    string            `json:"method"`
+	Code      int               `json:"code"`
+	CodeName  string            `json:"code_name"`
+	Message   string            `json:"message,omitempty"`
+	Details   []json.RawMessage `json:"details,omitempty"` // google.rpc.Status 中的错误详情，如 BadRequest、ErrorInfo、RetryInfo
+	Responses []json.RawMessage `json:"responses"`
+	Headers   []model.KeyValue  `json:"headers"`
+	Trailers  []model.KeyValue  `json:"trailers"`
+}
+
+// GRPCTarget 从请求地址解析出的调用目标
+type GRPCTarget struct {
+	Base       string // 原生 gRPC 为 host:port，其余传输为 http(s)://host[:port][/prefix]
+	TLS        bool
+	FullMethod string // pkg.Service/Method
+}
+
+// ParseGRPCTarget 解析 [scheme://]host[:port][/prefix]/pkg.Service/Method
+// grpcs、https 使用 TLS，grpc、http 或无 scheme 时不加密
+func ParseGRPCTarget(path, transport string) (*GRPCTarget, error) {
+	t := &GRPCTarget{}
+	rest := path
+	if i := strings.Index(rest, "://"); i >= 0 {
+		scheme := strings.ToLower(rest[:i])
+		t.TLS = scheme == "grpcs" || scheme == "https"
+		rest = rest[i+3:]
+	}
+	parts := strings.Split(strings.Trim(rest, "/"), "/")
+	if len(parts) < 3 || !strings.Contains(parts[len(parts)-2], ".") {
+		return nil, fmt.Errorf("invalid grpc path %q, expected host:port/package.Service/Method", path)
+	}
+	t.FullMethod = parts[len(parts)-2] + "/" + parts[len(parts)-1]
+	base := strings.Join(parts[:len(parts)-2], "/")
+
+	if transport == "" || transport == TransportGRPC {
+		// 原生 gRPC 不支持路径前缀
+		t.Base = parts[0]
+		return t, nil
+	}
+	scheme := "http://"
+	if t.TLS {
+		scheme = "https://"
+	}
+	t.Base = scheme + base
+	return t, nil
+}
+
+// DescriptorSource 解析方法描述，原生 gRPC 与 gRPC-Web、Connect 共用
+type DescriptorSource interface {
+	FindMethod(ctx context.Context, req *model.Request, target *GRPCTarget, reflect bool) (protoreflect.MethodDescriptor, *dynamicpb.Types, error)
+}
+
+// SchemaSource 先在集合已保存的 proto 描述中查找，找不到时对目标服务发起反射
+type SchemaSource struct {
+	DB *gorm.DB
+}
+
+func (s *SchemaSource) FindMethod(ctx context.Context, req *model.Request, target *GRPCTarget, reflect bool) (protoreflect.MethodDescriptor, *dynamicpb.Types, error) {
+	serviceName, methodName, _ := strings.Cut(target.FullMethod, "/")
+
+	if s.DB != nil && req.CollectionID != "" {
+		var schemas []model.ProtoSchema
+		if err := s.DB.Where("collection_id = ?", req.CollectionID).Order("id DESC").Find(&schemas).Error; err != nil {
+			return nil, nil, err
+		}
+		for _, schema := range schemas {
+			files, err := protoschema.Load(schema.Descriptor)
+			if err != nil {
+				continue
+			}
+			if md := findMethod(files, serviceName, methodName); md != nil {
+				return md, dynamicpb.NewTypes(files), nil
+			}
+		}
+	}
+
+	if !reflect {
+		return nil, nil, ErrMethodNotFound
+	}
+	md, err := grpcMetadata(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	reflectMetadata := map[string]string{}
+	for key, values := range md {
+		reflectMetadata[key] = values[0]
+	}
+	set, err := protoschema.Reflect(ctx, protoschema.ReflectOptions{Target: target.Base, TLS: target.TLS, Metadata: reflectMetadata})
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrMethodNotFound, err)
+	}
+	data, err := protoschema.Marshal(set)
+	if err != nil {
+		return nil, nil, err
+	}
+	files, err := protoschema.Load(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if md := findMethod(files, serviceName, methodName); md != nil {
+		return md, dynamicpb.NewTypes(files), nil
+	}
+	return nil, nil, ErrMethodNotFound
+}
+
+func findMethod(files interface {
+	FindDescriptorByName(protoreflect.FullName) (protoreflect.Descriptor, error)
+}, serviceName, methodName string) protoreflect.MethodDescriptor {
+	d, err := files.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return nil
+	}
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil
+	}
+	return sd.Methods().ByName(protoreflect.Name(methodName))
+}
+
+// GRPCInvoker 按请求的传输方式调用 gRPC 方法
+// 请求体为 JSON 消息，客户端流和双向流方法使用 JSON 数组表示依次发送的多条消息
+type GRPCInvoker struct {
+	Source DescriptorSource
+	Client *http.Client // gRPC-Web 与 Connect 使用
+}
+
+func (i *GRPCInvoker) Invoke(ctx context.Context, req *model.Request) (*Result, error) {
+	transport := req.Transport
+	if transport == "" {
+		transport = TransportGRPC
+	}
+	target, err := ParseGRPCTarget(req.Path, transport)
+	if err != nil {
+		return nil, err
+	}
+	md, types, err := i.Source.FindMethod(ctx, req, target, transport == TransportGRPC)
+	if err != nil {
+		return nil, err
+	}
+
+	call := &grpcCall{method: md, types: types}
+	messages, err := call.decodeRequests(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	outgoing, err := grpcMetadata(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch transport {
+	case TransportGRPC:
+		return i.native(ctx, target, outgoing, call, messages)
+	case TransportGRPCWeb, TransportGRPCWebText, TransportConnectJSON, TransportConnectPB:
+		if md.IsStreamingClient() {
+			return nil, fmt.Errorf("%s does not support client streaming methods", transport)
+		}
+		return i.web(ctx, target, transport, outgoing, call, messages[0])
+	}
+	return nil, fmt.Errorf("unknown grpc transport %q", transport)
+}
+
+// grpcCall 方法描述及消息编解码
+type grpcCall struct {
+	method protoreflect.MethodDescriptor
+	types  *dynamicpb.Types
+}
+
+// decodeRequests 将请求体解析为待发送的消息
+func (c *grpcCall) decodeRequests(body string) ([]*dynamicpb.Message, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		body = "{}"
+	}
+	var raws []json.RawMessage
+	if c.method.IsStreamingClient() && strings.HasPrefix(body, "[") {
+		if err := json.Unmarshal([]byte(body), &raws); err != nil {
+			return nil, err
+		}
+	} else {
+		raws = []json.RawMessage{json.RawMessage(body)}
+	}
+
+	messages := make([]*dynamicpb.Message, 0, len(raws))
+	for _, raw := range raws {
+		msg := dynamicpb.NewMessage(c.method.Input())
+		if err := (protojson.UnmarshalOptions{Resolver: c.types}).Unmarshal(raw, msg); err != nil {
+			return nil, fmt.Errorf("invalid request message: %w", err)
+		}
+		messages = append(messages, msg)
+	}
+	if len(messages) == 0 {
+		return nil, errors.New("at least one request message is required")
+	}
+	return messages, nil
+}
+
+func (c *grpcCall) newResponse() *dynamicpb.Message {
+	return dynamicpb.NewMessage(c.method.Output())
+}
+
+func (c *grpcCall) marshalJSON(msg proto.Message) json.RawMessage {
+	data, err := (protojson.MarshalOptions{Resolver: c.types, EmitUnpopulated: true}).Marshal(msg)
+	if err != nil {
+		data, _ = json.Marshal(err.Error())
+	}
+	return data
+}
+
+func (c *grpcCall) fullMethod() string {
+	return "/" + string(c.method.Parent().FullName()) + "/" + string(c.method.Name())
+}
+func (i *GRPCInvoker) native(ctx context.Context, target *GRPCTarget, md metadata.MD, call *grpcCall, messages []*dynamicpb.Message) (*Result, error) {
+	creds := insecure.NewCredentials()
+	if target.TLS {
+		creds = credentials.NewTLS(&tls.Config{})
+	}
+	conn, err := grpc.NewClient(target.Base, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if len(md) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, md)
+	}
+
+	rpc := &GRPCResult{Transport: TransportGRPC, Method: target.FullMethod, Responses: []json.RawMessage{}}
+	desc := &grpc.StreamDesc{
+		StreamName:    string(call.method.Name()),
+		ClientStreams: call.method.IsStreamingClient(),
+		ServerStreams: call.method.IsStreamingServer(),
+	}
+	stream, err := conn.NewStream(ctx, desc, call.fullMethod())
+	if err == nil {
+		err = func() error {
+			for _, msg := range messages {
+				if err := stream.SendMsg(msg); err != nil {
+					return err
+				}
+			}
+			if err := stream.CloseSend(); err != nil {
+				return err
+			}
+			for {
+				resp := call.newResponse()
+				if err := stream.RecvMsg(resp); err != nil {
+					if err == io.EOF {
+						return nil
+					}
+					return err
+				}
+				rpc.Responses = append(rpc.Responses, call.marshalJSON(resp))
+			}
+		}()
+		// RecvMsg 返回后响应头和尾部元数据均已就绪
+		if header, e := stream.Header(); e == nil {
+			rpc.Headers = metadataKeyValues(header)
+		}
+		rpc.Trailers = metadataKeyValues(stream.Trailer())
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil, err
+	}
+	result := grpcResult(rpc, call, st.Proto())
+	result.Headers = rpc.Headers
+	return result, nil
+}
+
+// grpcResult 汇总调用结果，非流式响应的响应体为单条消息，流式为消息数组
+func grpcResult(rpc *GRPCResult, call *grpcCall, st *spb.Status) *Result {
+	code := codes.Code(st.GetCode())
+	rpc.Code, rpc.CodeName, rpc.Message = int(code), code.String(), st.GetMessage()
+	rpc.Details = call.statusDetails(st.GetDetails())
+	var body []byte
+	if !call.method.IsStreamingServer() && len(rpc.Responses) == 1 {
+		body = rpc.Responses[0]
+	} else {
+		body, _ = json.Marshal(rpc.Responses)
+	}
+	return &Result{
+		Status:     code.String(),
+		StatusCode: int(code),
+		Body:       string(body),
+		Size:       len(body),
+		GRPC:       rpc,
+	}
+}
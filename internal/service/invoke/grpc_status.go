@@ -0,0 +1,136 @@
+package invoke
+
+import (
+	"FastGo/internal/model"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	_ "google.golang.org/genproto/googleapis/rpc/errdetails" // 注册 BadRequest、ErrorInfo、RetryInfo 等错误详情类型
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// 状态详情的尾部元数据键，值为 base64 编码的 google.rpc.Status
+const statusDetailsKey = "grpc-status-details-bin"
+
+// grpcMetadata 将启用的请求头转换为元数据，键统一为小写
+// -bin 结尾的键为二进制元数据，值填写 base64（可省略填充），发送前解码
+func grpcMetadata(req *model.Request) (metadata.MD, error) {
+	md := metadata.MD{}
+	for _, h := range model.DecodeKeyValues(req.Headers) {
+		if !h.Enabled || h.Key == "" {
+			continue
+		}
+		key := strings.ToLower(h.Key)
+		value := h.Value
+		if strings.HasSuffix(key, "-bin") {
+			data, err := decodeBase64(value)
+			if err != nil {
+				return nil, fmt.Errorf("metadata %s must be base64: %w", key, err)
+			}
+			value = string(data)
+		}
+		md.Append(key, value)
+	}
+	return md, nil
+}
+
+// metadataKeyValues 按键排序展示元数据，二进制值编码为 base64
+func metadataKeyValues(md metadata.MD) []model.KeyValue {
+	keys := make([]string, 0, len(md))
+	for key := range md {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	kvs := make([]model.KeyValue, 0, len(md))
+	for _, key := range keys {
+		for _, v := range md[key] {
+			if strings.HasSuffix(key, "-bin") {
+				v = base64.StdEncoding.EncodeToString([]byte(v))
+			}
+			kvs = append(kvs, model.KeyValue{Key: key, Value: v, Enabled: true})
+		}
+	}
+	return kvs
+}
+
+// decodeBase64 兼容有无填充的标准和 URL 安全编码
+func decodeBase64(s string) ([]byte, error) {
+	s = strings.TrimRight(strings.TrimSpace(s), "=")
+	if strings.ContainsAny(s, "-_") {
+		return base64.RawURLEncoding.DecodeString(s)
+	}
+	return base64.RawStdEncoding.DecodeString(s)
+}
+
+// decodeStatusDetails 解析 grpc-status-details-bin 中的 google.rpc.Status
+func decodeStatusDetails(value string) (*spb.Status, error) {
+	data, err := decodeBase64(value)
+	if err != nil {
+		return nil, err
+	}
+	st := &spb.Status{}
+	if err := proto.Unmarshal(data, st); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// detailResolver 先查找内置的错误详情类型，再查找用户 proto 中的自定义类型
+type detailResolver struct {
+	types interface {
+		protoregistry.MessageTypeResolver
+		protoregistry.ExtensionTypeResolver
+	}
+}
+
+func (r detailResolver) FindMessageByName(name protoreflect.FullName) (protoreflect.MessageType, error) {
+	if mt, err := protoregistry.GlobalTypes.FindMessageByName(name); err == nil {
+		return mt, nil
+	}
+	return r.types.FindMessageByName(name)
+}
+
+func (r detailResolver) FindMessageByURL(url string) (protoreflect.MessageType, error) {
+	if mt, err := protoregistry.GlobalTypes.FindMessageByURL(url); err == nil {
+		return mt, nil
+	}
+	return r.types.FindMessageByURL(url)
+}
+
+func (r detailResolver) FindExtensionByName(name protoreflect.FullName) (protoreflect.ExtensionType, error) {
+	return r.types.FindExtensionByName(name)
+}
+
+func (r detailResolver) FindExtensionByNumber(message protoreflect.FullName, field protoreflect.FieldNumber) (protoreflect.ExtensionType, error) {
+	return r.types.FindExtensionByNumber(message, field)
+}
+
+// statusDetails 将状态详情转换为 JSON，无法识别的类型保留 @type 和 base64 原文
+func (c *grpcCall) statusDetails(details []*anypb.Any) []json.RawMessage {
+	if len(details) == 0 {
+		return nil
+	}
+	opts := protojson.MarshalOptions{Resolver: detailResolver{types: c.types}}
+	list := make([]json.RawMessage, 0, len(details))
+	for _, d := range details {
+		data, err := opts.Marshal(d)
+		if err != nil {
+			data, _ = json.Marshal(map[string]string{
+				"@type": d.GetTypeUrl(),
+				"value": base64.StdEncoding.EncodeToString(d.GetValue()),
+			})
+		}
+		list = append(list, data)
+	}
+	return list
+}
@@ -0,0 +1,245 @@
+package invoke
+
+import (
+	"FastGo/internal/model"
+	"FastGo/internal/service/protoschema"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const greeterProto = `syntax = "proto3";
+package demo;
+
+message HelloRequest {
+  string name = 1;
+}
+
+message HelloReply {
+  string message = 1;
+}
+
+service Greeter {
+  rpc SayHello(HelloRequest) returns (HelloReply);
+  rpc SayHellos(HelloRequest) returns (stream HelloReply);
+}
+`
+
+type staticSource struct {
+	t *testing.T
+}
+
+func (s staticSource) FindMethod(ctx context.Context, req *model.Request, target *GRPCTarget, reflect bool) (protoreflect.MethodDescriptor, *dynamicpb.Types, error) {
+	set, err := protoschema.Compile(ctx, map[string]string{"greeter.proto": greeterProto})
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	data, _ := protoschema.Marshal(set)
+	files, err := protoschema.Load(data)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	service, method, _ := strings.Cut(target.FullMethod, "/")
+	md := findMethod(files, service, method)
+	if md == nil {
+		return nil, nil, ErrMethodNotFound
+	}
+	return md, dynamicpb.NewTypes(files), nil
+}
+
+// greeterServer 按 Content-Type 实现 gRPC-Web 与 Connect 两种协议
+func greeterServer(t *testing.T) *httptest.Server {
+	source := staticSource{t: t}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target := &GRPCTarget{FullMethod: strings.TrimPrefix(r.URL.Path, "/")}
+		md, _, err := source.FindMethod(r.Context(), nil, target, false)
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		contentType := r.Header.Get("Content-Type")
+		text := contentType == "application/grpc-web-text"
+		if text {
+			body, _ = base64.StdEncoding.DecodeString(string(body))
+		}
+		enveloped := strings.HasPrefix(contentType, "application/grpc-web") || strings.HasPrefix(contentType, "application/connect+")
+		if enveloped {
+			frames, err := decodeFrames(body)
+			if err != nil || len(frames) != 1 {
+				t.Errorf("bad request frames: %v", err)
+			}
+			body = frames[0].data
+		}
+
+		in := dynamicpb.NewMessage(md.Input())
+		if contentType == "application/json" {
+			var m struct{ Name string }
+			json.Unmarshal(body, &m)
+			in.Set(md.Input().Fields().ByName("name"), protoreflect.ValueOfString(m.Name))
+		} else if err := proto.Unmarshal(body, in); err != nil {
+			t.Errorf("bad request message: %v", err)
+		}
+		name := in.Get(md.Input().Fields().ByName("name")).String()
+
+		reply := func(n int) []byte {
+			out := dynamicpb.NewMessage(md.Output())
+			out.Set(md.Output().Fields().ByName("message"), protoreflect.ValueOfString(strings.Repeat("hello ", n)+name))
+			data, _ := proto.Marshal(out)
+			return data
+		}
+
+		switch {
+		case strings.HasPrefix(contentType, "application/grpc-web"):
+			w.Header().Set("Content-Type", contentType)
+			var out []byte
+			out = append(out, encodeFrame(frameData, reply(1))...)
+			out = append(out, encodeFrame(frameTrailer, []byte("grpc-status: 0\r\ngrpc-message: \r\n"))...)
+			if text {
+				// 分两段编码，客户端需要按组独立解码
+				w.Write([]byte(base64.StdEncoding.EncodeToString(out[:7]) + base64.StdEncoding.EncodeToString(out[7:])))
+				return
+			}
+			w.Write(out)
+		case contentType == "application/json":
+			if name == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"code":"invalid_argument","message":"name is required"}`))
+				return
+			}
+			w.Write([]byte(`{"message":"hello ` + name + `"}`))
+		case contentType == "application/connect+proto":
+			w.Write(encodeFrame(frameData, reply(1)))
+			w.Write(encodeFrame(frameData, reply(2)))
+			w.Write(encodeFrame(frameEndStream, []byte(`{"error":{"code":"resource_exhausted","message":"quota"}}`)))
+		default:
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+		}
+	}))
+}
+
+func TestGRPCWebAndConnect(t *testing.T) {
+	server := greeterServer(t)
+	defer server.Close()
+	invoker := &GRPCInvoker{Source: staticSource{t: t}}
+
+	cases := []struct {
+		transport string
+		method    string
+		body      string
+		code      int
+		response  string
+		message   string
+	}{
+		{TransportGRPCWeb, "SayHello", `{"name":"web"}`, 0, `{"message":"hello web"}`, ""},
+		{TransportGRPCWebText, "SayHello", `{"name":"text"}`, 0, `{"message":"hello text"}`, ""},
+		{TransportConnectJSON, "SayHello", `{"name":"connect"}`, 0, `{"message":"hello connect"}`, ""},
+		{TransportConnectJSON, "SayHello", `{}`, 3, `[]`, "name is required"},
+		{TransportConnectPB, "SayHellos", `{"name":"stream"}`, 8, `[{"message":"hello stream"},{"message":"hello hello stream"}]`, "quota"},
+	}
+	for _, c := range cases {
+		req := &model.Request{Type: model.GRPC1, Transport: c.transport, Path: server.URL + "/demo.Greeter/" + c.method, Body: c.body}
+		result, err := invoker.Invoke(context.Background(), req)
+		if err != nil {
+			t.Fatalf("%s: %v", c.transport, err)
+		}
+		var got, want interface{}
+		json.Unmarshal([]byte(result.Body), &got)
+		json.Unmarshal([]byte(c.response), &want)
+		if result.StatusCode != c.code || result.GRPC.Message != c.message {
+			t.Fatalf("%s: unexpected result %+v", c.transport, result.GRPC)
+		}
+		gotJSON, _ := json.Marshal(got)
+		wantJSON, _ := json.Marshal(want)
+		if string(gotJSON) != string(wantJSON) {
+			t.Fatalf("%s: body %s, want %s", c.transport, gotJSON, wantJSON)
+		}
+	}
+}
+
+func TestParseGRPCTarget(t *testing.T) {
+	target, err := ParseGRPCTarget("grpcs://api.example.com:443/demo.Greeter/SayHello", TransportGRPC)
+	if err != nil || target.Base != "api.example.com:443" || !target.TLS || target.FullMethod != "demo.Greeter/SayHello" {
+		t.Fatalf("unexpected target %+v, %v", target, err)
+	}
+	target, err = ParseGRPCTarget("localhost:8080/rpc/demo.Greeter/SayHello", TransportConnectJSON)
+	if err != nil || target.Base != "http://localhost:8080/rpc" || target.TLS {
+		t.Fatalf("unexpected target %+v, %v", target, err)
+	}
+	if _, err := ParseGRPCTarget("localhost:8080/SayHello", TransportGRPC); err == nil {
+		t.Fatal("expected invalid path error")
+	}
+}
+
+func TestGRPCNativeStatusDetails(t *testing.T) {
+	source := staticSource{t: t}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer(grpc.UnknownServiceHandler(func(srv interface{}, stream grpc.ServerStream) error {
+		method, _ := grpc.MethodFromServerStream(stream)
+		md, _, _ := source.FindMethod(stream.Context(), nil, &GRPCTarget{FullMethod: strings.TrimPrefix(method, "/")}, false)
+		in := dynamicpb.NewMessage(md.Input())
+		if err := stream.RecvMsg(in); err != nil {
+			return err
+		}
+		incoming, _ := metadata.FromIncomingContext(stream.Context())
+		stream.SetHeader(metadata.Pairs("trace-bin", incoming.Get("trace-bin")[0]))
+		stream.SetTrailer(metadata.Pairs("x-served-by", "test"))
+		st, _ := status.New(codes.InvalidArgument, "bad name").WithDetails(&errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "name", Description: "must not be empty"}},
+		})
+		return st.Err()
+	}))
+	go server.Serve(lis)
+	defer server.Stop()
+
+	registry := NewRegistry(nil, nil)
+	registry.Register(model.GRPC1, &GRPCInvoker{Source: source})
+	req := model.Request{
+		Type:    model.GRPC1,
+		Path:    lis.Addr().String() + "/demo.Greeter/SayHello",
+		Body:    `{}`,
+		Timeout: 5000,
+		Headers: model.EncodeKeyValues([]model.KeyValue{{Key: "Trace-Bin", Value: "AQI", Enabled: true}}),
+		Assertions: `[{"target":"status","operator":"eq","value":3},
+			{"target":"details.0.fieldViolations.0.field","operator":"eq","value":"name"},
+			{"target":"trailers.x-served-by","operator":"eq","value":"test"}]`,
+	}
+	_, result, err := registry.Invoke(context.Background(), &Call{Request: req})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rpc := result.GRPC
+	if rpc.Message != "bad name" || len(rpc.Details) != 1 || !strings.Contains(string(rpc.Details[0]), "google.rpc.BadRequest") {
+		t.Fatalf("unexpected status %+v", rpc)
+	}
+	found := false
+	for _, h := range rpc.Headers {
+		found = found || h.Key == "trace-bin" && h.Value == "AQI="
+	}
+	if !found {
+		t.Fatalf("binary header not echoed: %+v", rpc.Headers)
+	}
+	for _, a := range result.Assertions {
+		if !a.Passed {
+			t.Fatalf("assertion failed: %+v", a)
+		}
+	}
+}
@@ -0,0 +1,396 @@
+package invoke
+
+import (
+	"FastGo/internal/model"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// 帧标志位
+const (
+	frameData       byte = 0x00
+	frameCompressed byte = 0x01
+	frameEndStream  byte = 0x02 // Connect 流结束帧
+	frameTrailer    byte = 0x80 // gRPC-Web 尾部元数据帧
+)
+
+// errFrameCompressed 未请求压缩，服务端仍返回了压缩帧
+var errFrameCompressed = errors.New("compressed frames are not supported")
+
+// connectCodes Connect 错误码与 gRPC 状态码的对应关系，如 invalid_argument
+var connectCodes = func() map[string]codes.Code {
+	m := map[string]codes.Code{}
+	for c := codes.Canceled; c <= codes.Unauthenticated; c++ {
+		var b strings.Builder
+		for n, r := range c.String() {
+			if unicode.IsUpper(r) {
+				if n > 0 {
+					b.WriteByte('_')
+				}
+				r = unicode.ToLower(r)
+			}
+			b.WriteRune(r)
+		}
+		m[b.String()] = c
+	}
+	return m
+}()
+
+// web 通过 gRPC-Web 或 Connect 协议调用，仅支持一元和服务端流方法
+func (i *GRPCInvoker) web(ctx context.Context, target *GRPCTarget, transport string, md metadata.MD, call *grpcCall, msg *dynamicpb.Message) (*Result, error) {
+	// 二进制元数据在 HTTP 头中使用 base64 传输
+	headers := metadataKeyValues(md)
+
+	connect := transport == TransportConnectJSON || transport == TransportConnectPB
+	streaming := call.method.IsStreamingServer()
+
+	var payload []byte
+	var err error
+	if transport == TransportConnectJSON {
+		payload, err = (protojson.MarshalOptions{Resolver: call.types}).Marshal(msg)
+	} else {
+		payload, err = proto.Marshal(msg)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var contentType string
+	switch {
+	case transport == TransportGRPCWeb:
+		contentType = "application/grpc-web+proto"
+		payload = encodeFrame(frameData, payload)
+	case transport == TransportGRPCWebText:
+		contentType = "application/grpc-web-text"
+		payload = []byte(base64.StdEncoding.EncodeToString(encodeFrame(frameData, payload)))
+	case streaming:
+		contentType = "application/connect+json"
+		if transport == TransportConnectPB {
+			contentType = "application/connect+proto"
+		}
+		payload = encodeFrame(frameData, payload)
+	default:
+		contentType = "application/json"
+		if transport == TransportConnectPB {
+			contentType = "application/proto"
+		}
+	}
+	headers = append(headers, model.KeyValue{Key: "Content-Type", Value: contentType, Enabled: true})
+
+	// 超时时间作为截止时间传给服务端
+	deadline, hasDeadline := ctx.Deadline()
+	timeout := time.Until(deadline).Milliseconds()
+	if connect {
+		headers = append(headers, model.KeyValue{Key: "Connect-Protocol-Version", Value: "1", Enabled: true})
+		if hasDeadline {
+			headers = append(headers, model.KeyValue{Key: "Connect-Timeout-Ms", Value: strconv.FormatInt(timeout, 10), Enabled: true})
+		}
+	} else {
+		headers = append(headers,
+			model.KeyValue{Key: "Accept", Value: contentType, Enabled: true},
+			model.KeyValue{Key: "X-Grpc-Web", Value: "1", Enabled: true},
+		)
+		if hasDeadline {
+			headers = append(headers, model.KeyValue{Key: "Grpc-Timeout", Value: strconv.FormatInt(timeout, 10) + "m", Enabled: true})
+		}
+	}
+
+	result, err := doHTTP(ctx, i.Client, http.MethodPost, target.Base+"/"+target.FullMethod, headers, string(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	rpc := &GRPCResult{Transport: transport, Method: target.FullMethod, Responses: []json.RawMessage{}, Trailers: []model.KeyValue{}}
+	var st *spb.Status
+	switch {
+	case !connect:
+		st, err = decodeGRPCWeb(result, transport == TransportGRPCWebText, call, rpc)
+	case streaming:
+		st = decodeConnectStream(result, transport == TransportConnectJSON, call, rpc)
+	default:
+		st = decodeConnectUnary(result, transport == TransportConnectJSON, call, rpc)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if rpc.Headers == nil {
+		rpc.Headers = result.Headers
+	}
+
+	out := grpcResult(rpc, call, st)
+	out.Headers, out.Truncated, out.Warnings = result.Headers, result.Truncated, result.Warnings
+	return out, nil
+}
+
+// encodeFrame 编码一帧：1 字节标志位、4 字节大端长度和消息内容
+func encodeFrame(flag byte, data []byte) []byte {
+	frame := make([]byte, 5+len(data))
+	frame[0] = flag
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(data)))
+	copy(frame[5:], data)
+	return frame
+}
+
+type frame struct {
+	flag byte
+	data []byte
+}
+
+// decodeFrames 按长度前缀切分响应体
+func decodeFrames(data []byte) ([]frame, error) {
+	var frames []frame
+	for len(data) > 0 {
+		if len(data) < 5 {
+			return frames, errors.New("truncated frame header")
+		}
+		n := binary.BigEndian.Uint32(data[1:5])
+		if uint64(len(data)-5) < uint64(n) {
+			return frames, errors.New("truncated frame")
+		}
+		frames = append(frames, frame{flag: data[0], data: data[5 : 5+n]})
+		data = data[5+n:]
+	}
+	return frames, nil
+}
+
+// decodeGRPCWebText 解码 base64 响应体，服务端可能分段编码，按 4 字符一组独立解码
+func decodeGRPCWebText(s string) ([]byte, error) {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+	var out []byte
+	for len(s) > 0 {
+		n := 4
+		if len(s) < n {
+			n = len(s)
+		}
+		chunk, err := base64.StdEncoding.DecodeString(s[:n])
+		if err != nil {
+			return nil, fmt.Errorf("invalid grpc-web-text response: %w", err)
+		}
+		out = append(out, chunk...)
+		s = s[n:]
+	}
+	return out, nil
+}
+
+func newStatus(code codes.Code, message string) *spb.Status {
+	return &spb.Status{Code: int32(code), Message: message}
+}
+
+// decodeGRPCWeb 解析 gRPC-Web 响应，状态取自尾部帧，仅有尾部时状态在响应头中
+func decodeGRPCWeb(result *Result, text bool, call *grpcCall, rpc *GRPCResult) (*spb.Status, error) {
+	body := []byte(result.Body)
+	if text {
+		var err error
+		if body, err = decodeGRPCWebText(result.Body); err != nil {
+			return nil, err
+		}
+	}
+	frames, err := decodeFrames(body)
+	if err != nil {
+		return newStatus(codes.Internal, err.Error()), nil
+	}
+
+	hasTrailer := false
+	for _, f := range frames {
+		switch {
+		case f.flag&frameTrailer != 0:
+			hasTrailer = true
+			for _, line := range strings.Split(string(f.data), "\r\n") {
+				key, value, ok := strings.Cut(line, ":")
+				if ok {
+					rpc.Trailers = append(rpc.Trailers, model.KeyValue{Key: strings.ToLower(strings.TrimSpace(key)), Value: strings.TrimSpace(value), Enabled: true})
+				}
+			}
+		case f.flag&frameCompressed != 0:
+			return newStatus(codes.Internal, errFrameCompressed.Error()), nil
+		default:
+			resp := call.newResponse()
+			if err := (proto.UnmarshalOptions{Resolver: call.types}).Unmarshal(f.data, resp); err != nil {
+				return newStatus(codes.Internal, "invalid response message: "+err.Error()), nil
+			}
+			rpc.Responses = append(rpc.Responses, call.marshalJSON(resp))
+		}
+	}
+
+	if !hasTrailer {
+		// Trailers-Only 响应，响应头即尾部元数据
+		rpc.Trailers = result.Headers
+		rpc.Headers = []model.KeyValue{}
+	}
+	trailer := map[string]string{}
+	for _, kv := range rpc.Trailers {
+		trailer[strings.ToLower(kv.Key)] = kv.Value
+	}
+	return trailerStatus(result, trailer), nil
+}
+
+// trailerStatus 从 grpc-status、grpc-message 和 grpc-status-details-bin 解析状态
+func trailerStatus(result *Result, trailer map[string]string) *spb.Status {
+	value, ok := trailer["grpc-status"]
+	if !ok {
+		if result.StatusCode != http.StatusOK {
+			return newStatus(httpStatusCode(result.StatusCode), result.Status)
+		}
+		return newStatus(codes.Internal, "missing grpc-status")
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return newStatus(codes.Unknown, "invalid grpc-status "+value)
+	}
+	message, _ := url.PathUnescape(trailer["grpc-message"])
+	st := newStatus(codes.Code(n), message)
+	if details, ok := trailer[statusDetailsKey]; ok {
+		if full, err := decodeStatusDetails(details); err == nil {
+			st.Details = full.GetDetails()
+		} else {
+			result.Warnings = append(result.Warnings, "invalid "+statusDetailsKey+": "+err.Error())
+		}
+	}
+	return st
+}
+
+// connectError Connect 协议的错误响应
+type connectError struct {
+	Code    string          `json:"code"`
+	Message string          `json:"message"`
+	Details []connectDetail `json:"details,omitempty"`
+}
+
+// connectDetail 错误详情，value 为不带填充的 base64 编码消息
+type connectDetail struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+func (e *connectError) status() *spb.Status {
+	code, ok := connectCodes[e.Code]
+	if !ok {
+		code = codes.Unknown
+	}
+	st := newStatus(code, e.Message)
+	for _, d := range e.Details {
+		value, err := decodeBase64(d.Value)
+		if err != nil {
+			continue
+		}
+		st.Details = append(st.Details, &anypb.Any{TypeUrl: "type.googleapis.com/" + d.Type, Value: value})
+	}
+	return st
+}
+
+// decodeConnectUnary 解析 Connect 一元响应，非 200 时响应体为 JSON 错误，Trailer- 前缀的响应头为尾部元数据
+func decodeConnectUnary(result *Result, isJSON bool, call *grpcCall, rpc *GRPCResult) *spb.Status {
+	rpc.Headers = []model.KeyValue{}
+	for _, h := range result.Headers {
+		if key, ok := cutPrefixFold(h.Key, "Trailer-"); ok {
+			rpc.Trailers = append(rpc.Trailers, model.KeyValue{Key: strings.ToLower(key), Value: h.Value, Enabled: true})
+		} else {
+			rpc.Headers = append(rpc.Headers, h)
+		}
+	}
+
+	if result.StatusCode != http.StatusOK {
+		var e connectError
+		if err := json.Unmarshal([]byte(result.Body), &e); err != nil || e.Code == "" {
+			return newStatus(httpStatusCode(result.StatusCode), result.Status)
+		}
+		return e.status()
+	}
+	resp := call.newResponse()
+	if err := unmarshalMessage([]byte(result.Body), isJSON, call, resp); err != nil {
+		return newStatus(codes.Internal, "invalid response message: "+err.Error())
+	}
+	rpc.Responses = append(rpc.Responses, call.marshalJSON(resp))
+	return newStatus(codes.OK, "")
+}
+
+// decodeConnectStream 解析 Connect 流式响应，最后一帧为携带错误和尾部元数据的结束帧
+func decodeConnectStream(result *Result, isJSON bool, call *grpcCall, rpc *GRPCResult) *spb.Status {
+	if result.StatusCode != http.StatusOK {
+		return newStatus(httpStatusCode(result.StatusCode), result.Status)
+	}
+	frames, err := decodeFrames([]byte(result.Body))
+	if err != nil {
+		return newStatus(codes.Internal, err.Error())
+	}
+	for _, f := range frames {
+		switch {
+		case f.flag&frameCompressed != 0:
+			return newStatus(codes.Internal, errFrameCompressed.Error())
+		case f.flag&frameEndStream != 0:
+			var end struct {
+				Error    *connectError       `json:"error"`
+				Metadata map[string][]string `json:"metadata"`
+			}
+			if err := json.Unmarshal(f.data, &end); err != nil {
+				return newStatus(codes.Internal, "invalid end of stream message: "+err.Error())
+			}
+			rpc.Trailers = responseHeaders(end.Metadata)
+			if end.Error != nil {
+				return end.Error.status()
+			}
+			return newStatus(codes.OK, "")
+		default:
+			resp := call.newResponse()
+			if err := unmarshalMessage(f.data, isJSON, call, resp); err != nil {
+				return newStatus(codes.Internal, "invalid response message: "+err.Error())
+			}
+			rpc.Responses = append(rpc.Responses, call.marshalJSON(resp))
+		}
+	}
+	return newStatus(codes.Internal, "missing end of stream message")
+}
+
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) > len(prefix) && strings.EqualFold(s[:len(prefix)], prefix) {
+		return s[len(prefix):], true
+	}
+	return s, false
+}
+
+func unmarshalMessage(data []byte, isJSON bool, call *grpcCall, msg proto.Message) error {
+	if isJSON {
+		return (protojson.UnmarshalOptions{Resolver: call.types, DiscardUnknown: true}).Unmarshal(data, msg)
+	}
+	return (proto.UnmarshalOptions{Resolver: call.types}).Unmarshal(data, msg)
+}
+
+// httpStatusCode 按 gRPC 规范将 HTTP 状态映射为 gRPC 状态码
+func httpStatusCode(status int) codes.Code {
+	switch status {
+	case http.StatusBadRequest:
+		return codes.Internal
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.Unimplemented
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return codes.Unavailable
+	}
+	return codes.Unknown
+}
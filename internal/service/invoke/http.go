@@ -0,0 +1,111 @@
+package invoke
+
+import (
+	"FastGo/internal/model"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// HTTPInvoker 发送 HTTP 请求，网络错误时按 RetryCount 重试
+type HTTPInvoker struct {
+	Client *http.Client
+}
+
+func (i *HTTPInvoker) Invoke(ctx context.Context, req *model.Request) (*Result, error) {
+	method := string(req.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	headers := model.DecodeKeyValues(req.Headers)
+
+	var result *Result
+	var err error
+	for attempt := 0; attempt <= req.RetryCount; attempt++ {
+		result, err = doHTTP(ctx, i.Client, method, requestURL(req), headers, req.Body)
+		if err == nil || ctx.Err() != nil {
+			break
+		}
+	}
+	return result, err
+}
+
+// requestURL 拼接启用的查询参数
+func requestURL(req *model.Request) string {
+	query := url.Values{}
+	for _, kv := range model.DecodeKeyValues(req.QueryParams) {
+		if kv.Enabled && kv.Key != "" {
+			query.Add(kv.Key, kv.Value)
+		}
+	}
+	if len(query) == 0 {
+		return req.Path
+	}
+	sep := "?"
+	if strings.Contains(req.Path, "?") {
+		sep = "&"
+	}
+	return req.Path + sep + query.Encode()
+}
+
+// doHTTP 发送一次 HTTP 请求并读取响应
+func doHTTP(ctx context.Context, client *http.Client, method, rawURL string, headers []model.KeyValue, body string) (*Result, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, method, rawURL, reader)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range headers {
+		if h.Enabled && h.Key != "" {
+			httpReq.Header.Add(h.Key, h.Value)
+		}
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody+1))
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{
+		Status:     resp.Status,
+		StatusCode: resp.StatusCode,
+		Headers:    responseHeaders(resp.Header),
+	}
+	if len(data) > maxResponseBody {
+		data = data[:maxResponseBody]
+		result.Truncated = true
+	}
+	result.Body = string(data)
+	result.Size = len(data)
+	return result, nil
+}
+
+func responseHeaders(header http.Header) []model.KeyValue {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	headers := make([]model.KeyValue, 0, len(header))
+	for _, key := range keys {
+		for _, v := range header[key] {
+			headers = append(headers, model.KeyValue{Key: key, Value: v, Enabled: true})
+		}
+	}
+	return headers
+}
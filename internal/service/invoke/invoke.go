@@ -0,0 +1,158 @@
+package invoke
+
+import (
+	"FastGo/internal/model"
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// 未设置超时时间时的默认值
+const defaultTimeout = 30 * time.Second
+
+// 响应体读取上限，超出部分截断
+const maxResponseBody = 10 << 20
+
+// ErrUnsupportedType 请求类型没有对应的发送实现
+var ErrUnsupportedType = errors.New("unsupported request type")
+
+// 变量引用 {{name}}
+var variableRegex = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.\-]+)\s*\}\}`)
+
+// Call 一次发送的参数，Request 中的变量在发送前替换
+type Call struct {
+	Request   model.Request
+	Variables map[string]string
+}
+
+// Result 发送结果，协议相关的结构化内容放在对应字段中
+type Result struct {
+	Status     string            `json:"status"`
+	StatusCode int               `json:"status_code"`
+	Headers    []model.KeyValue  `json:"headers"`
+	Body       string            `json:"body"`
+	Size       int               `json:"size"`
+	Duration   int64             `json:"duration"` // 毫秒
+	Truncated  bool              `json:"truncated,omitempty"`
+	GraphQL    *GraphQLResult    `json:"graphql,omitempty"`
+	JSONRPC    *JSONRPCResult    `json:"jsonrpc,omitempty"`
+	GRPC       *GRPCResult       `json:"grpc,omitempty"`
+	Assertions []AssertionResult `json:"assertions,omitempty"`
+	Warnings   []string          `json:"warnings,omitempty"`
+}
+
+// Invoker 某一请求类型的发送实现
+type Invoker interface {
+	Invoke(ctx context.Context, req *model.Request) (*Result, error)
+}
+
+// Registry 按请求类型分发的发送器
+type Registry struct {
+	invokers map[model.RequestType]Invoker
+}
+
+// NewRegistry 创建包含全部内置请求类型的发送器
+func NewRegistry(db *gorm.DB, rdb *redis.Client) *Registry {
+	r := &Registry{invokers: map[model.RequestType]Invoker{}}
+	r.Register(model.HTTP1, &HTTPInvoker{})
+	r.Register(model.GraphQL, &GraphQLInvoker{Schemas: NewSchemaCache(rdb)})
+	r.Register(model.JSONRPC, &JSONRPCInvoker{})
+	r.Register(model.GRPC1, &GRPCInvoker{Source: &SchemaSource{DB: db}})
+	return r
+}
+
+// Register 注册请求类型的发送实现
+func (r *Registry) Register(t model.RequestType, invoker Invoker) {
+	r.invokers[t] = invoker
+}
+
+// Invoker 返回请求类型的发送实现
+func (r *Registry) Invoker(t model.RequestType) (Invoker, bool) {
+	invoker, ok := r.invokers[t]
+	return invoker, ok
+}
+
+// Invoke 替换变量后按请求类型发送并执行断言，返回实际发送的请求
+func (r *Registry) Invoke(ctx context.Context, call *Call) (*model.Request, *Result, error) {
+	req := Expand(call.Request, call.Variables)
+	invoker, ok := r.invokers[req.Type]
+	if !ok {
+		return &req, nil, ErrUnsupportedType
+	}
+
+	timeout := defaultTimeout
+	if req.Timeout > 0 {
+		timeout = time.Duration(req.Timeout) * time.Millisecond
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	result, err := invoker.Invoke(ctx, &req)
+	if result != nil {
+		result.Duration = time.Since(start).Milliseconds()
+	}
+	if result != nil && err == nil {
+		assertions, e := ParseAssertions(req.Assertions)
+		if e != nil {
+			result.Warnings = append(result.Warnings, e.Error())
+		}
+		result.Assertions = Assert(result, assertions)
+	}
+	return &req, result, err
+}
+
+// Expand 替换请求中的 {{name}} 变量，未定义的变量保持原样
+// 认证设置在替换后转换为请求头或查询参数
+func Expand(req model.Request, vars map[string]string) model.Request {
+	if len(vars) == 0 {
+		applyAuth(&req, vars)
+		return req
+	}
+	req.Path = ExpandString(req.Path, vars)
+	req.Body = ExpandString(req.Body, vars)
+	req.Query = ExpandString(req.Query, vars)
+	req.Variables = ExpandString(req.Variables, vars)
+	req.Headers = model.EncodeKeyValues(expandKeyValues(model.DecodeKeyValues(req.Headers), vars))
+	req.QueryParams = model.EncodeKeyValues(expandKeyValues(model.DecodeKeyValues(req.QueryParams), vars))
+	applyAuth(&req, vars)
+	return req
+}
+
+// ExpandString 替换字符串中的 {{name}} 变量
+func ExpandString(s string, vars map[string]string) string {
+	if !strings.Contains(s, "{{") {
+		return s
+	}
+	return variableRegex.ReplaceAllStringFunc(s, func(m string) string {
+		name := variableRegex.FindStringSubmatch(m)[1]
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		return m
+	})
+}
+
+func expandKeyValues(kvs []model.KeyValue, vars map[string]string) []model.KeyValue {
+	for i := range kvs {
+		kvs[i].Key = ExpandString(kvs[i].Key, vars)
+		kvs[i].Value = ExpandString(kvs[i].Value, vars)
+	}
+	return kvs
+}
+
+// Variables 将环境变量列表转换为变量表，仅包含启用的变量
+func Variables(kvs []model.KeyValue) map[string]string {
+	vars := make(map[string]string, len(kvs))
+	for _, kv := range kvs {
+		if kv.Enabled {
+			vars[kv.Key] = kv.Value
+		}
+	}
+	return vars
+}
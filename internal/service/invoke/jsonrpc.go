@@ -0,0 +1,248 @@
+package invoke
+
+import (
+	"FastGo/internal/model"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// ErrInvalidJSONRPC 请求体不是合法的 JSON-RPC 调用
+var ErrInvalidJSONRPC = errors.New("body must be a JSON-RPC call or an array of calls")
+
+// JSONRPCCall 用户编辑的调用，id 由服务端生成
+type JSONRPCCall struct {
+	Method       string          `json:"method"`
+	Params       json.RawMessage `json:"params,omitempty"`
+	Notification bool            `json:"notification,omitempty"` // 通知不带 id，服务端不返回响应
+}
+
+// JSONRPCResult JSON-RPC 响应，result 与 error 分开展示
+type JSONRPCResult struct {
+	Batch     bool              `json:"batch"`
+	Responses []JSONRPCResponse `json:"responses"`
+}
+
+// JSONRPCResponse 单个调用的响应
+type JSONRPCResponse struct {
+	ID           interface{}     `json:"id,omitempty"`
+	Method       string          `json:"method"`
+	Notification bool            `json:"notification,omitempty"`
+	Result       json.RawMessage `json:"result,omitempty"`
+	Error        *JSONRPCError   `json:"error,omitempty"`
+	Missing      bool            `json:"missing,omitempty"` // 服务端未返回该调用的响应
+}
+
+// JSONRPCError JSON-RPC 错误对象
+type JSONRPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+type jsonRPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *int            `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type jsonRPCResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *JSONRPCError   `json:"error"`
+}
+
+// JSONRPCInvoker 通过 HTTP 或 WebSocket（ws://、wss:// 地址）发送 JSON-RPC 2.0 调用
+type JSONRPCInvoker struct {
+	Client *http.Client
+	Dialer *websocket.Dialer
+}
+
+// ParseJSONRPCCalls 解析请求体，数组表示批量调用
+func ParseJSONRPCCalls(body string) ([]JSONRPCCall, bool, error) {
+	body = strings.TrimSpace(body)
+	var calls []JSONRPCCall
+	batch := strings.HasPrefix(body, "[")
+	if batch {
+		if err := json.Unmarshal([]byte(body), &calls); err != nil {
+			return nil, false, ErrInvalidJSONRPC
+		}
+	} else {
+		var call JSONRPCCall
+		if err := json.Unmarshal([]byte(body), &call); err != nil {
+			return nil, false, ErrInvalidJSONRPC
+		}
+		calls = []JSONRPCCall{call}
+	}
+	if len(calls) == 0 {
+		return nil, false, ErrInvalidJSONRPC
+	}
+	for _, c := range calls {
+		if c.Method == "" {
+			return nil, false, fmt.Errorf("%w: method is required", ErrInvalidJSONRPC)
+		}
+	}
+	return calls, batch, nil
+}
+
+func (i *JSONRPCInvoker) Invoke(ctx context.Context, req *model.Request) (*Result, error) {
+	calls, batch, err := ParseJSONRPCCalls(req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// 按调用顺序生成 id，通知不带 id
+	messages := make([]jsonRPCRequest, 0, len(calls))
+	rpc := &JSONRPCResult{Batch: batch, Responses: make([]JSONRPCResponse, 0, len(calls))}
+	pending := map[string]int{}
+	for n, c := range calls {
+		msg := jsonRPCRequest{JSONRPC: "2.0", Method: c.Method, Params: c.Params}
+		resp := JSONRPCResponse{Method: c.Method, Notification: c.Notification}
+		if !c.Notification {
+			id := n + 1
+			msg.ID, resp.ID, resp.Missing = &id, id, true
+			pending[fmt.Sprint(id)] = n
+		}
+		messages = append(messages, msg)
+		rpc.Responses = append(rpc.Responses, resp)
+	}
+
+	var payload []byte
+	if batch {
+		payload, _ = json.Marshal(messages)
+	} else {
+		payload, _ = json.Marshal(messages[0])
+	}
+
+	var result *Result
+	if strings.HasPrefix(req.Path, "ws://") || strings.HasPrefix(req.Path, "wss://") {
+		result, err = i.websocket(ctx, req, payload, pending, rpc)
+	} else {
+		result, err = i.http(ctx, req, payload, pending, rpc)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.JSONRPC = rpc
+	return result, nil
+}
+
+func (i *JSONRPCInvoker) http(ctx context.Context, req *model.Request, payload []byte, pending map[string]int, rpc *JSONRPCResult) (*Result, error) {
+	headers := model.DecodeKeyValues(req.Headers)
+	if !hasEnabledHeader(headers, "Content-Type") {
+		headers = append(headers, model.KeyValue{Key: "Content-Type", Value: "application/json", Enabled: true})
+	}
+	result, err := doHTTP(ctx, i.Client, http.MethodPost, requestURL(req), headers, string(payload))
+	if err != nil {
+		return nil, err
+	}
+	// 全部为通知时服务端不返回内容
+	if strings.TrimSpace(result.Body) != "" {
+		if err := applyJSONRPCResponses([]byte(result.Body), pending, rpc); err != nil {
+			result.Warnings = append(result.Warnings, err.Error())
+		}
+	}
+	return result, nil
+}
+
+func (i *JSONRPCInvoker) websocket(ctx context.Context, req *model.Request, payload []byte, pending map[string]int, rpc *JSONRPCResult) (*Result, error) {
+	dialer := i.Dialer
+	if dialer == nil {
+		dialer = websocket.DefaultDialer
+	}
+	header := http.Header{}
+	for _, h := range model.DecodeKeyValues(req.Headers) {
+		if h.Enabled && h.Key != "" {
+			header.Add(h.Key, h.Value)
+		}
+	}
+	conn, resp, err := dialer.DialContext(ctx, requestURL(req), header)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	result := &Result{Status: resp.Status, StatusCode: resp.StatusCode, Headers: responseHeaders(resp.Header)}
+	if err := conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+		return nil, err
+	}
+
+	// 读取消息直到所有调用都收到响应，服务端主动推送的其他消息忽略
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetReadDeadline(deadline)
+	} else {
+		conn.SetReadDeadline(time.Now().Add(defaultTimeout))
+	}
+	var received [][]byte
+	for len(pending) > 0 {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			result.Warnings = append(result.Warnings, "connection closed before all responses arrived: "+err.Error())
+			break
+		}
+		received = append(received, data)
+		if err := applyJSONRPCResponses(data, pending, rpc); err != nil {
+			result.Warnings = append(result.Warnings, err.Error())
+		}
+	}
+	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+
+	body := bytes.Join(received, []byte("\n"))
+	result.Body, result.Size = string(body), len(body)
+	return result, nil
+}
+
+// applyJSONRPCResponses 按 id 将响应对应到调用，处理后从 pending 中移除
+func applyJSONRPCResponses(data []byte, pending map[string]int, rpc *JSONRPCResult) error {
+	var list []jsonRPCResponse
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		if err := json.Unmarshal(trimmed, &list); err != nil {
+			return fmt.Errorf("invalid JSON-RPC response: %w", err)
+		}
+	} else {
+		var single jsonRPCResponse
+		if err := json.Unmarshal(trimmed, &single); err != nil {
+			return fmt.Errorf("invalid JSON-RPC response: %w", err)
+		}
+		list = []jsonRPCResponse{single}
+	}
+
+	for _, r := range list {
+		key := strings.Trim(string(r.ID), `"`)
+		n, ok := pending[key]
+		if !ok {
+			// id 为 null 的错误表示请求本身无法解析，归到第一个未完成的调用
+			if string(r.ID) != "null" || r.Error == nil || len(pending) == 0 {
+				continue
+			}
+			for k, v := range pending {
+				if !ok || v < n {
+					key, n, ok = k, v, true
+				}
+			}
+		}
+		delete(pending, key)
+		rpc.Responses[n].Result = r.Result
+		rpc.Responses[n].Error = r.Error
+		rpc.Responses[n].Missing = false
+	}
+	return nil
+}
+
+func hasEnabledHeader(headers []model.KeyValue, key string) bool {
+	for _, h := range headers {
+		if h.Enabled && strings.EqualFold(h.Key, key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package invoke
+
+import (
+	"FastGo/internal/model"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// jsonRPCEcho 返回 params，method 为 fail 时返回错误
+func jsonRPCEcho(data []byte) []byte {
+	var batch []map[string]interface{}
+	single := !strings.HasPrefix(strings.TrimSpace(string(data)), "[")
+	if single {
+		var m map[string]interface{}
+		json.Unmarshal(data, &m)
+		batch = []map[string]interface{}{m}
+	} else {
+		json.Unmarshal(data, &batch)
+	}
+	var out []map[string]interface{}
+	for _, m := range batch {
+		if _, ok := m["id"]; !ok {
+			continue
+		}
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": m["id"]}
+		if m["method"] == "fail" {
+			resp["error"] = map[string]interface{}{"code": -32601, "message": "Method not found"}
+		} else {
+			resp["result"] = m["params"]
+		}
+		out = append(out, resp)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	if single {
+		b, _ := json.Marshal(out[0])
+		return b
+	}
+	// 批量响应顺序与请求无关
+	out[0], out[len(out)-1] = out[len(out)-1], out[0]
+	b, _ := json.Marshal(out)
+	return b
+}
+
+func TestJSONRPCHTTPBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		w.Write(jsonRPCEcho(data))
+	}))
+	defer server.Close()
+
+	registry := NewRegistry(nil, nil)
+	req := model.Request{
+		Type: model.JSONRPC,
+		Path: server.URL,
+		Body: `[{"method":"echo","params":{"a":1}},{"method":"log","notification":true},{"method":"fail"}]`,
+		Assertions: `[{"target":"result.a","operator":"eq","value":1},
+			{"target":"responses.2.error.code","operator":"eq","value":-32601},
+			{"target":"error","operator":"not_exists"}]`,
+	}
+	_, result, err := registry.Invoke(context.Background(), &Call{Request: req})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rpc := result.JSONRPC
+	if !rpc.Batch || len(rpc.Responses) != 3 || !rpc.Responses[1].Notification {
+		t.Fatalf("unexpected responses: %+v", rpc)
+	}
+	if string(rpc.Responses[0].Result) != `{"a":1}` || rpc.Responses[2].Error == nil || rpc.Responses[2].Missing {
+		t.Fatalf("responses not matched by id: %+v", rpc.Responses)
+	}
+	for _, a := range result.Assertions {
+		if !a.Passed {
+			t.Fatalf("assertion failed: %+v", a)
+		}
+	}
+}
+
+func TestJSONRPCWebSocket(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","method":"tick"}`))
+		conn.WriteMessage(websocket.TextMessage, jsonRPCEcho(data))
+		conn.ReadMessage()
+	}))
+	defer server.Close()
+
+	req := &model.Request{Path: "ws" + strings.TrimPrefix(server.URL, "http"), Body: `{"method":"fail"}`}
+	result, err := (&JSONRPCInvoker{}).Invoke(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := result.JSONRPC.Responses[0]; r.Error == nil || r.Error.Code != -32601 {
+		t.Fatalf("unexpected response: %+v", result.JSONRPC)
+	}
+}
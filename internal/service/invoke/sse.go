@@ -0,0 +1,266 @@
+package invoke
+
+import (
+	"FastGo/internal/model"
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SSE 默认重连间隔与重连次数
+const (
+	defaultSSERetry      = 3 * time.Second
+	defaultSSEReconnects = 10
+)
+
+// SSE 消息类型
+const (
+	SSEConnecting = "connecting" // 开始连接
+	SSEOpen       = "open"       // 连接建立
+	SSEEventType  = "event"      // 收到事件
+	SSEReconnect  = "reconnect"  // 连接断开，准备重连
+	SSEError      = "error"      // 连接失败
+	SSEClose      = "close"      // 流结束
+)
+
+// ErrSSEReconnectsExhausted 重连次数已用完
+var ErrSSEReconnectsExhausted = errors.New("sse reconnect attempts exhausted")
+
+// SSEMessage 推送给客户端并记录在执行记录中的消息
+type SSEMessage struct {
+	Type       string    `json:"type"`
+	Time       time.Time `json:"time"`
+	Event      *SSEEvent `json:"event,omitempty"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Attempt    int       `json:"attempt,omitempty"`
+	Delay      int64     `json:"delay,omitempty"` // 重连等待时间，毫秒
+	Error      string    `json:"error,omitempty"`
+}
+
+// SSEEvent 解析后的事件
+type SSEEvent struct {
+	ID    string `json:"id,omitempty"`
+	Event string `json:"event"`
+	Data  string `json:"data"`
+	Retry int    `json:"retry,omitempty"`
+}
+
+// SSEStreamer 连接 SSE 地址并持续读取事件，断开后携带 Last-Event-ID 重连
+// 重连次数取请求的 RetryCount，为 0 时使用默认值
+type SSEStreamer struct {
+	Client *http.Client
+}
+
+// Stream 读取事件直到 ctx 取消、服务端返回非 200 状态或重连次数用完，每条消息通过 emit 推送
+func (s *SSEStreamer) Stream(ctx context.Context, req *model.Request, emit func(SSEMessage)) error {
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	maxReconnects := req.RetryCount
+	if maxReconnects <= 0 {
+		maxReconnects = defaultSSEReconnects
+	}
+
+	parser := &SSEParser{}
+	retry := defaultSSERetry
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			if attempt > maxReconnects {
+				return ErrSSEReconnectsExhausted
+			}
+			emit(SSEMessage{Type: SSEReconnect, Time: time.Now(), Attempt: attempt, Delay: retry.Milliseconds()})
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(retry):
+			}
+		}
+
+		err := s.connect(ctx, client, req, parser, func(m SSEMessage) {
+			if m.Type == SSEOpen {
+				// 连接成功后重新计算重连次数
+				attempt = 0
+			}
+			if m.Event != nil && m.Event.Retry > 0 {
+				retry = time.Duration(m.Event.Retry) * time.Millisecond
+			}
+			emit(m)
+		})
+		if ctx.Err() != nil {
+			return nil
+		}
+		var status *sseStatusError
+		if errors.As(err, &status) {
+			// 204 表示服务端要求停止重连
+			if status.code == http.StatusNoContent {
+				return nil
+			}
+			return err
+		}
+		if err != nil {
+			emit(SSEMessage{Type: SSEError, Time: time.Now(), Error: err.Error()})
+		}
+	}
+}
+
+type sseStatusError struct {
+	code   int
+	status string
+}
+
+func (e *sseStatusError) Error() string {
+	return "unexpected status " + e.status
+}
+
+func (s *SSEStreamer) connect(ctx context.Context, client *http.Client, req *model.Request, parser *SSEParser, emit func(SSEMessage)) error {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL(req), nil)
+	if err != nil {
+		return err
+	}
+	for _, h := range model.DecodeKeyValues(req.Headers) {
+		if h.Enabled && h.Key != "" {
+			httpReq.Header.Set(h.Key, h.Value)
+		}
+	}
+	httpReq.Header.Set("Accept", "text/event-stream")
+	httpReq.Header.Set("Cache-Control", "no-cache")
+	if parser.LastEventID != "" {
+		httpReq.Header.Set("Last-Event-ID", parser.LastEventID)
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &sseStatusError{code: resp.StatusCode, status: resp.Status}
+	}
+	emit(SSEMessage{Type: SSEOpen, Time: time.Now(), StatusCode: resp.StatusCode})
+
+	return parser.Read(resp.Body, func(e SSEEvent) {
+		emit(SSEMessage{Type: SSEEventType, Time: time.Now(), Event: &e})
+	})
+}
+
+// SSEParser 按 text/event-stream 规范解析事件，LastEventID 在重连之间保留
+type SSEParser struct {
+	LastEventID string
+	afterCR     bool
+}
+
+// Read 读取事件直到流结束，流正常结束时返回 io.ErrUnexpectedEOF 以触发重连
+func (p *SSEParser) Read(r io.Reader, emit func(SSEEvent)) error {
+	reader := bufio.NewReader(r)
+	p.afterCR = false
+	var data strings.Builder
+	var event string
+	var retry int
+	hasData := false
+
+	for {
+		line, err := p.readLine(reader)
+		if err != nil {
+			if err == io.EOF {
+				return io.ErrUnexpectedEOF
+			}
+			return err
+		}
+
+		if line == "" {
+			if hasData {
+				if event == "" {
+					event = "message"
+				}
+				emit(SSEEvent{ID: p.LastEventID, Event: event, Data: strings.TrimSuffix(data.String(), "\n"), Retry: retry})
+			} else if retry > 0 {
+				emit(SSEEvent{ID: p.LastEventID, Retry: retry})
+			}
+			data.Reset()
+			event, retry, hasData = "", 0, false
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event = value
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+			hasData = true
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				p.LastEventID = value
+			}
+		case "retry":
+			if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+				retry = n
+			}
+		}
+	}
+}
+
+// readLine 读取一行，兼容 \n、\r\n 和 \r 三种换行
+// \r 之后的 \n 在下一次读取时跳过，避免等待后续数据
+func (p *SSEParser) readLine(r *bufio.Reader) (string, error) {
+	var b strings.Builder
+	for {
+		c, err := r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		if p.afterCR {
+			p.afterCR = false
+			if c == '\n' {
+				continue
+			}
+		}
+		switch c {
+		case '\n':
+			return b.String(), nil
+		case '\r':
+			p.afterCR = true
+			return b.String(), nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+}
+
+// SSEResult 汇总事件流，作为执行记录的响应
+func SSEResult(transcript []SSEMessage, err error) *Result {
+	result := &Result{}
+	events := 0
+	for _, m := range transcript {
+		switch m.Type {
+		case SSEOpen:
+			result.StatusCode = m.StatusCode
+			result.Status = strconv.Itoa(m.StatusCode) + " " + http.StatusText(m.StatusCode)
+		case SSEEventType:
+			events++
+		}
+	}
+	var status *sseStatusError
+	if errors.As(err, &status) {
+		result.StatusCode = status.code
+		result.Status = status.status
+	}
+	result.Body = fmt.Sprintf("%d events", events)
+	if len(transcript) > 0 {
+		result.Duration = transcript[len(transcript)-1].Time.Sub(transcript[0].Time).Milliseconds()
+	}
+	return result
+}
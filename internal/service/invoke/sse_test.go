@@ -0,0 +1,62 @@
+package invoke
+
+import (
+	"FastGo/internal/model"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSSEParser(t *testing.T) {
+	stream := ": comment\r\nevent: update\r\nid: 7\r\ndata: a\r\ndata:b\r\n\r\ndata: plain\rretry: 1500\r\r"
+	p := &SSEParser{}
+	var events []SSEEvent
+	err := p.Read(strings.NewReader(stream), func(e SSEEvent) { events = append(events, e) })
+	if err == nil {
+		t.Fatal("stream end should be reported for reconnect")
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %+v", events)
+	}
+	if events[0].Event != "update" || events[0].ID != "7" || events[0].Data != "a\nb" {
+		t.Fatalf("unexpected first event: %+v", events[0])
+	}
+	if events[1].Event != "message" || events[1].Data != "plain" || events[1].Retry != 1500 || events[1].ID != "7" {
+		t.Fatalf("unexpected second event: %+v", events[1])
+	}
+}
+
+func TestSSEStreamReconnect(t *testing.T) {
+	var calls int32
+	var lastEventID atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		if n > 2 {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		lastEventID.Store(r.Header.Get("Last-Event-ID"))
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "retry: 1\nid: %d\ndata: tick\n\n", n)
+	}))
+	defer server.Close()
+
+	var messages []SSEMessage
+	err := (&SSEStreamer{}).Stream(context.Background(), &model.Request{Path: server.URL}, func(m SSEMessage) {
+		messages = append(messages, m)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastEventID.Load() != "1" {
+		t.Fatalf("reconnect should send Last-Event-ID, got %v", lastEventID.Load())
+	}
+	result := SSEResult(messages, err)
+	if result.Body != "2 events" {
+		t.Fatalf("unexpected transcript: %+v", messages)
+	}
+}
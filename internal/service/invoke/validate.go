@@ -0,0 +1,156 @@
+package invoke
+
+import (
+	"FastGo/internal/model"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// 各请求类型可用的方法和地址 scheme
+var (
+	httpMethods = map[model.RequestMethod]bool{
+		model.GET: true, model.POST: true, model.PUT: true, model.DELETE: true,
+		model.PATCH: true, model.HEAD: true, model.OPTIONS: true,
+	}
+	streamMethods = map[model.RequestMethod]bool{model.GET: true, model.POST: true}
+	httpSchemes   = []string{"http", "https"}
+	wsSchemes     = []string{"ws", "wss"}
+)
+
+// Validate 按请求类型检查保存前的请求
+// 地址为空视为草稿不做检查，以 / 开头的相对地址在发送时拼接集合或文件夹的 BaseURL；
+// 包含 {{变量}} 的地址和请求体要等发送时才能确定，跳过格式检查
+func Validate(req *model.Request) error {
+	for _, kv := range model.DecodeKeyValues(req.Headers) {
+		if kv.Enabled && strings.TrimSpace(kv.Key) == "" {
+			return fmt.Errorf("enabled header must have a key")
+		}
+	}
+	for _, kv := range model.DecodeKeyValues(req.QueryParams) {
+		if kv.Enabled && strings.TrimSpace(kv.Key) == "" {
+			return fmt.Errorf("enabled query param must have a key")
+		}
+	}
+	if err := validateAuth(req.Auth); err != nil {
+		return err
+	}
+	if _, err := ParseAssertions(req.Assertions); err != nil {
+		return err
+	}
+
+	switch req.Type {
+	case model.HTTP1:
+		if !httpMethods[req.Method] {
+			return fmt.Errorf("unsupported HTTP method %q", req.Method)
+		}
+		return validateURL(req.Path, httpSchemes...)
+	case model.SSE:
+		if !streamMethods[req.Method] {
+			return fmt.Errorf("SSE requests must use GET or POST")
+		}
+		return validateURL(req.Path, httpSchemes...)
+	case model.WebSocket:
+		return validateURL(req.Path, wsSchemes...)
+	case model.GraphQL:
+		if !streamMethods[req.Method] {
+			return fmt.Errorf("GraphQL requests must use GET or POST")
+		}
+		if err := validateURL(req.Path, httpSchemes...); err != nil {
+			return err
+		}
+		if strings.TrimSpace(req.Variables) != "" && !hasVariables(req.Variables) {
+			var vars map[string]interface{}
+			if err := json.Unmarshal([]byte(req.Variables), &vars); err != nil {
+				return fmt.Errorf("GraphQL variables must be a JSON object")
+			}
+		}
+		return nil
+	case model.JSONRPC:
+		if err := validateURL(req.Path, append(httpSchemes, wsSchemes...)...); err != nil {
+			return err
+		}
+		if strings.TrimSpace(req.Body) != "" && !hasVariables(req.Body) {
+			if _, _, err := ParseJSONRPCCalls(req.Body); err != nil {
+				return err
+			}
+		}
+		return nil
+	case model.GRPC1:
+		switch req.Transport {
+		case "", TransportGRPC, TransportGRPCWeb, TransportGRPCWebText, TransportConnectJSON, TransportConnectPB:
+		default:
+			return fmt.Errorf("unsupported gRPC transport %q", req.Transport)
+		}
+		if req.Path != "" && !strings.HasPrefix(req.Path, "/") && !hasVariables(req.Path) {
+			if _, err := ParseGRPCTarget(req.Path, req.Transport); err != nil {
+				return err
+			}
+		}
+		if strings.TrimSpace(req.Body) != "" && !hasVariables(req.Body) && !json.Valid([]byte(req.Body)) {
+			return fmt.Errorf("gRPC body must be a JSON message or an array of messages")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported request type %q", req.Type)
+	}
+}
+
+// ValidateDefaults 检查集合或文件夹上的默认值，BaseURL 可用于任意请求类型，只检查格式
+func ValidateDefaults(d *model.Defaults) error {
+	for _, kv := range model.DecodeKeyValues(d.Headers) {
+		if kv.Enabled && strings.TrimSpace(kv.Key) == "" {
+			return fmt.Errorf("enabled header must have a key")
+		}
+	}
+	if d.Timeout < 0 || d.RetryCount < 0 {
+		return fmt.Errorf("timeout and retry count must not be negative")
+	}
+	if d.BaseURL != "" && !hasVariables(d.BaseURL) {
+		if u, err := url.Parse(d.BaseURL); err != nil || u.Host == "" {
+			return fmt.Errorf("invalid base url %q", d.BaseURL)
+		}
+	}
+	return validateAuth(d.Auth)
+}
+
+// validateAuth 检查认证方式及其必填项
+func validateAuth(s string) error {
+	auth := model.DecodeAuth(s)
+	switch auth.Type {
+	case model.AuthInherit, model.AuthNone, model.AuthBearer, model.AuthBasic:
+		return nil
+	case model.AuthAPIKey:
+		if auth.Key == "" {
+			return fmt.Errorf("api key auth requires a key")
+		}
+		if auth.In != "" && auth.In != "header" && auth.In != "query" {
+			return fmt.Errorf("api key must be sent in header or query")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported auth type %q", auth.Type)
+	}
+}
+
+// validateURL 检查地址的 scheme，地址为空、相对地址或包含变量时跳过
+func validateURL(path string, schemes ...string) error {
+	if path == "" || strings.HasPrefix(path, "/") || hasVariables(path) {
+		return nil
+	}
+	u, err := url.Parse(path)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid url %q", path)
+	}
+	for _, s := range schemes {
+		if strings.EqualFold(u.Scheme, s) {
+			return nil
+		}
+	}
+	return fmt.Errorf("url scheme must be one of %s", strings.Join(schemes, ", "))
+}
+
+func hasVariables(s string) bool {
+	return variableRegex.MatchString(s)
+}
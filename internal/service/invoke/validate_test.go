@@ -0,0 +1,39 @@
+package invoke
+
+import (
+	"FastGo/internal/model"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name string
+		req  model.Request
+		ok   bool
+	}{
+		{"http draft", model.Request{Type: model.HTTP1, Method: model.GET}, true},
+		{"http url", model.Request{Type: model.HTTP1, Method: model.PATCH, Path: "https://api.example.com/users"}, true},
+		{"http relative url", model.Request{Type: model.HTTP1, Method: model.GET, Path: "/users"}, true},
+		{"bad auth", model.Request{Type: model.HTTP1, Method: model.GET, Auth: `{"type":"digest"}`}, false},
+		{"http variable url", model.Request{Type: model.HTTP1, Method: model.GET, Path: "{{base}}/users"}, true},
+		{"http bad scheme", model.Request{Type: model.HTTP1, Method: model.GET, Path: "ws://example.com"}, false},
+		{"http bad method", model.Request{Type: model.HTTP1, Method: "TRACE"}, false},
+		{"websocket", model.Request{Type: model.WebSocket, Path: "wss://example.com/ws"}, true},
+		{"sse put", model.Request{Type: model.SSE, Method: model.PUT}, false},
+		{"graphql variables", model.Request{Type: model.GraphQL, Method: model.POST, Variables: `[1]`}, false},
+		{"jsonrpc body", model.Request{Type: model.JSONRPC, Body: `{"jsonrpc":"2.0"}`}, false},
+		{"jsonrpc variable body", model.Request{Type: model.JSONRPC, Body: `{"method":"add","params":{{params}}}`}, true},
+		{"grpc path", model.Request{Type: model.GRPC1, Path: "localhost:50051/demo.Greeter/SayHello"}, true},
+		{"grpc bad path", model.Request{Type: model.GRPC1, Path: "localhost:50051"}, false},
+		{"grpc bad transport", model.Request{Type: model.GRPC1, Transport: "thrift"}, false},
+		{"header without key", model.Request{Type: model.HTTP1, Method: model.GET, Headers: `[{"key":"","value":"x","enabled":true}]`}, false},
+		{"bad assertions", model.Request{Type: model.HTTP1, Method: model.GET, Assertions: `{`}, false},
+		{"unknown type", model.Request{Type: "SOAP"}, false},
+	}
+	for _, c := range cases {
+		err := Validate(&c.req)
+		if (err == nil) != c.ok {
+			t.Errorf("%s: unexpected result %v", c.name, err)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"FastGo/internal/model"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// 冲突的处理方式
+const (
+	ResolveOurs   = "ours"   // 采用分支中的版本
+	ResolveTheirs = "theirs" // 保留源集合中的版本
+)
+
+// 条目在源集合中的变化
+const (
+	ChangeAdded    = "added"
+	ChangeModified = "modified"
+	ChangeDeleted  = "deleted"
+)
+
+// 冲突类型
+const (
+	ConflictEdit   = "edit"   // 两边修改了同一字段且结果不同
+	ConflictDelete = "delete" // 一边删除了条目，另一边修改了条目
+)
+
+// ErrUnresolvedConflicts 合并前仍有未处理的冲突
+var ErrUnresolvedConflicts = errors.New("merge has unresolved conflicts")
+
+// ForkFolder 集合状态中的文件夹，Parent 为上级文件夹的条目 ID，为空表示集合根目录
+type ForkFolder struct {
+	Name     string         `json:"name"`
+	Parent   string         `json:"parent"`
+	Defaults model.Defaults `json:"defaults"` // 请求默认值，整体作为一个字段合并
+	Tags     string         `json:"tags"`     // 标签，JSON 数组
+}
+
+// CollectionState 集合中文件夹和请求的内容，按条目 ID 索引，不包含排序
+// 请求快照中的 FolderID 为所在文件夹的条目 ID，CollectionID 为空
+type CollectionState struct {
+	Folders  map[string]ForkFolder      `json:"folders"`
+	Requests map[string]RequestSnapshot `json:"requests"`
+}
+
+// FieldConflict 冲突条目中一个字段在三个版本中的取值，条目被删除的一方为 nil
+type FieldConflict struct {
+	Field  string      `json:"field"`
+	Base   interface{} `json:"base"`
+	Ours   interface{} `json:"ours"`
+	Theirs interface{} `json:"theirs"`
+}
+
+// MergeChange 合并时源集合中一个条目的变化
+type MergeChange struct {
+	Kind       string          `json:"kind"` // folder 或 request
+	Key        string          `json:"key"`  // 条目 ID，分支中新增的条目为分支中的 ID
+	Name       string          `json:"name"`
+	Action     string          `json:"action"`
+	Fields     []string        `json:"fields"`               // 将写入源集合的字段
+	Conflict   string          `json:"conflict,omitempty"`   // 冲突类型，为空表示没有冲突
+	Conflicts  []FieldConflict `json:"conflicts,omitempty"`  // 冲突的字段
+	Resolution string          `json:"resolution,omitempty"` // 冲突的处理方式
+
+	write  bool          // 合并时是否写入源集合
+	result reflect.Value // 合并后的内容，无效值表示删除
+}
+
+// Unresolved 是否为尚未处理的冲突
+func (c *MergeChange) Unresolved() bool {
+	return c.Conflict != "" && c.Resolution == ""
+}
+
+// ThreeWayMerge 比较共同版本（base）、分支（ours）和源集合（theirs），返回合并分支修改时源集合中的变化
+// 只有分支中的修改写入源集合，源集合自己的修改保持不变；请求逐字段合并，两边修改了同一字段时为冲突，
+// 冲突按 resolutions（条目 ID 到 ours/theirs）处理，未处理的冲突不写入
+func ThreeWayMerge(base, ours, theirs *CollectionState, resolutions map[string]string) []MergeChange {
+	var changes []MergeChange
+	for _, key := range unionKeys(base.Folders, ours.Folders, theirs.Folders) {
+		b, o, t := lookup(base.Folders, key), lookup(ours.Folders, key), lookup(theirs.Folders, key)
+		if c, ok := mergeItem(KindFolder, key, b, o, t, resolutions[key]); ok {
+			changes = append(changes, c)
+		}
+	}
+	for _, key := range unionKeys(base.Requests, ours.Requests, theirs.Requests) {
+		b, o, t := lookup(base.Requests, key), lookup(ours.Requests, key), lookup(theirs.Requests, key)
+		if c, ok := mergeItem(KindRequest, key, b, o, t, resolutions[key]); ok {
+			changes = append(changes, c)
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Kind != changes[j].Kind {
+			return changes[i].Kind == KindFolder
+		}
+		if changes[i].Name != changes[j].Name {
+			return changes[i].Name < changes[j].Name
+		}
+		return changes[i].Key < changes[j].Key
+	})
+	if changes == nil {
+		changes = []MergeChange{}
+	}
+	return changes
+}
+
+// unionKeys 返回三个版本中全部条目 ID
+func unionKeys(maps ...interface{}) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, m := range maps {
+		for _, k := range reflect.ValueOf(m).MapKeys() {
+			if key := k.String(); !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// lookup 取出条目，不存在时返回无效值
+func lookup(m interface{}, key string) reflect.Value {
+	return reflect.ValueOf(m).MapIndex(reflect.ValueOf(key))
+}
+
+func sameItem(a, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// mergeItem 合并单个条目，分支中没有修改时返回 false
+func mergeItem(kind, key string, b, o, t reflect.Value, resolution string) (MergeChange, bool) {
+	if sameItem(o, b) {
+		return MergeChange{}, false
+	}
+	change := MergeChange{Kind: kind, Key: key, Fields: []string{}}
+	for _, v := range []reflect.Value{o, t, b} {
+		if v.IsValid() {
+			change.Name = v.FieldByName("Name").String()
+			break
+		}
+	}
+
+	switch {
+	case !b.IsValid():
+		// 分支中新增
+		change.Action, change.write, change.result = ChangeAdded, true, o
+		change.Fields = changedFields(reflect.Zero(o.Type()), o)
+
+	case !o.IsValid():
+		// 分支中删除，源集合已删除时无需处理
+		if !t.IsValid() {
+			return MergeChange{}, false
+		}
+		change.Action = ChangeDeleted
+		if !sameItem(t, b) {
+			change.Conflict = ConflictDelete
+			change.Conflicts = fieldConflicts(b, o, t, changedFields(b, t))
+			change.Resolution = validResolution(resolution)
+		}
+		change.write = change.Conflict == "" || change.Resolution == ResolveOurs
+
+	case !t.IsValid():
+		// 分支中修改，源集合中已删除，采用分支版本时重新创建
+		change.Action, change.result = ChangeAdded, o
+		change.Fields = changedFields(reflect.Zero(o.Type()), o)
+		change.Conflict = ConflictDelete
+		change.Conflicts = fieldConflicts(b, o, t, changedFields(b, o))
+		change.Resolution = validResolution(resolution)
+		change.write = change.Resolution == ResolveOurs
+
+	default:
+		change.Action = ChangeModified
+		change.Resolution = validResolution(resolution)
+		merged := reflect.New(t.Type()).Elem()
+		merged.Set(t)
+		var conflicted []string
+		for i := 0; i < t.NumField(); i++ {
+			bf, of, tf := b.Field(i), o.Field(i), t.Field(i)
+			switch {
+			case sameItem(of, bf):
+			case sameItem(tf, bf), sameItem(of, tf):
+				merged.Field(i).Set(of)
+			default:
+				conflicted = append(conflicted, jsonName(t.Type().Field(i)))
+				if change.Resolution == ResolveOurs {
+					merged.Field(i).Set(of)
+				}
+			}
+		}
+		change.Fields = changedFields(t, merged)
+		if len(conflicted) > 0 {
+			change.Conflict = ConflictEdit
+			change.Conflicts = fieldConflicts(b, o, t, conflicted)
+		} else {
+			change.Resolution = ""
+		}
+		if len(change.Fields) == 0 && change.Conflict == "" {
+			return MergeChange{}, false
+		}
+		change.write = len(change.Fields) > 0
+		change.result = merged
+	}
+	return change, true
+}
+
+func validResolution(r string) string {
+	if r == ResolveOurs || r == ResolveTheirs {
+		return r
+	}
+	return ""
+}
+
+// changedFields 返回两个版本中取值不同的字段
+func changedFields(from, to reflect.Value) []string {
+	fields := []string{}
+	for i := 0; i < to.NumField(); i++ {
+		if !reflect.DeepEqual(from.Field(i).Interface(), to.Field(i).Interface()) {
+			fields = append(fields, jsonName(to.Type().Field(i)))
+		}
+	}
+	return fields
+}
+
+// fieldConflicts 列出字段在三个版本中的取值
+func fieldConflicts(b, o, t reflect.Value, fields []string) []FieldConflict {
+	value := func(v reflect.Value, field string) interface{} {
+		if !v.IsValid() {
+			return nil
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if jsonName(v.Type().Field(i)) == field {
+				return v.Field(i).Interface()
+			}
+		}
+		return nil
+	}
+	conflicts := make([]FieldConflict, 0, len(fields))
+	for _, f := range fields {
+		conflicts = append(conflicts, FieldConflict{Field: f, Base: value(b, f), Ours: value(o, f), Theirs: value(t, f)})
+	}
+	return conflicts
+}
+
+func jsonName(f reflect.StructField) string {
+	return strings.Split(f.Tag.Get("json"), ",")[0]
+}
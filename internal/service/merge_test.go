@@ -0,0 +1,113 @@
+package service
+
+import "testing"
+
+func testStates() (base, ours, theirs *CollectionState) {
+	build := func() *CollectionState {
+		return &CollectionState{
+			Folders: map[string]ForkFolder{"f1": {Name: "Users"}},
+			Requests: map[string]RequestSnapshot{
+				"r1": {Name: "List users", FolderID: "f1", Method: "GET", Path: "/users", Timeout: 1000},
+				"r2": {Name: "Get user", FolderID: "f1", Method: "GET", Path: "/users/:id"},
+			},
+		}
+	}
+	return build(), build(), build()
+}
+
+func findChange(changes []MergeChange, key string) *MergeChange {
+	for i := range changes {
+		if changes[i].Key == key {
+			return &changes[i]
+		}
+	}
+	return nil
+}
+
+func TestThreeWayMerge(t *testing.T) {
+	base, ours, theirs := testStates()
+	ours.Folders["f2"] = ForkFolder{Name: "Orders"}
+	ours.Requests["r3"] = RequestSnapshot{Name: "List orders", FolderID: "f2", Method: "GET", Path: "/orders"}
+
+	// 两边修改了同一请求的不同字段
+	r1 := ours.Requests["r1"]
+	r1.Method = "POST"
+	ours.Requests["r1"] = r1
+	r1 = theirs.Requests["r1"]
+	r1.Timeout = 3000
+	theirs.Requests["r1"] = r1
+
+	changes := ThreeWayMerge(base, ours, theirs, nil)
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes, got %+v", changes)
+	}
+	if c := changes[0]; c.Kind != KindFolder || c.Key != "f2" || c.Action != ChangeAdded {
+		t.Errorf("unexpected folder change %+v", c)
+	}
+	c := findChange(changes, "r1")
+	if c == nil || c.Action != ChangeModified || c.Conflict != "" || len(c.Fields) != 1 || c.Fields[0] != "method" {
+		t.Fatalf("unexpected request change %+v", c)
+	}
+	merged := c.result.Interface().(RequestSnapshot)
+	if merged.Method != "POST" || merged.Timeout != 3000 {
+		t.Errorf("expected both edits to be kept, got %+v", merged)
+	}
+	if c := findChange(changes, "r3"); c == nil || c.Action != ChangeAdded || c.Unresolved() {
+		t.Errorf("unexpected added request %+v", c)
+	}
+
+	// 分支没有修改时没有变化
+	if changes := ThreeWayMerge(base, base, theirs, nil); len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+}
+
+func TestThreeWayMergeEditConflict(t *testing.T) {
+	base, ours, theirs := testStates()
+	r2 := ours.Requests["r2"]
+	r2.Path = "/v2/users/:id"
+	ours.Requests["r2"] = r2
+	r2 = theirs.Requests["r2"]
+	r2.Path = "/users/{id}"
+	theirs.Requests["r2"] = r2
+
+	c := findChange(ThreeWayMerge(base, ours, theirs, nil), "r2")
+	if c == nil || c.Conflict != ConflictEdit || !c.Unresolved() || c.write {
+		t.Fatalf("expected unresolved edit conflict, got %+v", c)
+	}
+	if len(c.Conflicts) != 1 || c.Conflicts[0].Field != "path" || c.Conflicts[0].Theirs != "/users/{id}" {
+		t.Errorf("unexpected conflicts %+v", c.Conflicts)
+	}
+
+	c = findChange(ThreeWayMerge(base, ours, theirs, map[string]string{"r2": ResolveOurs}), "r2")
+	if c == nil || c.Unresolved() || !c.write || c.result.Interface().(RequestSnapshot).Path != "/v2/users/:id" {
+		t.Errorf("expected ours to be written, got %+v", c)
+	}
+	c = findChange(ThreeWayMerge(base, ours, theirs, map[string]string{"r2": ResolveTheirs}), "r2")
+	if c == nil || c.Unresolved() || c.write {
+		t.Errorf("expected theirs to be kept, got %+v", c)
+	}
+}
+
+func TestThreeWayMergeDeleteConflict(t *testing.T) {
+	base, ours, theirs := testStates()
+	delete(ours.Requests, "r1")
+	r1 := theirs.Requests["r1"]
+	r1.Name = "List all users"
+	theirs.Requests["r1"] = r1
+	delete(ours.Requests, "r2")
+
+	changes := ThreeWayMerge(base, ours, theirs, nil)
+	if c := findChange(changes, "r2"); c == nil || c.Action != ChangeDeleted || c.Conflict != "" || !c.write {
+		t.Errorf("expected clean delete, got %+v", c)
+	}
+	c := findChange(changes, "r1")
+	if c == nil || c.Conflict != ConflictDelete || !c.Unresolved() {
+		t.Fatalf("expected delete conflict, got %+v", c)
+	}
+
+	c = findChange(ThreeWayMerge(base, ours, theirs, map[string]string{"r1": ResolveOurs}), "r1")
+	if c == nil || !c.write || c.result.IsValid() {
+		t.Errorf("expected delete to be written, got %+v", c)
+	}
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"FastGo/internal/model"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// 排序项类型
+const (
+	KindFolder  = "folder"
+	KindRequest = "request"
+)
+
+// 排序错误
+var (
+	ErrNotSibling         = errors.New("items must be direct children of the same parent")
+	ErrIncompleteSiblings = errors.New("items must include every child of the parent")
+)
+
+// ReorderItem 拖拽排序后的同级项，ID 为 FolderID 或 RequestID
+type ReorderItem struct {
+	Kind string `json:"kind" binding:"required,oneof=folder request"`
+	ID   string `json:"id" binding:"required"`
+}
+
+// children 限定为集合中 parentID 的直接子文件夹或请求，parentID 为空表示集合根目录
+func children(tx *gorm.DB, table interface{}, collectionID, parentID string) *gorm.DB {
+	query := tx.Model(table).Where("collection_id = ?", collectionID)
+	if _, ok := table.(*model.Folder); !ok {
+		return query.Where("folder_id = ?", parentID)
+	}
+	if parentID == "" {
+		return query.Where("folder_id NOT IN (?)", tx.Model(&model.FolderClosure{}).Select("descendant").Where("depth = 1"))
+	}
+	return query.Where("folder_id IN (?)", tx.Model(&model.FolderClosure{}).Select("descendant").Where("ancestor = ? AND depth = 1", parentID))
+}
+
+// NextSortOrder 返回新建文件夹或请求在 parentID 下的排序值，排在同级已有项之后
+func NextSortOrder(tx *gorm.DB, table interface{}, collectionID, parentID string) (int, error) {
+	var max *int
+	err := children(tx, table, collectionID, parentID).Select("MAX(sort_order)").Scan(&max).Error
+	if err != nil || max == nil {
+		return 0, err
+	}
+	return *max + 1, nil
+}
+
+// Reorder 按传入顺序重写同级文件夹和请求的排序值，parentID 为空表示集合根目录
+// 传入的项必须是该父目录下的全部子项，文件夹与请求分别排序，树中同级先列文件夹再列请求，需要在事务中调用
+func Reorder(tx *gorm.DB, collectionID, parentID string, items []ReorderItem) error {
+	var folderIDs, requestIDs []string
+	for _, item := range items {
+		switch item.Kind {
+		case KindFolder:
+			folderIDs = append(folderIDs, item.ID)
+		case KindRequest:
+			requestIDs = append(requestIDs, item.ID)
+		default:
+			return fmt.Errorf("unknown item kind %q", item.Kind)
+		}
+	}
+
+	groups := []struct {
+		table interface{}
+		field string
+		ids   []string
+	}{
+		{&model.Folder{}, "folder_id", folderIDs},
+		{&model.Request{}, "request_id", requestIDs},
+	}
+	for _, g := range groups {
+		var matched, total int64
+		if len(g.ids) > 0 {
+			err := children(tx, g.table, collectionID, parentID).Where(g.field+" IN (?)", g.ids).Count(&matched).Error
+			if err != nil {
+				return err
+			}
+			if matched != int64(len(g.ids)) {
+				return ErrNotSibling
+			}
+		}
+		if err := children(tx, g.table, collectionID, parentID).Count(&total).Error; err != nil {
+			return err
+		}
+		if total != matched {
+			return ErrIncompleteSiblings
+		}
+	}
+
+	for i, id := range folderIDs {
+		if err := tx.Model(&model.Folder{}).Where("folder_id = ?", id).Update("sort_order", i).Error; err != nil {
+			return err
+		}
+	}
+	for i, id := range requestIDs {
+		if err := tx.Model(&model.Request{}).Where("request_id = ?", id).Update("sort_order", i).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OrderedRequests 按树中的显示顺序展开请求：同级先递归文件夹，再列请求
+// folderID 不为空时只展开该文件夹，找不到时返回 false
+func (t *CollectionTree) OrderedRequests(folderID string) ([]*model.Request, bool) {
+	var list []*model.Request
+	var walk func(nodes []*FolderNode, requests []*model.Request)
+	walk = func(nodes []*FolderNode, requests []*model.Request) {
+		for _, n := range nodes {
+			walk(n.Folders, n.Requests)
+		}
+		list = append(list, requests...)
+	}
+
+	if folderID == "" {
+		walk(t.Folders, t.Requests)
+		return list, true
+	}
+	var find func(nodes []*FolderNode) *FolderNode
+	find = func(nodes []*FolderNode) *FolderNode {
+		for _, n := range nodes {
+			if n.Folder.FolderID == folderID {
+				return n
+			}
+			if found := find(n.Folders); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+	node := find(t.Folders)
+	if node == nil {
+		return nil, false
+	}
+	walk(node.Folders, node.Requests)
+	return list, true
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"FastGo/internal/model"
+	"reflect"
+	"testing"
+)
+
+func TestOrderedRequests(t *testing.T) {
+	req := func(id string) *model.Request { return &model.Request{RequestID: id} }
+	tree := &CollectionTree{
+		Folders: []*FolderNode{
+			{
+				Folder: model.Folder{FolderID: "f1"},
+				Folders: []*FolderNode{
+					{Folder: model.Folder{FolderID: "f2"}, Requests: []*model.Request{req("r3")}},
+				},
+				Requests: []*model.Request{req("r2")},
+			},
+		},
+		Requests: []*model.Request{req("r1")},
+	}
+
+	ids := func(list []*model.Request) []string {
+		out := []string{}
+		for _, r := range list {
+			out = append(out, r.RequestID)
+		}
+		return out
+	}
+
+	list, ok := tree.OrderedRequests("")
+	if !ok || !reflect.DeepEqual(ids(list), []string{"r3", "r2", "r1"}) {
+		t.Fatalf("unexpected order %v", ids(list))
+	}
+	list, ok = tree.OrderedRequests("f1")
+	if !ok || !reflect.DeepEqual(ids(list), []string{"r3", "r2"}) {
+		t.Fatalf("unexpected folder order %v", ids(list))
+	}
+	if _, ok := tree.OrderedRequests("missing"); ok {
+		t.Fatal("expected missing folder to be reported")
+	}
+}
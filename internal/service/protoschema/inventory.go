@@ -0,0 +1,131 @@
+package protoschema
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// 服务清单缓存时间，不可达的地址缓存较短时间以便尽快恢复
+const (
+	inventoryTTL   = 10 * time.Minute
+	unreachableTTL = time.Minute
+)
+
+// Inventory 某个 gRPC 地址通过反射得到的服务清单
+type Inventory struct {
+	Target    string    `json:"target"`
+	TLS       bool      `json:"tls"`
+	Reachable bool      `json:"reachable"`
+	Error     string    `json:"error,omitempty"`
+	Services  []Service `json:"services"`
+	CheckedAt time.Time `json:"checked_at"`
+	Cached    bool      `json:"cached"`
+}
+
+// InventoryCache 按地址缓存服务清单
+type InventoryCache struct {
+	rdb *redis.Client
+}
+
+// NewInventoryCache 创建服务清单缓存，rdb 为空时不缓存
+func NewInventoryCache(rdb *redis.Client) *InventoryCache {
+	return &InventoryCache{rdb: rdb}
+}
+
+func inventoryCacheKey(target string, tls bool) string {
+	sum := sha1.Sum([]byte(target + "|" + strconv.FormatBool(tls)))
+	return "grpc:inventory:" + hex.EncodeToString(sum[:])
+}
+
+// Get 读取服务清单，缓存不存在或 refresh 为 true 时重新反射
+func (c *InventoryCache) Get(ctx context.Context, opts ReflectOptions, refresh bool) *Inventory {
+	key := inventoryCacheKey(opts.Target, opts.TLS)
+	if c.rdb != nil && !refresh {
+		if data, err := c.rdb.Get(ctx, key).Bytes(); err == nil {
+			var inventory Inventory
+			if json.Unmarshal(data, &inventory) == nil {
+				inventory.Cached = true
+				return &inventory
+			}
+		}
+	}
+
+	inventory := &Inventory{Target: opts.Target, TLS: opts.TLS, Services: []Service{}, CheckedAt: time.Now()}
+	services, err := reflectServices(ctx, opts)
+	if err != nil {
+		inventory.Error = err.Error()
+	} else {
+		inventory.Reachable, inventory.Services = true, services
+	}
+
+	if c.rdb != nil {
+		ttl := inventoryTTL
+		if !inventory.Reachable {
+			ttl = unreachableTTL
+		}
+		// 缓存失败不影响结果
+		if data, err := json.Marshal(inventory); err == nil {
+			c.rdb.Set(ctx, key, data, ttl)
+		}
+	}
+	return inventory
+}
+
+func reflectServices(ctx context.Context, opts ReflectOptions) ([]Service, error) {
+	set, err := Reflect(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	data, err := Marshal(set)
+	if err != nil {
+		return nil, err
+	}
+	files, err := Load(data)
+	if err != nil {
+		return nil, err
+	}
+	return Services(files), nil
+}
+
+// HealthResult grpc.health.v1 检查结果
+type HealthResult struct {
+	Target   string `json:"target"`
+	Service  string `json:"service"`
+	Status   string `json:"status"` // SERVING、NOT_SERVING、SERVICE_UNKNOWN 等，调用失败时为 UNKNOWN
+	Code     string `json:"code"`   // gRPC 状态码，服务端未实现健康检查时为 Unimplemented
+	Error    string `json:"error,omitempty"`
+	Duration int64  `json:"duration"` // 毫秒
+}
+
+// Health 调用标准健康检查，service 为空表示检查整个服务端
+func Health(ctx context.Context, opts ReflectOptions, service string) *HealthResult {
+	result := &HealthResult{Target: opts.Target, Service: service, Status: healthpb.HealthCheckResponse_UNKNOWN.String()}
+	start := time.Now()
+	defer func() {
+		result.Duration = time.Since(start).Milliseconds()
+	}()
+
+	conn, err := dial(opts)
+	if err != nil {
+		result.Code, result.Error = status.Code(err).String(), err.Error()
+		return result
+	}
+	defer conn.Close()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(opts.outgoing(ctx), &healthpb.HealthCheckRequest{Service: service})
+	result.Code = status.Code(err).String()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Status = resp.GetStatus().String()
+	return result
+}
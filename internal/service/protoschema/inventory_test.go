@@ -0,0 +1,56 @@
+package protoschema
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+func TestInventoryAndHealth(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	checker := health.NewServer()
+	checker.SetServingStatus("demo.Greeter", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(server, checker)
+	reflection.Register(server)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	ctx := context.Background()
+	opts := ReflectOptions{Target: lis.Addr().String()}
+
+	inventory := NewInventoryCache(nil).Get(ctx, opts, false)
+	if !inventory.Reachable || inventory.Cached {
+		t.Fatalf("unexpected inventory %+v", inventory)
+	}
+	found := false
+	for _, s := range inventory.Services {
+		found = found || s.Name == "grpc.health.v1.Health"
+	}
+	if !found {
+		t.Fatalf("health service not listed: %+v", inventory.Services)
+	}
+
+	if r := Health(ctx, opts, ""); r.Status != "SERVING" || r.Code != "OK" {
+		t.Fatalf("unexpected health %+v", r)
+	}
+	if r := Health(ctx, opts, "demo.Greeter"); r.Status != "NOT_SERVING" {
+		t.Fatalf("unexpected health %+v", r)
+	}
+	if r := Health(ctx, opts, "missing.Service"); r.Status != "UNKNOWN" || r.Code != "NotFound" {
+		t.Fatalf("unexpected health %+v", r)
+	}
+
+	server.Stop()
+	if inventory := NewInventoryCache(nil).Get(ctx, opts, true); inventory.Reachable || inventory.Error == "" {
+		t.Fatalf("stopped server reported reachable: %+v", inventory)
+	}
+}
@@ -0,0 +1,193 @@
+package protoschema
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// ReflectOptions 服务反射参数
+type ReflectOptions struct {
+	Target   string            // 服务地址 host:port
+	TLS      bool              // 是否使用 TLS
+	Metadata map[string]string // 附加的请求元数据，如鉴权信息
+}
+
+// Reflect 通过服务反射获取全部服务的描述，优先使用 v1 协议，服务端未实现时回退到 v1alpha
+func Reflect(ctx context.Context, opts ReflectOptions) (*descriptorpb.FileDescriptorSet, error) {
+	conn, err := dial(opts)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	ctx = opts.outgoing(ctx)
+
+	stream, err := reflectionv1.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	set, err := reflectFiles(stream)
+	if status.Code(err) != codes.Unimplemented {
+		return set, err
+	}
+
+	alpha, err := reflectionv1alpha.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return reflectFiles(&alphaStream{stream: alpha})
+}
+
+func dial(opts ReflectOptions) (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if opts.TLS {
+		creds = credentials.NewTLS(&tls.Config{})
+	}
+	return grpc.NewClient(opts.Target, grpc.WithTransportCredentials(creds))
+}
+
+// outgoing 将附加元数据写入请求上下文
+func (opts ReflectOptions) outgoing(ctx context.Context) context.Context {
+	if len(opts.Metadata) > 0 {
+		return metadata.NewOutgoingContext(ctx, metadata.New(opts.Metadata))
+	}
+	return ctx
+}
+
+type reflectStream interface {
+	Send(*reflectionv1.ServerReflectionRequest) error
+	Recv() (*reflectionv1.ServerReflectionResponse, error)
+	CloseSend() error
+}
+
+// reflectFiles 列出服务并逐个获取其所在文件，再补齐缺失的依赖文件
+func reflectFiles(stream reflectStream) (*descriptorpb.FileDescriptorSet, error) {
+	defer stream.CloseSend()
+
+	resp, err := roundTrip(stream, &reflectionv1.ServerReflectionRequest{
+		MessageRequest: &reflectionv1.ServerReflectionRequest_ListServices{ListServices: "*"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string]*descriptorpb.FileDescriptorProto{}
+	var order []string
+	collect := func(resp *reflectionv1.ServerReflectionResponse) error {
+		for _, raw := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+			fd := &descriptorpb.FileDescriptorProto{}
+			if err := proto.Unmarshal(raw, fd); err != nil {
+				return err
+			}
+			if _, ok := files[fd.GetName()]; !ok {
+				files[fd.GetName()] = fd
+				order = append(order, fd.GetName())
+			}
+		}
+		return nil
+	}
+
+	for _, svc := range resp.GetListServicesResponse().GetService() {
+		// 反射服务自身无需生成请求
+		if svc.GetName() == "grpc.reflection.v1.ServerReflection" || svc.GetName() == "grpc.reflection.v1alpha.ServerReflection" {
+			continue
+		}
+		resp, err := roundTrip(stream, &reflectionv1.ServerReflectionRequest{
+			MessageRequest: &reflectionv1.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: svc.GetName()},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := collect(resp); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := 0; i < len(order); i++ {
+		for _, dep := range files[order[i]].GetDependency() {
+			if _, ok := files[dep]; ok {
+				continue
+			}
+			resp, err := roundTrip(stream, &reflectionv1.ServerReflectionRequest{
+				MessageRequest: &reflectionv1.ServerReflectionRequest_FileByFilename{FileByFilename: dep},
+			})
+			if err != nil {
+				return nil, err
+			}
+			if err := collect(resp); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, name := range order {
+		set.File = append(set.File, files[name])
+	}
+	if len(set.File) == 0 {
+		return nil, ErrNoServices
+	}
+	return set, nil
+}
+
+func roundTrip(stream reflectStream, req *reflectionv1.ServerReflectionRequest) (*reflectionv1.ServerReflectionResponse, error) {
+	if err := stream.Send(req); err != nil {
+		return nil, err
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	if e := resp.GetErrorResponse(); e != nil {
+		return nil, fmt.Errorf("reflection error %d: %s", e.GetErrorCode(), e.GetErrorMessage())
+	}
+	return resp, nil
+}
+
+// alphaStream 将 v1alpha 流适配为 v1 消息，两者的线上格式一致
+type alphaStream struct {
+	stream reflectionv1alpha.ServerReflection_ServerReflectionInfoClient
+}
+
+func (s *alphaStream) Send(req *reflectionv1.ServerReflectionRequest) error {
+	data, err := proto.Marshal(req)
+	if err != nil {
+		return err
+	}
+	alpha := &reflectionv1alpha.ServerReflectionRequest{}
+	if err := proto.Unmarshal(data, alpha); err != nil {
+		return err
+	}
+	return s.stream.Send(alpha)
+}
+
+func (s *alphaStream) Recv() (*reflectionv1.ServerReflectionResponse, error) {
+	alpha, err := s.stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	data, err := proto.Marshal(alpha)
+	if err != nil {
+		return nil, err
+	}
+	resp := &reflectionv1.ServerReflectionResponse{}
+	if err := proto.Unmarshal(data, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *alphaStream) CloseSend() error {
+	return s.stream.CloseSend()
+}
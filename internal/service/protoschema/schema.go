@@ -0,0 +1,138 @@
+package protoschema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// ErrNoServices 描述中不包含任何服务
+var ErrNoServices = errors.New("no services found in proto files")
+
+// Service 服务及其方法
+type Service struct {
+	Name    string   `json:"name"`
+	Methods []Method `json:"methods"`
+}
+
+// Method 服务方法
+type Method struct {
+	Name            string `json:"name"`
+	FullName        string `json:"full_name"` // 调用路径，如 pkg.Service/Method
+	Input           string `json:"input"`
+	Output          string `json:"output"`
+	ClientStreaming bool   `json:"client_streaming"`
+	ServerStreaming bool   `json:"server_streaming"`
+}
+
+// Compile 编译 proto 源文件，sources 为相对路径到文件内容的映射
+// 标准 google/protobuf 导入无需上传，返回结果包含全部依赖
+func Compile(ctx context.Context, sources map[string]string) (*descriptorpb.FileDescriptorSet, error) {
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(sources),
+		}),
+	}
+	files, err := compiler.Compile(ctx, names...)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	seen := map[string]bool{}
+	var add func(fd protoreflect.FileDescriptor)
+	add = func(fd protoreflect.FileDescriptor) {
+		if seen[fd.Path()] {
+			return
+		}
+		seen[fd.Path()] = true
+		imports := fd.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			add(imports.Get(i).FileDescriptor)
+		}
+		set.File = append(set.File, protodesc.ToFileDescriptorProto(fd))
+	}
+	for _, fd := range files {
+		add(fd)
+	}
+	return set, nil
+}
+
+// ParseDescriptorSet 解析 protoc --descriptor_set_out 生成的二进制描述文件
+func ParseDescriptorSet(data []byte) (*descriptorpb.FileDescriptorSet, error) {
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, set); err != nil {
+		return nil, fmt.Errorf("invalid descriptor set: %w", err)
+	}
+	if _, err := protodesc.NewFiles(set); err != nil {
+		return nil, fmt.Errorf("invalid descriptor set: %w", err)
+	}
+	return set, nil
+}
+
+// Marshal 序列化描述用于保存
+func Marshal(set *descriptorpb.FileDescriptorSet) ([]byte, error) {
+	return proto.Marshal(set)
+}
+
+// Load 从保存的描述构建文件注册表
+func Load(data []byte) (*protoregistry.Files, error) {
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, set); err != nil {
+		return nil, err
+	}
+	return protodesc.NewFiles(set)
+}
+
+// Services 列出全部服务及方法，按服务全名排序
+func Services(files *protoregistry.Files) []Service {
+	var services []Service
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		for i := 0; i < fd.Services().Len(); i++ {
+			sd := fd.Services().Get(i)
+			svc := Service{Name: string(sd.FullName())}
+			for j := 0; j < sd.Methods().Len(); j++ {
+				md := sd.Methods().Get(j)
+				svc.Methods = append(svc.Methods, Method{
+					Name:            string(md.Name()),
+					FullName:        string(sd.FullName()) + "/" + string(md.Name()),
+					Input:           string(md.Input().FullName()),
+					Output:          string(md.Output().FullName()),
+					ClientStreaming: md.IsStreamingClient(),
+					ServerStreaming: md.IsStreamingServer(),
+				})
+			}
+			services = append(services, svc)
+		}
+		return true
+	})
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+	return services
+}
+
+// FindService 按全名查找服务
+func FindService(files *protoregistry.Files, name string) (protoreflect.ServiceDescriptor, error) {
+	d, err := files.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, err
+	}
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", name)
+	}
+	return sd, nil
+}
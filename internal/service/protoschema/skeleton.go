@@ -0,0 +1,191 @@
+package protoschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Skeleton 生成消息的 JSON 请求体骨架，字段按定义顺序输出，使用 protojson 的字段名和取值格式
+// oneof 只填充第一个成员，其余成员通过 OneofHints 提示；递归引用的消息输出为空对象
+func Skeleton(md protoreflect.MessageDescriptor) string {
+	var buf bytes.Buffer
+	writeMessage(&buf, md, map[protoreflect.FullName]bool{})
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+		return buf.String()
+	}
+	return out.String()
+}
+
+// OneofHints 列出消息及其嵌套消息中 oneof 的可选成员，如 "Request.payload: text | image"
+func OneofHints(md protoreflect.MessageDescriptor) []string {
+	var hints []string
+	seen := map[protoreflect.FullName]bool{}
+	var walk func(md protoreflect.MessageDescriptor)
+	walk = func(md protoreflect.MessageDescriptor) {
+		if seen[md.FullName()] || wellKnown(md) {
+			return
+		}
+		seen[md.FullName()] = true
+		oneofs := md.Oneofs()
+		for i := 0; i < oneofs.Len(); i++ {
+			od := oneofs.Get(i)
+			if od.IsSynthetic() {
+				continue
+			}
+			names := make([]string, 0, od.Fields().Len())
+			for j := 0; j < od.Fields().Len(); j++ {
+				names = append(names, od.Fields().Get(j).JSONName())
+			}
+			hints = append(hints, string(md.Name())+"."+string(od.Name())+": "+strings.Join(names, " | "))
+		}
+		fields := md.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			fd := fields.Get(i)
+			if fd.IsMap() {
+				fd = fd.MapValue()
+			}
+			if fd.Message() != nil {
+				walk(fd.Message())
+			}
+		}
+	}
+	walk(md)
+	return hints
+}
+
+func writeMessage(buf *bytes.Buffer, md protoreflect.MessageDescriptor, path map[protoreflect.FullName]bool) {
+	if v, ok := wellKnownValue(md); ok {
+		buf.WriteString(v)
+		return
+	}
+	if path[md.FullName()] {
+		buf.WriteString("{}")
+		return
+	}
+	path[md.FullName()] = true
+	defer delete(path, md.FullName())
+
+	buf.WriteByte('{')
+	first := true
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if od := fd.ContainingOneof(); od != nil && !od.IsSynthetic() && od.Fields().Get(0) != fd {
+			continue
+		}
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		writeString(buf, fd.JSONName())
+		buf.WriteByte(':')
+		writeField(buf, fd, path)
+	}
+	buf.WriteByte('}')
+}
+
+func writeField(buf *bytes.Buffer, fd protoreflect.FieldDescriptor, path map[protoreflect.FullName]bool) {
+	switch {
+	case fd.IsMap():
+		buf.WriteByte('{')
+		writeString(buf, mapKeySample(fd.MapKey()))
+		buf.WriteByte(':')
+		writeValue(buf, fd.MapValue(), path)
+		buf.WriteByte('}')
+	case fd.IsList():
+		buf.WriteByte('[')
+		writeValue(buf, fd, path)
+		buf.WriteByte(']')
+	default:
+		writeValue(buf, fd, path)
+	}
+}
+
+func writeValue(buf *bytes.Buffer, fd protoreflect.FieldDescriptor, path map[protoreflect.FullName]bool) {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		writeMessage(buf, fd.Message(), path)
+	case protoreflect.EnumKind:
+		writeString(buf, enumSample(fd.Enum()))
+	default:
+		buf.WriteString(scalarSample(fd.Kind()))
+	}
+}
+
+// scalarSample 标量默认值，64 位整数按 protojson 规则使用字符串
+func scalarSample(kind protoreflect.Kind) string {
+	switch kind {
+	case protoreflect.BoolKind:
+		return "false"
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		return `""`
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return `"0"`
+	}
+	return "0"
+}
+
+func mapKeySample(fd protoreflect.FieldDescriptor) string {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return "key"
+	case protoreflect.BoolKind:
+		return "false"
+	}
+	return "0"
+}
+
+func enumSample(ed protoreflect.EnumDescriptor) string {
+	if ed.FullName() == "google.protobuf.NullValue" {
+		return "NULL_VALUE"
+	}
+	if ed.Values().Len() == 0 {
+		return ""
+	}
+	return string(ed.Values().Get(0).Name())
+}
+
+// wellKnownValue 常用内置类型按 protojson 的特殊格式输出
+func wellKnownValue(md protoreflect.MessageDescriptor) (string, bool) {
+	switch md.FullName() {
+	case "google.protobuf.Timestamp":
+		return `"1970-01-01T00:00:00Z"`, true
+	case "google.protobuf.Duration":
+		return `"0s"`, true
+	case "google.protobuf.FieldMask":
+		return `""`, true
+	case "google.protobuf.Struct", "google.protobuf.Empty":
+		return "{}", true
+	case "google.protobuf.ListValue":
+		return "[]", true
+	case "google.protobuf.Value":
+		return "null", true
+	case "google.protobuf.Any":
+		return `{"@type":""}`, true
+	case "google.protobuf.DoubleValue", "google.protobuf.FloatValue",
+		"google.protobuf.Int32Value", "google.protobuf.UInt32Value":
+		return "0", true
+	case "google.protobuf.Int64Value", "google.protobuf.UInt64Value":
+		return `"0"`, true
+	case "google.protobuf.BoolValue":
+		return "false", true
+	case "google.protobuf.StringValue", "google.protobuf.BytesValue":
+		return `""`, true
+	}
+	return "", false
+}
+
+func wellKnown(md protoreflect.MessageDescriptor) bool {
+	_, ok := wellKnownValue(md)
+	return ok
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	data, _ := json.Marshal(s)
+	buf.Write(data)
+}
@@ -0,0 +1,92 @@
+package protoschema
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const testProto = `syntax = "proto3";
+package demo;
+
+import "google/protobuf/timestamp.proto";
+import "google/protobuf/duration.proto";
+
+enum Kind {
+  KIND_UNSPECIFIED = 0;
+  KIND_A = 1;
+}
+
+message Node {
+  string name = 1;
+  repeated Node children = 2;
+}
+
+message CreateRequest {
+  int64 id = 1;
+  Kind kind = 2;
+  map<string, Node> nodes = 3;
+  oneof payload {
+    string text = 4;
+    bytes raw = 5;
+  }
+  google.protobuf.Timestamp created_at = 6;
+  google.protobuf.Duration ttl = 7;
+  Node root = 8;
+}
+
+service Demo {
+  rpc Create(CreateRequest) returns (Node);
+  rpc Watch(Node) returns (stream Node);
+}
+`
+
+func TestSkeleton(t *testing.T) {
+	set, err := Compile(context.Background(), map[string]string{"demo.proto": testProto})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := Load(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	services := Services(files)
+	if len(services) != 1 || len(services[0].Methods) != 2 || !services[0].Methods[1].ServerStreaming {
+		t.Fatalf("unexpected services: %+v", services)
+	}
+
+	d, err := files.FindDescriptorByName("demo.CreateRequest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	md := d.(protoreflect.MessageDescriptor)
+
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(Skeleton(md)), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body["id"] != "0" || body["kind"] != "KIND_UNSPECIFIED" || body["createdAt"] != "1970-01-01T00:00:00Z" || body["ttl"] != "0s" {
+		t.Fatalf("unexpected scalar values: %v", body)
+	}
+	if _, ok := body["text"]; !ok {
+		t.Fatalf("first oneof member missing: %v", body)
+	}
+	if _, ok := body["raw"]; ok {
+		t.Fatalf("only one oneof member expected: %v", body)
+	}
+	node := body["nodes"].(map[string]interface{})["key"].(map[string]interface{})
+	if children := node["children"].([]interface{}); len(children) != 1 || len(children[0].(map[string]interface{})) != 0 {
+		t.Fatalf("recursive message should stop at an empty object: %v", node)
+	}
+
+	if hints := OneofHints(md); len(hints) != 1 || hints[0] != "CreateRequest.payload: text | raw" {
+		t.Fatalf("unexpected oneof hints: %v", hints)
+	}
+}
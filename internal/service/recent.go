@@ -0,0 +1,180 @@
+package service
+
+import (
+	"FastGo/config"
+	"FastGo/internal/model"
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// ItemRef 指向一个集合或请求
+type ItemRef struct {
+	Kind string `json:"kind"`
+	ID   string `json:"id"` // CollectionID 或 RequestID
+}
+
+// RecentItem 最近使用的条目及最后使用时间
+type RecentItem struct {
+	ItemRef
+	UsedAt time.Time `json:"used_at"`
+}
+
+// RecentStore 用 Redis 有序集合保存每个用户最近使用的集合和请求，分数为最后使用时间（毫秒）
+// 每个用户只保留最近的若干条，超过保留时长的条目在读写时清除，整个键在最后一次使用后过期
+type RecentStore struct {
+	rdb   *redis.Client
+	limit int
+	ttl   time.Duration
+}
+
+// NewRecentStore 创建最近使用记录，rdb 为空时不记录
+func NewRecentStore(rdb *redis.Client, opts config.RecentOptions) *RecentStore {
+	return &RecentStore{rdb: rdb, limit: opts.Limit(), ttl: opts.Expiry()}
+}
+
+func recentKey(userID uint64) string {
+	return "recent:" + strconv.FormatUint(userID, 10)
+}
+
+func recentMember(kind, id string) string {
+	return kind + ":" + id
+}
+
+// Touch 记录一次使用，记录失败不影响调用方
+func (s *RecentStore) Touch(ctx context.Context, userID uint64, kind, id string) {
+	if s.rdb == nil || userID == 0 || id == "" {
+		return
+	}
+	key := recentKey(userID)
+	now := time.Now()
+	s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(now.UnixMilli()), Member: recentMember(kind, id)})
+		pipe.ZRemRangeByScore(ctx, key, "-inf", "("+strconv.FormatInt(now.Add(-s.ttl).UnixMilli(), 10))
+		pipe.ZRemRangeByRank(ctx, key, 0, int64(-s.limit-1))
+		pipe.Expire(ctx, key, s.ttl)
+		return nil
+	})
+}
+
+// List 按最后使用时间倒序返回未过期的条目，limit 不大于 0 时返回全部
+func (s *RecentStore) List(ctx context.Context, userID uint64, limit int) ([]RecentItem, error) {
+	items := []RecentItem{}
+	if s.rdb == nil {
+		return items, nil
+	}
+	count := int64(0)
+	if limit > 0 {
+		count = int64(limit)
+	}
+	zs, err := s.rdb.ZRevRangeByScoreWithScores(ctx, recentKey(userID), &redis.ZRangeBy{
+		Min:   strconv.FormatInt(time.Now().Add(-s.ttl).UnixMilli(), 10),
+		Max:   "+inf",
+		Count: count,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	for _, z := range zs {
+		member, _ := z.Member.(string)
+		kind, id, ok := strings.Cut(member, ":")
+		if !ok {
+			continue
+		}
+		items = append(items, RecentItem{
+			ItemRef: ItemRef{Kind: kind, ID: id},
+			UsedAt:  time.UnixMilli(int64(z.Score)),
+		})
+	}
+	return items, nil
+}
+
+// Remove 删除指定条目
+func (s *RecentStore) Remove(ctx context.Context, userID uint64, refs ...ItemRef) error {
+	if s.rdb == nil || len(refs) == 0 {
+		return nil
+	}
+	members := make([]interface{}, 0, len(refs))
+	for _, r := range refs {
+		members = append(members, recentMember(r.Kind, r.ID))
+	}
+	return s.rdb.ZRem(ctx, recentKey(userID), members...).Err()
+}
+
+// Clear 清空用户的最近使用记录
+func (s *RecentStore) Clear(ctx context.Context, userID uint64) error {
+	if s.rdb == nil {
+		return nil
+	}
+	return s.rdb.Del(ctx, recentKey(userID)).Err()
+}
+
+// ItemSummary 集合或请求的摘要，用于置顶和最近使用列表
+type ItemSummary struct {
+	ItemRef
+	Name         string `json:"name"`
+	WorkspaceID  uint64 `json:"workspace_id"`
+	CollectionID string `json:"collection_id"`
+	FolderID     string `json:"folder_id,omitempty"`
+	Type         string `json:"type,omitempty"`
+	Method       string `json:"method,omitempty"`
+	Path         string `json:"path,omitempty"`
+}
+
+// DescribeItems 查询条目摘要，已删除或不存在的条目不在结果中
+func DescribeItems(db *gorm.DB, refs []ItemRef) (map[ItemRef]ItemSummary, error) {
+	summaries := make(map[ItemRef]ItemSummary, len(refs))
+	var collectionIDs, requestIDs []string
+	for _, r := range refs {
+		switch r.Kind {
+		case KindCollection:
+			collectionIDs = append(collectionIDs, r.ID)
+		case KindRequest:
+			requestIDs = append(requestIDs, r.ID)
+		}
+	}
+
+	var requests []model.Request
+	if len(requestIDs) > 0 {
+		if err := db.Where("request_id IN (?)", requestIDs).Find(&requests).Error; err != nil {
+			return nil, err
+		}
+		for _, r := range requests {
+			collectionIDs = append(collectionIDs, r.CollectionID)
+		}
+	}
+	collections := map[string]model.Collections{}
+	if len(collectionIDs) > 0 {
+		var rows []model.Collections
+		if err := db.Where("collection_id IN (?)", collectionIDs).Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, c := range rows {
+			collections[c.CollectionID] = c
+		}
+	}
+
+	for _, id := range collectionIDs {
+		if c, ok := collections[id]; ok {
+			ref := ItemRef{Kind: KindCollection, ID: id}
+			summaries[ref] = ItemSummary{ItemRef: ref, Name: c.Name, WorkspaceID: c.WorkspaceID, CollectionID: id}
+		}
+	}
+	// 所在集合已删除的请求同样视为已删除
+	for _, r := range requests {
+		c, ok := collections[r.CollectionID]
+		if !ok {
+			continue
+		}
+		ref := ItemRef{Kind: KindRequest, ID: r.RequestID}
+		summaries[ref] = ItemSummary{
+			ItemRef: ref, Name: r.Name, WorkspaceID: c.WorkspaceID, CollectionID: r.CollectionID,
+			FolderID: r.FolderID, Type: string(r.Type), Method: string(r.Method), Path: r.Path,
+		}
+	}
+	return summaries, nil
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"FastGo/internal/model"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrRequestNotFound 请求不存在
+var ErrRequestNotFound = errors.New("request not found")
+
+// ValidateParent 校验请求所在位置：文件夹存在且属于集合，只给出集合时集合存在
+// 返回文件夹所在集合，都为空时返回空
+func ValidateParent(tx *gorm.DB, folderID, collectionID string) (string, error) {
+	if folderID != "" {
+		var folder model.Folder
+		if err := tx.Where("folder_id = ?", folderID).First(&folder).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return "", ErrFolderNotFound
+			}
+			return "", err
+		}
+		if collectionID != "" && collectionID != folder.CollectionID {
+			return "", ErrParentInCollection
+		}
+		return folder.CollectionID, nil
+	}
+	if collectionID == "" {
+		return "", nil
+	}
+	var count int64
+	if err := tx.Model(&model.Collections{}).Where("collection_id = ?", collectionID).Count(&count).Error; err != nil {
+		return "", err
+	}
+	if count == 0 {
+		return "", ErrCollectionNotFound
+	}
+	return collectionID, nil
+}
+
+// MoveRequest 将请求移动到文件夹或集合根目录，folderID 为空表示集合根目录
+// collectionID 为空时使用目标文件夹所在集合，都为空时留在原集合，移动后排在同级末尾
+// 需要在事务中调用
+func MoveRequest(tx *gorm.DB, requestID, folderID, collectionID string) (*model.Request, error) {
+	var request model.Request
+	if err := tx.Where("request_id = ?", requestID).First(&request).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRequestNotFound
+		}
+		return nil, err
+	}
+
+	if folderID != "" || (collectionID != "" && collectionID != request.CollectionID) {
+		var err error
+		if collectionID, err = ValidateParent(tx, folderID, collectionID); err != nil {
+			return nil, err
+		}
+	}
+	if collectionID == "" {
+		collectionID = request.CollectionID
+	}
+
+	order, err := NextSortOrder(tx, &model.Request{}, collectionID, folderID)
+	if err != nil {
+		return nil, err
+	}
+	request.CollectionID, request.FolderID, request.SortOrder = collectionID, folderID, order
+	err = tx.Model(&model.Request{}).Where("id = ?", request.ID).Updates(map[string]interface{}{
+		"collection_id": collectionID,
+		"folder_id":     folderID,
+		"sort_order":    order,
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// DeleteRequest 将请求移入回收站并记录删除修订，需要在事务中调用
+func DeleteRequest(tx *gorm.DB, requestID string, authorID uint64) (*DeleteReport, error) {
+	var request model.Request
+	if err := tx.Where("request_id = ?", requestID).First(&request).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRequestNotFound
+		}
+		return nil, err
+	}
+
+	report := &DeleteReport{}
+	if err := trashRequests(tx, "request_id IN (?)", []string{requestID}, time.Now(), report); err != nil {
+		return nil, err
+	}
+	if _, err := RecordRevision(tx, &request, authorID, model.RevisionDelete); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"FastGo/internal/model"
+	"FastGo/internal/service/invoke"
+	"FastGo/pkg/uid"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 修订错误
+var (
+	ErrRevisionNotFound = errors.New("revision not found")
+	ErrInvalidRevision  = errors.New("revision cannot be restored") // 修订内容不能通过当前的保存检查
+)
+
+// RequestSnapshot 修订中保存的请求内容，字段名与差异中的字段名一致
+type RequestSnapshot struct {
+	Name         string `json:"name"`
+	CollectionID string `json:"collection_id"`
+	FolderID     string `json:"folder_id"`
+	Type         string `json:"type"`
+	Method       string `json:"method"`
+	Path         string `json:"path"`
+	Transport    string `json:"transport"`
+	Headers      string `json:"headers"`
+	QueryParams  string `json:"query_params"`
+	Auth         string `json:"auth"`
+	Body         string `json:"body"`
+	Query        string `json:"query"`
+	Variables    string `json:"variables"`
+	Assertions   string `json:"assertions"`
+	Timeout      int    `json:"timeout"`
+	RetryCount   int    `json:"retry_count"`
+	Priority     int    `json:"priority"`
+	Description  string `json:"description"`
+	Tags         string `json:"tags"`
+}
+
+// NewSnapshot 从请求生成快照
+func NewSnapshot(r *model.Request) RequestSnapshot {
+	return RequestSnapshot{
+		Name:         r.Name,
+		CollectionID: r.CollectionID,
+		FolderID:     r.FolderID,
+		Type:         string(r.Type),
+		Method:       string(r.Method),
+		Path:         r.Path,
+		Transport:    r.Transport,
+		Headers:      r.Headers,
+		QueryParams:  r.QueryParams,
+		Auth:         r.Auth,
+		Body:         r.Body,
+		Query:        r.Query,
+		Variables:    r.Variables,
+		Assertions:   r.Assertions,
+		Timeout:      r.Timeout,
+		RetryCount:   r.RetryCount,
+		Priority:     r.Priority,
+		Description:  r.Description,
+		Tags:         r.Tags,
+	}
+}
+
+// ApplyContent 将快照中的内容写回请求，所在集合和文件夹保持当前位置
+func (s RequestSnapshot) ApplyContent(r *model.Request) {
+	r.Name = s.Name
+	r.Type = model.RequestType(s.Type)
+	r.Method = model.RequestMethod(s.Method)
+	r.Path = s.Path
+	r.Transport = s.Transport
+	r.Headers = s.Headers
+	r.QueryParams = s.QueryParams
+	r.Auth = s.Auth
+	r.Body = s.Body
+	r.Query = s.Query
+	r.Variables = s.Variables
+	r.Assertions = s.Assertions
+	r.Timeout = s.Timeout
+	r.RetryCount = s.RetryCount
+	r.Priority = s.Priority
+	r.Description = s.Description
+	r.Tags = s.Tags
+}
+
+// DecodeSnapshot 解析修订中保存的快照
+func DecodeSnapshot(rev *model.RequestRevision) (RequestSnapshot, error) {
+	var s RequestSnapshot
+	err := json.Unmarshal([]byte(rev.Snapshot), &s)
+	return s, err
+}
+
+// FieldChange 两个快照之间一个字段的差异
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// DiffSnapshots 按字段比较两个快照，返回发生变化的字段
+func DiffSnapshots(from, to RequestSnapshot) []FieldChange {
+	changes := []FieldChange{}
+	a, b := reflect.ValueOf(from), reflect.ValueOf(to)
+	t := a.Type()
+	for i := 0; i < t.NumField(); i++ {
+		old, cur := a.Field(i).Interface(), b.Field(i).Interface()
+		if old == cur {
+			continue
+		}
+		field := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		changes = append(changes, FieldChange{Field: field, Old: old, New: cur})
+	}
+	return changes
+}
+
+// RecordRevision 为请求的当前状态写入一条修订，需要与保存请求在同一事务中调用
+func RecordRevision(tx *gorm.DB, r *model.Request, authorID uint64, action string) (*model.RequestRevision, error) {
+	// 锁住请求行，同一请求的并发保存依次分配版本号，(request_id, version) 上的唯一索引兜底
+	var locked []model.Request
+	err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+		Where("request_id = ?", r.RequestID).Find(&locked).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var version *int
+	err = tx.Model(&model.RequestRevision{}).Where("request_id = ?", r.RequestID).
+		Select("MAX(version)").Scan(&version).Error
+	if err != nil {
+		return nil, err
+	}
+	next := 1
+	if version != nil {
+		next = *version + 1
+	}
+
+	rev, err := newRevision(r, authorID, action, next)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Create(rev).Error; err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
+
+// recordMoved 为批量移动的请求写入移动修订，requests 为移动后的内容
+func recordMoved(tx *gorm.DB, requests []model.Request, authorID uint64) error {
+	for i := range requests {
+		if _, err := RecordRevision(tx, &requests[i], authorID, model.RevisionMove); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordCreated 为新建的一批请求写入首个修订
+func recordCreated(tx *gorm.DB, requests []model.Request, authorID uint64) error {
+	if len(requests) == 0 {
+		return nil
+	}
+	revisions := make([]*model.RequestRevision, 0, len(requests))
+	for i := range requests {
+		rev, err := newRevision(&requests[i], authorID, model.RevisionCreate, 1)
+		if err != nil {
+			return err
+		}
+		revisions = append(revisions, rev)
+	}
+	return tx.CreateInBatches(revisions, 500).Error
+}
+
+func newRevision(r *model.Request, authorID uint64, action string, version int) (*model.RequestRevision, error) {
+	snapshot, err := json.Marshal(NewSnapshot(r))
+	if err != nil {
+		return nil, err
+	}
+	return &model.RequestRevision{
+		RevisionID:   uid.NewUUID(),
+		RequestID:    r.RequestID,
+		CollectionID: r.CollectionID,
+		Version:      version,
+		Action:       action,
+		AuthorID:     authorID,
+		Snapshot:     string(snapshot),
+	}, nil
+}
+
+// FindRevision 查找请求的修订
+func FindRevision(db *gorm.DB, requestID, revisionID string) (*model.RequestRevision, error) {
+	var rev model.RequestRevision
+	err := db.Where("request_id = ? AND revision_id = ?", requestID, revisionID).First(&rev).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// RestoreRevision 将请求内容恢复为历史修订，并记录为一条新的修订，需要在事务中调用
+// 恢复的内容与新建和编辑一样需要通过保存前的检查
+func RestoreRevision(tx *gorm.DB, requestID, revisionID string, authorID uint64) (*model.Request, *model.RequestRevision, error) {
+	var request model.Request
+	if err := tx.Where("request_id = ?", requestID).First(&request).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrRequestNotFound
+		}
+		return nil, nil, err
+	}
+	rev, err := FindRevision(tx, requestID, revisionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	snapshot, err := DecodeSnapshot(rev)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	snapshot.ApplyContent(&request)
+	if err := invoke.Validate(&request); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidRevision, err)
+	}
+	if err := tx.Save(&request).Error; err != nil {
+		return nil, nil, err
+	}
+	created, err := RecordRevision(tx, &request, authorID, model.RevisionRestore)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &request, created, nil
+}
+
+// ChangeEntry 集合变更日志中的一条记录
+type ChangeEntry struct {
+	model.RequestRevision
+	Name   string   `json:"name"`   // 保存时的请求名称
+	Fields []string `json:"fields"` // 相对上一修订变化的字段，首个修订为空
+}
+
+// CollectionChangelog 按时间倒序列出集合内请求的修订，beforeID 不为 0 时从该修订之前开始
+func CollectionChangelog(db *gorm.DB, collectionID string, beforeID uint64, limit int) ([]ChangeEntry, error) {
+	query := db.Where("collection_id = ?", collectionID)
+	if beforeID > 0 {
+		query = query.Where("id < ?", beforeID)
+	}
+	var revisions []model.RequestRevision
+	if err := query.Order("id DESC").Limit(limit).Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+
+	// 批量查询每条修订的上一版本
+	var pairs [][]interface{}
+	for _, r := range revisions {
+		if r.Version > 1 {
+			pairs = append(pairs, []interface{}{r.RequestID, r.Version - 1})
+		}
+	}
+	previous := map[string]map[int]RequestSnapshot{}
+	if len(pairs) > 0 {
+		var rows []model.RequestRevision
+		if err := db.Where("(request_id, version) IN ?", pairs).Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		for i := range rows {
+			s, err := DecodeSnapshot(&rows[i])
+			if err != nil {
+				continue
+			}
+			if previous[rows[i].RequestID] == nil {
+				previous[rows[i].RequestID] = map[int]RequestSnapshot{}
+			}
+			previous[rows[i].RequestID][rows[i].Version] = s
+		}
+	}
+
+	entries := make([]ChangeEntry, 0, len(revisions))
+	for i := range revisions {
+		r := &revisions[i]
+		entry := ChangeEntry{RequestRevision: *r, Fields: []string{}}
+		current, err := DecodeSnapshot(r)
+		if err == nil {
+			entry.Name = current.Name
+			if prev, ok := previous[r.RequestID][r.Version-1]; ok {
+				for _, c := range DiffSnapshots(prev, current) {
+					entry.Fields = append(entry.Fields, c.Field)
+				}
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
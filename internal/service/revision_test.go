@@ -0,0 +1,58 @@
+package service
+
+import (
+	"FastGo/internal/model"
+	"FastGo/internal/service/servicetest"
+	"errors"
+	"testing"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	old := model.Request{Name: "List users", Method: model.GET, Path: "/users", Timeout: 1000}
+	cur := old
+	cur.Method = model.POST
+	cur.Timeout = 3000
+
+	changes := DiffSnapshots(NewSnapshot(&old), NewSnapshot(&cur))
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", changes)
+	}
+	if changes[0].Field != "method" || changes[0].Old != "GET" || changes[0].New != "POST" {
+		t.Errorf("unexpected method change %+v", changes[0])
+	}
+	if changes[1].Field != "timeout" || changes[1].Old != 1000 || changes[1].New != 3000 {
+		t.Errorf("unexpected timeout change %+v", changes[1])
+	}
+
+	if changes := DiffSnapshots(NewSnapshot(&old), NewSnapshot(&old)); len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+}
+
+func TestRestoreRevisionValidates(t *testing.T) {
+	db := servicetest.NewDB(t)
+	request := model.Request{RequestID: "r1", CollectionID: "c", Name: "List users", Type: model.HTTP1, Method: model.GET, Path: "/users"}
+	must(t, db.Create(&request).Error)
+	first, err := RecordRevision(db, &request, 1, model.RevisionCreate)
+	must(t, err)
+
+	// 历史修订中的请求头缺少名称，不能通过当前的保存检查
+	invalid := request
+	invalid.Headers = model.EncodeKeyValues([]model.KeyValue{{Key: " ", Value: "x", Enabled: true}})
+	bad, err := RecordRevision(db, &invalid, 1, model.RevisionUpdate)
+	must(t, err)
+	if _, _, err := RestoreRevision(db, "r1", bad.RevisionID, 1); !errors.Is(err, ErrInvalidRevision) {
+		t.Fatalf("expected ErrInvalidRevision, got %v", err)
+	}
+	var stored model.Request
+	must(t, db.Where("request_id = ?", "r1").First(&stored).Error)
+	if stored.Headers != "" {
+		t.Errorf("expected the request to be unchanged, got %q", stored.Headers)
+	}
+
+	restored, rev, err := RestoreRevision(db, "r1", first.RevisionID, 1)
+	must(t, err)
+	if restored.Path != "/users" || rev.Action != model.RevisionRestore || rev.Version != 3 {
+		t.Errorf("unexpected restore %+v %+v", restored, rev)
+	}
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"FastGo/internal/model"
+	"FastGo/pkg/tagexpr"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// 请求全文索引覆盖的列，需与 idx_request_search 一致
+const (
+	searchRequestColumns = "request.name, request.path, request.headers, request.body, request.description"
+	snippetRadius        = 40   // 摘要中匹配位置前后保留的字符数
+	tagScanLimit         = 1000 // 按标签筛选时每类结果先取出的最大条数
+)
+
+// SearchOptions 搜索条件，Types、Methods 不为空时只搜索请求
+// TagExpr 不为空时只返回继承集合和文件夹标签后满足表达式的结果
+type SearchOptions struct {
+	Query        string
+	WorkspaceIDs []uint64
+	Types        []string
+	Methods      []string
+	TagExpr      *tagexpr.Expr
+	Limit        int
+}
+
+// Highlight 一个字段中的匹配摘要，Matches 为摘要内匹配片段的起止位置（按字符计，不含结束位置）
+type Highlight struct {
+	Field   string   `json:"field"`
+	Snippet string   `json:"snippet"`
+	Matches [][2]int `json:"matches"`
+}
+
+// SearchResult 一条搜索结果
+type SearchResult struct {
+	Kind           string      `json:"kind"`
+	ID             string      `json:"id"`
+	Name           string      `json:"name"`
+	WorkspaceID    uint64      `json:"workspace_id"`
+	CollectionID   string      `json:"collection_id"`
+	CollectionName string      `json:"collection_name"`
+	FolderID       string      `json:"folder_id,omitempty"`
+	Type           string      `json:"type,omitempty"`
+	Method         string      `json:"method,omitempty"`
+	Path           string      `json:"path,omitempty"`
+	Score          float64     `json:"score"`
+	Highlights     []Highlight `json:"highlights"`
+}
+
+// Search 在工作区内按全文索引搜索请求、文件夹和集合，按相关度倒序返回
+// 不同表的相关度分别计算，合并后统一排序
+func Search(db *gorm.DB, opts SearchOptions) ([]SearchResult, error) {
+	results := []SearchResult{}
+	terms := searchTerms(opts.Query)
+	if len(terms) == 0 || len(opts.WorkspaceIDs) == 0 {
+		return results, nil
+	}
+	against := booleanQuery(terms)
+	scanLimit := opts.Limit
+	if opts.TagExpr != nil {
+		scanLimit = tagScanLimit
+	}
+
+	var collections []model.Collections
+	if err := db.Where("workspace_id IN (?)", opts.WorkspaceIDs).Find(&collections).Error; err != nil {
+		return nil, err
+	}
+	if len(collections) == 0 {
+		return results, nil
+	}
+	collectionIDs := make([]string, 0, len(collections))
+	byID := make(map[string]*model.Collections, len(collections))
+	for i := range collections {
+		collectionIDs = append(collectionIDs, collections[i].CollectionID)
+		byID[collections[i].CollectionID] = &collections[i]
+	}
+	// matchTags 按标签表达式筛选，folderID 为空时只继承集合的标签
+	var inherited map[string][]string
+	matchTags := func(collectionID, folderID, own string) bool {
+		if opts.TagExpr == nil {
+			return true
+		}
+		tags := model.DecodeTags(byID[collectionID].Tags)
+		if folderID != "" {
+			tags = append(tags, inherited[folderID]...)
+		}
+		return opts.TagExpr.Match(mergeTags(tags, own))
+	}
+	loadInherited := func(folderIDs []string) error {
+		if opts.TagExpr == nil {
+			return nil
+		}
+		var err error
+		inherited, err = folderTags(db, folderIDs)
+		return err
+	}
+
+	newResult := func(kind, id, name, collectionID string, score float64) SearchResult {
+		c := byID[collectionID]
+		return SearchResult{
+			Kind: kind, ID: id, Name: name, WorkspaceID: c.WorkspaceID,
+			CollectionID: collectionID, CollectionName: c.Name, Score: score,
+		}
+	}
+
+	var requests []struct {
+		model.Request
+		Score float64
+	}
+	query := db.Model(&model.Request{}).
+		Select("request.*, MATCH("+searchRequestColumns+") AGAINST (? IN BOOLEAN MODE) AS score", against).
+		Where("collection_id IN (?)", collectionIDs).
+		Where("MATCH("+searchRequestColumns+") AGAINST (? IN BOOLEAN MODE)", against)
+	if len(opts.Types) > 0 {
+		query = query.Where("type IN (?)", opts.Types)
+	}
+	if len(opts.Methods) > 0 {
+		query = query.Where("method IN (?)", opts.Methods)
+	}
+	if err := query.Order("score DESC").Limit(scanLimit).Scan(&requests).Error; err != nil {
+		return nil, err
+	}
+	folderIDs := make([]string, 0, len(requests))
+	for _, r := range requests {
+		if r.FolderID != "" {
+			folderIDs = append(folderIDs, r.FolderID)
+		}
+	}
+	if err := loadInherited(folderIDs); err != nil {
+		return nil, err
+	}
+	for _, r := range requests {
+		if !matchTags(r.CollectionID, r.FolderID, r.Tags) {
+			continue
+		}
+		result := newResult(KindRequest, r.RequestID, r.Name, r.CollectionID, r.Score)
+		result.FolderID = r.FolderID
+		result.Type, result.Method, result.Path = string(r.Type), string(r.Method), r.Path
+		result.Highlights = highlightFields(terms, []string{"name", "path", "description", "headers", "body"},
+			[]string{r.Name, r.Path, r.Description, r.Headers, r.Body})
+		results = append(results, result)
+	}
+
+	if len(opts.Types) == 0 && len(opts.Methods) == 0 {
+		var folders []struct {
+			model.Folder
+			Score float64
+		}
+		err := db.Model(&model.Folder{}).
+			Select("folders.*, MATCH(folders.name) AGAINST (? IN BOOLEAN MODE) AS score", against).
+			Where("collection_id IN (?)", collectionIDs).
+			Where("MATCH(folders.name) AGAINST (? IN BOOLEAN MODE)", against).
+			Order("score DESC").Limit(scanLimit).Scan(&folders).Error
+		if err != nil {
+			return nil, err
+		}
+		folderIDs := make([]string, 0, len(folders))
+		for _, f := range folders {
+			folderIDs = append(folderIDs, f.FolderID)
+		}
+		if err := loadInherited(folderIDs); err != nil {
+			return nil, err
+		}
+		for _, f := range folders {
+			// 文件夹自身的标签已包含在继承结果中
+			if !matchTags(f.CollectionID, f.FolderID, "") {
+				continue
+			}
+			result := newResult(KindFolder, f.FolderID, f.Name, f.CollectionID, f.Score)
+			result.FolderID = f.FolderID
+			result.Highlights = highlightFields(terms, []string{"name"}, []string{f.Name})
+			results = append(results, result)
+		}
+
+		var matched []struct {
+			CollectionID string
+			Score        float64
+		}
+		err = db.Model(&model.Collections{}).
+			Select("collection_id, MATCH(collections.name) AGAINST (? IN BOOLEAN MODE) AS score", against).
+			Where("collection_id IN (?)", collectionIDs).
+			Where("MATCH(collections.name) AGAINST (? IN BOOLEAN MODE)", against).
+			Order("score DESC").Limit(scanLimit).Scan(&matched).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matched {
+			c := byID[m.CollectionID]
+			if !matchTags(c.CollectionID, "", "") {
+				continue
+			}
+			result := newResult(KindCollection, c.CollectionID, c.Name, c.CollectionID, m.Score)
+			result.Highlights = highlightFields(terms, []string{"name"}, []string{c.Name})
+			results = append(results, result)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+	return results, nil
+}
+
+// searchTerms 按空白拆分关键词，去掉全文检索的运算符
+func searchTerms(q string) []string {
+	clean := strings.Map(func(r rune) rune {
+		switch r {
+		case '+', '-', '<', '>', '(', ')', '~', '*', '"', '@', '\'':
+			return ' '
+		}
+		return r
+	}, q)
+	return strings.Fields(clean)
+}
+
+// booleanQuery 生成布尔模式的检索式，每个关键词都必须出现
+func booleanQuery(terms []string) string {
+	parts := make([]string, 0, len(terms))
+	for _, t := range terms {
+		parts = append(parts, `+"`+t+`"`)
+	}
+	return strings.Join(parts, " ")
+}
+
+// highlightFields 为包含关键词的字段生成摘要，不区分大小写，未匹配的字段不返回
+func highlightFields(terms []string, fields, values []string) []Highlight {
+	highlights := []Highlight{}
+	for i, value := range values {
+		if h, ok := highlight(terms, value); ok {
+			h.Field = fields[i]
+			highlights = append(highlights, h)
+		}
+	}
+	return highlights
+}
+
+// highlight 截取第一个匹配位置附近的摘要，并标出摘要中全部匹配片段
+func highlight(terms []string, value string) (Highlight, bool) {
+	text := []rune(value)
+	lower := []rune(strings.ToLower(value))
+	if len(lower) != len(text) {
+		// 少数字符转小写后长度变化，此时直接在原文上匹配
+		lower = text
+	}
+
+	var ranges [][2]int
+	for _, t := range terms {
+		term := []rune(strings.ToLower(t))
+		for i := 0; i+len(term) <= len(lower); i++ {
+			if string(lower[i:i+len(term)]) == string(term) {
+				ranges = append(ranges, [2]int{i, i + len(term)})
+				i += len(term) - 1
+			}
+		}
+	}
+	if len(ranges) == 0 {
+		return Highlight{}, false
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+
+	start := ranges[0][0] - snippetRadius
+	if start < 0 {
+		start = 0
+	}
+	end := ranges[0][1] + snippetRadius
+	if end > len(text) {
+		end = len(text)
+	}
+
+	h := Highlight{Snippet: string(text[start:end]), Matches: [][2]int{}}
+	last := 0
+	for _, r := range ranges {
+		if r[0] < start || r[1] > end || r[0] < last {
+			continue
+		}
+		h.Matches = append(h.Matches, [2]int{r[0] - start, r[1] - start})
+		last = r[1]
+	}
+	if start > 0 {
+		h.Snippet = "…" + h.Snippet
+		for i := range h.Matches {
+			h.Matches[i][0]++
+			h.Matches[i][1]++
+		}
+	}
+	if end < len(text) {
+		h.Snippet += "…"
+	}
+	return h, true
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"FastGo/internal/model"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm/schema"
+)
+
+// MATCH() 的列必须与全文索引定义的列完全一致
+func TestSearchRequestColumnsMatchIndex(t *testing.T) {
+	s, err := schema.Parse(&model.Request{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	index, ok := s.ParseIndexes()["idx_request_search"]
+	if !ok || index.Class != "FULLTEXT" {
+		t.Fatalf("fulltext index idx_request_search not found: %+v", index)
+	}
+	columns := make([]string, 0, len(index.Fields))
+	for _, f := range index.Fields {
+		columns = append(columns, s.Table+"."+f.DBName)
+	}
+	if got := strings.Join(columns, ", "); got != searchRequestColumns {
+		t.Errorf("expected MATCH columns %q to equal index columns %q", searchRequestColumns, got)
+	}
+}
+
+func TestBooleanQuery(t *testing.T) {
+	terms := searchTerms(` list  +users* -"admin" `)
+	if want := []string{"list", "users", "admin"}; !reflect.DeepEqual(terms, want) {
+		t.Fatalf("expected %v, got %v", want, terms)
+	}
+	if got, want := booleanQuery(terms), `+"list" +"users" +"admin"`; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	h, ok := highlight([]string{"user"}, "List Users and user groups")
+	if !ok {
+		t.Fatal("expected a match")
+	}
+	if h.Snippet != "List Users and user groups" {
+		t.Errorf("unexpected snippet %q", h.Snippet)
+	}
+	if want := [][2]int{{5, 9}, {15, 19}}; !reflect.DeepEqual(h.Matches, want) {
+		t.Errorf("expected matches %v, got %v", want, h.Matches)
+	}
+
+	long := "用户" + "................................................................token"
+	h, ok = highlight([]string{"TOKEN"}, long)
+	if !ok || h.Snippet[:len("…")] != "…" {
+		t.Fatalf("expected a truncated snippet, got %q", h.Snippet)
+	}
+	runes := []rune(h.Snippet)
+	m := h.Matches[0]
+	if string(runes[m[0]:m[1]]) != "token" {
+		t.Errorf("match range %v points at %q", m, string(runes[m[0]:m[1]]))
+	}
+
+	if _, ok := highlight([]string{"missing"}, "List users"); ok {
+		t.Error("expected no match")
+	}
+}
@@ -0,0 +1,69 @@
+// Package servicetest 为服务层测试提供内存数据库
+package servicetest
+
+import (
+	"FastGo/internal/model"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// NewDB 打开内存 SQLite 数据库并按模型建表，测试结束时关闭
+// 模型中的全文索引和 ON UPDATE 默认值只适用于 MySQL，这里只按字段类型生成最简单的表结构
+func NewDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 内存数据库按连接隔离，只保留一个连接
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	tables := []interface{}{
+		&model.Workspace{}, &model.Collections{}, &model.Folder{}, &model.FolderClosure{},
+		&model.Request{}, &model.RequestExample{}, &model.RequestRevision{},
+		&model.Environment{}, &model.ProtoSchema{}, &model.Favorite{},
+		&model.CollectionFork{}, &model.MergeRequest{},
+	}
+	for _, table := range tables {
+		s, err := schema.Parse(table, &sync.Map{}, db.NamingStrategy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		columns := make([]string, 0, len(s.Fields))
+		for _, f := range s.Fields {
+			if f.DBName == "" {
+				continue
+			}
+			if f.PrimaryKey {
+				columns = append(columns, f.DBName+" INTEGER PRIMARY KEY AUTOINCREMENT")
+				continue
+			}
+			columnType := "TEXT"
+			switch f.GORMDataType {
+			case schema.Int, schema.Uint, schema.Bool:
+				columnType = "INTEGER"
+			case schema.Float:
+				columnType = "REAL"
+			case schema.Time:
+				columnType = "DATETIME"
+			}
+			columns = append(columns, f.DBName+" "+columnType)
+		}
+		ddl := fmt.Sprintf("CREATE TABLE %s (%s)", s.Table, strings.Join(columns, ", "))
+		if err := db.Exec(ddl).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
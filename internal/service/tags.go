@@ -0,0 +1,198 @@
+package service
+
+import (
+	"FastGo/internal/model"
+	"FastGo/pkg/tagexpr"
+	"errors"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// TagCount 标签及直接设置了该标签的请求、文件夹和集合数量
+type TagCount struct {
+	Name        string `json:"name"`
+	Requests    int    `json:"requests"`
+	Folders     int    `json:"folders"`
+	Collections int    `json:"collections"`
+	Total       int    `json:"total"`
+}
+
+// ListTags 统计工作区内使用的标签，按使用次数倒序，次数相同时按名称排序
+func ListTags(db *gorm.DB, workspaceID uint64) ([]TagCount, error) {
+	counts := map[string]*TagCount{}
+	add := func(encoded string, field func(*TagCount) *int) {
+		for _, t := range model.DecodeTags(encoded) {
+			c, ok := counts[t]
+			if !ok {
+				c = &TagCount{Name: t}
+				counts[t] = c
+			}
+			*field(c)++
+			c.Total++
+		}
+	}
+
+	var collections []model.Collections
+	if err := db.Select("collection_id", "tags").Where("workspace_id = ?", workspaceID).Find(&collections).Error; err != nil {
+		return nil, err
+	}
+	collectionIDs := make([]string, 0, len(collections))
+	for _, c := range collections {
+		collectionIDs = append(collectionIDs, c.CollectionID)
+		add(c.Tags, func(t *TagCount) *int { return &t.Collections })
+	}
+
+	if len(collectionIDs) > 0 {
+		var folderTags, requestTags []string
+		err := db.Model(&model.Folder{}).Where("collection_id IN (?) AND tags <> ''", collectionIDs).Pluck("tags", &folderTags).Error
+		if err != nil {
+			return nil, err
+		}
+		err = db.Model(&model.Request{}).Where("collection_id IN (?) AND tags <> ''", collectionIDs).Pluck("tags", &requestTags).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range folderTags {
+			add(s, func(t *TagCount) *int { return &t.Folders })
+		}
+		for _, s := range requestTags {
+			add(s, func(t *TagCount) *int { return &t.Requests })
+		}
+	}
+
+	list := make([]TagCount, 0, len(counts))
+	for _, c := range counts {
+		list = append(list, *c)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Total != list[j].Total {
+			return list[i].Total > list[j].Total
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list, nil
+}
+
+// SetTags 替换请求、文件夹或集合的标签，修改请求标签时记录修订，需要在事务中调用
+func SetTags(tx *gorm.DB, kind, id string, tags []string, authorID uint64) ([]string, error) {
+	tags = model.NormalizeTags(tags)
+	encoded := model.EncodeTags(tags)
+
+	switch kind {
+	case KindRequest:
+		var request model.Request
+		if err := tx.Where("request_id = ?", id).First(&request).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrRequestNotFound
+			}
+			return nil, err
+		}
+		if request.Tags == encoded {
+			return tags, nil
+		}
+		request.Tags = encoded
+		if err := tx.Model(&request).Update("tags", encoded).Error; err != nil {
+			return nil, err
+		}
+		if _, err := RecordRevision(tx, &request, authorID, model.RevisionUpdate); err != nil {
+			return nil, err
+		}
+	case KindFolder:
+		if err := updateTags(tx, &model.Folder{}, "folder_id = ?", id, encoded, ErrFolderNotFound); err != nil {
+			return nil, err
+		}
+	case KindCollection:
+		if err := updateTags(tx, &model.Collections{}, "collection_id = ?", id, encoded, ErrCollectionNotFound); err != nil {
+			return nil, err
+		}
+	}
+	return tags, nil
+}
+
+// updateTags 更新文件夹或集合的标签，标签未变化时 RowsAffected 为 0，因此先确认记录存在
+func updateTags(tx *gorm.DB, m interface{}, query, id, encoded string, notFound error) error {
+	var count int64
+	if err := tx.Model(m).Where(query, id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return notFound
+	}
+	return tx.Model(m).Where(query, id).Update("tags", encoded).Error
+}
+
+// mergeTags 合并上级继承的标签和自身的标签
+func mergeTags(inherited []string, own string) []string {
+	tags := model.DecodeTags(own)
+	if len(inherited) == 0 {
+		return tags
+	}
+	return model.NormalizeTags(append(append([]string{}, inherited...), tags...))
+}
+
+// RequestTags 返回树中每个请求（按 RequestID）继承集合和各级文件夹后的全部标签
+func (t *CollectionTree) RequestTags() map[string][]string {
+	result := map[string][]string{}
+	var walk func(inherited []string, nodes []*FolderNode, requests []*model.Request)
+	walk = func(inherited []string, nodes []*FolderNode, requests []*model.Request) {
+		for _, n := range nodes {
+			walk(mergeTags(inherited, n.Folder.Tags), n.Folders, n.Requests)
+		}
+		for _, r := range requests {
+			result[r.RequestID] = mergeTags(inherited, r.Tags)
+		}
+	}
+	walk(model.DecodeTags(t.Collection.Tags), t.Folders, t.Requests)
+	return result
+}
+
+// Filter 按标签表达式裁剪树：保留继承标签后满足表达式的请求，
+// 以及自身满足表达式或包含保留内容的文件夹，返回集合是否仍需显示
+func (t *CollectionTree) Filter(expr *tagexpr.Expr) bool {
+	var prune func(inherited []string, nodes []*FolderNode, requests []*model.Request) ([]*FolderNode, []*model.Request)
+	prune = func(inherited []string, nodes []*FolderNode, requests []*model.Request) ([]*FolderNode, []*model.Request) {
+		var keptFolders []*FolderNode
+		for _, n := range nodes {
+			tags := mergeTags(inherited, n.Folder.Tags)
+			n.Folders, n.Requests = prune(tags, n.Folders, n.Requests)
+			if len(n.Folders) > 0 || len(n.Requests) > 0 || expr.Match(tags) {
+				keptFolders = append(keptFolders, n)
+			}
+		}
+		var keptRequests []*model.Request
+		for _, r := range requests {
+			if expr.Match(mergeTags(inherited, r.Tags)) {
+				keptRequests = append(keptRequests, r)
+			}
+		}
+		return keptFolders, keptRequests
+	}
+
+	own := model.DecodeTags(t.Collection.Tags)
+	t.Folders, t.Requests = prune(own, t.Folders, t.Requests)
+	return len(t.Folders) > 0 || len(t.Requests) > 0 || expr.Match(own)
+}
+
+// folderTags 查询文件夹继承各级上级文件夹后的标签，不包含集合的标签
+func folderTags(db *gorm.DB, folderIDs []string) (map[string][]string, error) {
+	result := map[string][]string{}
+	if len(folderIDs) == 0 {
+		return result, nil
+	}
+	var rows []struct {
+		Descendant string
+		Tags       string
+	}
+	err := db.Table("folder_closures").Select("folder_closures.descendant, folders.tags").
+		Joins("JOIN folders ON folders.folder_id = folder_closures.ancestor").
+		Where("folder_closures.descendant IN (?) AND folders.deleted_at IS NULL", folderIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		result[r.Descendant] = mergeTags(result[r.Descendant], r.Tags)
+	}
+	return result, nil
+}
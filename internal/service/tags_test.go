@@ -0,0 +1,53 @@
+package service
+
+import (
+	"FastGo/internal/model"
+	"FastGo/pkg/tagexpr"
+	"reflect"
+	"testing"
+)
+
+func TestCollectionTreeFilter(t *testing.T) {
+	login := &model.Request{RequestID: "login", Tags: model.EncodeTags([]string{"smoke"})}
+	refund := &model.Request{RequestID: "refund", Tags: model.EncodeTags([]string{"deprecated"})}
+	charge := &model.Request{RequestID: "charge"}
+	health := &model.Request{RequestID: "health", Tags: model.EncodeTags([]string{"smoke"})}
+	tree := &CollectionTree{
+		Collection: model.Collections{Tags: model.EncodeTags([]string{"core"})},
+		Folders: []*FolderNode{
+			{Folder: model.Folder{FolderID: "auth"}, Requests: []*model.Request{login}},
+			{Folder: model.Folder{FolderID: "payments", Tags: model.EncodeTags([]string{"Payments"})}, Requests: []*model.Request{refund, charge}},
+		},
+		Requests: []*model.Request{health},
+	}
+
+	tags := tree.RequestTags()
+	if want := []string{"core", "deprecated", "payments"}; !reflect.DeepEqual(tags["refund"], want) {
+		t.Errorf("expected refund tags %v, got %v", want, tags["refund"])
+	}
+
+	expr, err := tagexpr.Parse("payments && !deprecated || smoke")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tree.Filter(expr) {
+		t.Fatal("expected the collection to be kept")
+	}
+	var kept []string
+	for _, f := range tree.Folders {
+		for _, r := range f.Requests {
+			kept = append(kept, r.RequestID)
+		}
+	}
+	for _, r := range tree.Requests {
+		kept = append(kept, r.RequestID)
+	}
+	if want := []string{"login", "charge", "health"}; !reflect.DeepEqual(kept, want) {
+		t.Errorf("expected %v, got %v", want, kept)
+	}
+
+	expr, _ = tagexpr.Parse("missing")
+	if tree.Filter(expr) {
+		t.Error("expected the collection to be dropped")
+	}
+}
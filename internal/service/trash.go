@@ -0,0 +1,313 @@
+package service
+
+import (
+	"FastGo/internal/model"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/spf13/cast"
+	"gorm.io/gorm"
+)
+
+// 回收站条目类型，文件夹和请求沿用排序项的类型
+const (
+	KindWorkspace  = "workspace"
+	KindCollection = "collection"
+)
+
+// ErrNotInTrash 要恢复的记录不存在或不在回收站中
+var ErrNotInTrash = errors.New("item not found in trash")
+
+// TrashItem 回收站中的一次删除，只列出被直接删除的项，随其一起删除的下级内容在恢复时一起找回
+type TrashItem struct {
+	Kind         string    `json:"kind"`
+	ID           string    `json:"id"` // 工作区为自增 ID，其余为 CollectionID、FolderID、RequestID
+	Name         string    `json:"name"`
+	CollectionID string    `json:"collection_id,omitempty"`
+	DeletedAt    time.Time `json:"deleted_at"`
+}
+
+// RestoreReport 恢复的记录数量
+type RestoreReport struct {
+	Workspaces  int64 `json:"workspaces"`
+	Collections int64 `json:"collections"`
+	Folders     int64 `json:"folders"`
+	Requests    int64 `json:"requests"`
+
+	restored []model.Request // 恢复的请求，用于记录修订
+}
+
+// ListTrash 列出工作区回收站中的条目，按删除时间倒序
+// 与所在集合或上级文件夹同时删除的项不单独列出
+func ListTrash(db *gorm.DB, workspaceID uint64) ([]TrashItem, error) {
+	items := []TrashItem{}
+
+	var workspace model.Workspace
+	if err := db.Unscoped().Where("id = ?", workspaceID).First(&workspace).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return items, nil
+		}
+		return nil, err
+	}
+	if workspace.DeletedAt.Valid {
+		items = append(items, TrashItem{
+			Kind: KindWorkspace, ID: cast.ToString(workspace.ID), Name: workspace.Name, DeletedAt: workspace.DeletedAt.Time,
+		})
+	}
+
+	var collections []model.Collections
+	if err := db.Unscoped().Where("workspace_id = ?", workspaceID).Find(&collections).Error; err != nil {
+		return nil, err
+	}
+	if len(collections) == 0 {
+		return items, nil
+	}
+	collectionIDs := make([]string, 0, len(collections))
+	collectionDeleted := make(map[string]gorm.DeletedAt, len(collections))
+	for _, c := range collections {
+		collectionIDs = append(collectionIDs, c.CollectionID)
+		collectionDeleted[c.CollectionID] = c.DeletedAt
+		if c.DeletedAt.Valid && !sameDeletion(c.DeletedAt, workspace.DeletedAt) {
+			items = append(items, TrashItem{
+				Kind: KindCollection, ID: c.CollectionID, Name: c.Name, CollectionID: c.CollectionID, DeletedAt: c.DeletedAt.Time,
+			})
+		}
+	}
+
+	var folders []model.Folder
+	if err := db.Unscoped().Where("collection_id IN (?)", collectionIDs).Find(&folders).Error; err != nil {
+		return nil, err
+	}
+	folderDeleted := make(map[string]gorm.DeletedAt, len(folders))
+	var deletedFolders []string
+	for _, f := range folders {
+		folderDeleted[f.FolderID] = f.DeletedAt
+		if f.DeletedAt.Valid {
+			deletedFolders = append(deletedFolders, f.FolderID)
+		}
+	}
+	parents := map[string]string{}
+	if len(deletedFolders) > 0 {
+		var closures []model.FolderClosure
+		if err := db.Where("descendant IN (?) AND depth = 1", deletedFolders).Find(&closures).Error; err != nil {
+			return nil, err
+		}
+		for _, c := range closures {
+			parents[c.Descendant] = c.Ancestor
+		}
+	}
+	for _, f := range folders {
+		if !f.DeletedAt.Valid {
+			continue
+		}
+		container := collectionDeleted[f.CollectionID]
+		if parent, ok := parents[f.FolderID]; ok {
+			container = folderDeleted[parent]
+		}
+		if !sameDeletion(f.DeletedAt, container) {
+			items = append(items, TrashItem{
+				Kind: KindFolder, ID: f.FolderID, Name: f.Name, CollectionID: f.CollectionID, DeletedAt: f.DeletedAt.Time,
+			})
+		}
+	}
+
+	var requests []model.Request
+	err := db.Unscoped().Where("collection_id IN (?) AND deleted_at IS NOT NULL", collectionIDs).Find(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range requests {
+		container := collectionDeleted[r.CollectionID]
+		if r.FolderID != "" {
+			container = folderDeleted[r.FolderID]
+		}
+		if !sameDeletion(r.DeletedAt, container) {
+			items = append(items, TrashItem{
+				Kind: KindRequest, ID: r.RequestID, Name: r.Name, CollectionID: r.CollectionID, DeletedAt: r.DeletedAt.Time,
+			})
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return items[i].DeletedAt.After(items[j].DeletedAt) })
+	return items, nil
+}
+
+// Restore 从回收站恢复条目以及与其同时删除的下级内容，
+// 并恢复已删除的上级文件夹、集合和工作区，使恢复的内容重新出现在原位置，恢复的请求记录恢复修订，需要在事务中调用
+func Restore(tx *gorm.DB, kind, id string, authorID uint64) (*RestoreReport, error) {
+	report := &RestoreReport{}
+	// 新会话使每次查询从干净的条件开始，避免前一次查询的条件带入后续语句
+	if err := restore(tx.Unscoped().Session(&gorm.Session{}), kind, id, report); err != nil {
+		return nil, err
+	}
+	for i := range report.restored {
+		if _, err := RecordRevision(tx, &report.restored[i], authorID, model.RevisionRestore); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+func restore(db *gorm.DB, kind, id string, report *RestoreReport) error {
+	switch kind {
+	case KindWorkspace:
+		var workspace model.Workspace
+		if err := db.Where("id = ? AND deleted_at IS NOT NULL", id).First(&workspace).Error; err != nil {
+			return notInTrash(err)
+		}
+		stamp := workspace.DeletedAt.Time
+		var collectionIDs []string
+		err := db.Model(&model.Collections{}).Where("workspace_id = ? AND deleted_at = ?", workspace.ID, stamp).
+			Pluck("collection_id", &collectionIDs).Error
+		if err != nil {
+			return err
+		}
+		if err := restoreCollections(db, collectionIDs, stamp, report); err != nil {
+			return err
+		}
+		return restoreWorkspace(db, workspace.ID, report)
+
+	case KindCollection:
+		var collection model.Collections
+		if err := db.Where("collection_id = ? AND deleted_at IS NOT NULL", id).First(&collection).Error; err != nil {
+			return notInTrash(err)
+		}
+		if err := restoreCollections(db, []string{id}, collection.DeletedAt.Time, report); err != nil {
+			return err
+		}
+		return restoreWorkspace(db, collection.WorkspaceID, report)
+
+	case KindFolder:
+		var folder model.Folder
+		if err := db.Where("folder_id = ? AND deleted_at IS NOT NULL", id).First(&folder).Error; err != nil {
+			return notInTrash(err)
+		}
+		var subtree []string
+		if err := db.Model(&model.FolderClosure{}).Where("ancestor = ?", id).Pluck("descendant", &subtree).Error; err != nil {
+			return err
+		}
+		if len(subtree) == 0 {
+			subtree = []string{id}
+		}
+		if err := restoreFolders(db, subtree, folder.DeletedAt.Time, report); err != nil {
+			return err
+		}
+		return restoreParents(db, folder.CollectionID, id, report)
+
+	case KindRequest:
+		var request model.Request
+		if err := db.Where("request_id = ? AND deleted_at IS NOT NULL", id).First(&request).Error; err != nil {
+			return notInTrash(err)
+		}
+		if err := restoreRequests(db, report, "id = ?", request.ID); err != nil {
+			return err
+		}
+		return restoreParents(db, request.CollectionID, request.FolderID, report)
+	}
+	return ErrNotInTrash
+}
+
+func notInTrash(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotInTrash
+	}
+	return err
+}
+
+// sameDeletion 两条记录是否在同一次删除中移入回收站
+func sameDeletion(a, b gorm.DeletedAt) bool {
+	return a.Valid && b.Valid && a.Time.Equal(b.Time)
+}
+
+// restoreCollections 恢复集合及其中与集合同时删除的文件夹和请求
+func restoreCollections(db *gorm.DB, collectionIDs []string, stamp time.Time, report *RestoreReport) error {
+	if len(collectionIDs) == 0 {
+		return nil
+	}
+	res := db.Model(&model.Folder{}).Where("collection_id IN (?) AND deleted_at = ?", collectionIDs, stamp).Update("deleted_at", nil)
+	if res.Error != nil {
+		return res.Error
+	}
+	report.Folders += res.RowsAffected
+	if err := restoreRequests(db, report, "collection_id IN (?) AND deleted_at = ?", collectionIDs, stamp); err != nil {
+		return err
+	}
+	res = db.Model(&model.Collections{}).Where("collection_id IN (?) AND deleted_at = ?", collectionIDs, stamp).Update("deleted_at", nil)
+	if res.Error != nil {
+		return res.Error
+	}
+	report.Collections += res.RowsAffected
+	return nil
+}
+
+// restoreFolders 恢复子树中与根文件夹同时删除的文件夹和请求
+func restoreFolders(db *gorm.DB, folderIDs []string, stamp time.Time, report *RestoreReport) error {
+	res := db.Model(&model.Folder{}).Where("folder_id IN (?) AND deleted_at = ?", folderIDs, stamp).Update("deleted_at", nil)
+	if res.Error != nil {
+		return res.Error
+	}
+	report.Folders += res.RowsAffected
+	return restoreRequests(db, report, "folder_id IN (?) AND deleted_at = ?", folderIDs, stamp)
+}
+
+// restoreRequests 恢复满足条件的已删除请求，恢复的请求记入 report
+func restoreRequests(db *gorm.DB, report *RestoreReport, query string, args ...interface{}) error {
+	var requests []model.Request
+	if err := db.Where(query, args...).Where("deleted_at IS NOT NULL").Find(&requests).Error; err != nil {
+		return err
+	}
+	if len(requests) == 0 {
+		return nil
+	}
+	ids := make([]uint64, 0, len(requests))
+	for i := range requests {
+		ids = append(ids, requests[i].ID)
+		requests[i].DeletedAt = gorm.DeletedAt{}
+	}
+	res := db.Model(&model.Request{}).Where("id IN (?)", ids).Update("deleted_at", nil)
+	if res.Error != nil {
+		return res.Error
+	}
+	report.Requests += res.RowsAffected
+	report.restored = append(report.restored, requests...)
+	return nil
+}
+
+// restoreParents 恢复文件夹的全部祖先以及所在集合和工作区，只恢复这条路径上的记录
+func restoreParents(db *gorm.DB, collectionID, folderID string, report *RestoreReport) error {
+	if folderID != "" {
+		res := db.Model(&model.Folder{}).
+			Where("folder_id IN (?) AND deleted_at IS NOT NULL", db.Model(&model.FolderClosure{}).Select("ancestor").Where("descendant = ?", folderID)).
+			Update("deleted_at", nil)
+		if res.Error != nil {
+			return res.Error
+		}
+		report.Folders += res.RowsAffected
+	}
+
+	var collection model.Collections
+	if err := db.Where("collection_id = ?", collectionID).First(&collection).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if collection.DeletedAt.Valid {
+		res := db.Model(&model.Collections{}).Where("id = ?", collection.ID).Update("deleted_at", nil)
+		if res.Error != nil {
+			return res.Error
+		}
+		report.Collections += res.RowsAffected
+	}
+	return restoreWorkspace(db, collection.WorkspaceID, report)
+}
+
+func restoreWorkspace(db *gorm.DB, workspaceID uint64, report *RestoreReport) error {
+	res := db.Model(&model.Workspace{}).Where("id = ? AND deleted_at IS NOT NULL", workspaceID).Update("deleted_at", nil)
+	if res.Error != nil {
+		return res.Error
+	}
+	report.Workspaces += res.RowsAffected
+	return nil
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"FastGo/internal/model"
+	"FastGo/internal/service/servicetest"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// lastAction 返回请求最新修订的操作类型，没有修订时为空
+func lastAction(t *testing.T, db *gorm.DB, requestID string) string {
+	t.Helper()
+	var revisions []model.RequestRevision
+	must(t, db.Where("request_id = ?", requestID).Order("version DESC").Limit(1).Find(&revisions).Error)
+	if len(revisions) == 0 {
+		return ""
+	}
+	return revisions[0].Action
+}
+
+func TestRestoreRecordsRevisions(t *testing.T) {
+	db := servicetest.NewDB(t)
+	seedCollection(t, db)
+
+	_, err := DeleteRequest(db, "r3", 1)
+	must(t, err)
+	if action := lastAction(t, db, "r3"); action != model.RevisionDelete {
+		t.Fatalf("expected a delete revision, got %q", action)
+	}
+	_, err = Restore(db, KindRequest, "r3", 1)
+	must(t, err)
+	if action := lastAction(t, db, "r3"); action != model.RevisionRestore {
+		t.Errorf("expected a restore revision, got %q", action)
+	}
+
+	// 随文件夹恢复的请求同样记录修订
+	_, err = DeleteFolder(db, "a1", false, 1)
+	must(t, err)
+	report, err := Restore(db, KindFolder, "a1", 1)
+	must(t, err)
+	if report.Requests != 2 {
+		t.Fatalf("expected 2 restored requests, got %+v", report)
+	}
+	for _, id := range []string{"r1", "r2"} {
+		if action := lastAction(t, db, id); action != model.RevisionRestore {
+			t.Errorf("expected a restore revision for %s, got %q", id, action)
+		}
+	}
+}
+
+// trashIDs 列出回收站中直接删除的条目 ID
+func trashIDs(t *testing.T, db *gorm.DB, workspaceID uint64) []string {
+	t.Helper()
+	items, err := ListTrash(db, workspaceID)
+	must(t, err)
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.Kind+":"+item.ID)
+	}
+	return ids
+}
+
+func TestDeleteFolderSubtree(t *testing.T) {
+	db := servicetest.NewDB(t)
+	workspace := seedCollection(t, db)
+
+	report, err := DeleteFolder(db, "a1", false, 1)
+	must(t, err)
+	if report.Folders != 2 || report.Requests != 2 || report.Rehomed != 0 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if n := count(t, db, &model.Folder{}, "folder_id IN (?)", []string{"a1", "a1x"}); n != 0 {
+		t.Errorf("expected the subtree to be trashed, got %d live folders", n)
+	}
+	if n := count(t, db, &model.Folder{}, "folder_id = ?", "a2"); n != 1 {
+		t.Errorf("expected the sibling to be kept, got %d", n)
+	}
+	// 闭包关系保留到永久删除
+	if n := count(t, db, &model.FolderClosure{}, "descendant = ?", "a1x"); n != 3 {
+		t.Errorf("expected closures to be kept, got %d", n)
+	}
+	if ids := trashIDs(t, db, workspace.ID); len(ids) != 1 || ids[0] != KindFolder+":a1" {
+		t.Errorf("expected only a1 to be listed, got %v", ids)
+	}
+}
+
+func TestDeleteFolderRehome(t *testing.T) {
+	db := servicetest.NewDB(t)
+	seedCollection(t, db)
+
+	report, err := DeleteFolder(db, "a1", true, 1)
+	must(t, err)
+	if report.Folders != 1 || report.Requests != 0 || report.Rehomed != 3 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if parent, err := parentFolderID(db, "a1x"); err != nil || parent != "a" {
+		t.Errorf("expected a1x to move under a, got %q %v", parent, err)
+	}
+	if d := closureDepth(t, db, "a", "a1x"); d != 1 {
+		t.Errorf("expected depth 1, got %d", d)
+	}
+	for _, id := range []string{"r1", "r2"} {
+		var r model.Request
+		must(t, db.Where("request_id = ?", id).First(&r).Error)
+		if r.FolderID != "a" {
+			t.Errorf("expected %s to move into a, got %q", id, r.FolderID)
+		}
+		if action := lastAction(t, db, id); action != model.RevisionMove {
+			t.Errorf("expected a move revision for %s, got %q", id, action)
+		}
+	}
+}
+
+func TestRestoreSharedStamp(t *testing.T) {
+	db := servicetest.NewDB(t)
+	workspace := seedCollection(t, db)
+
+	// r1 先单独删除，之后再删除所在文件夹，恢复文件夹时 r1 仍留在回收站
+	_, err := DeleteRequest(db, "r1", 1)
+	must(t, err)
+	time.Sleep(10 * time.Millisecond)
+	_, err = DeleteFolder(db, "a1", false, 1)
+	must(t, err)
+	if ids := trashIDs(t, db, workspace.ID); len(ids) != 2 {
+		t.Fatalf("expected a1 and r1 to be listed, got %v", ids)
+	}
+
+	report, err := Restore(db, KindFolder, "a1", 1)
+	must(t, err)
+	if report.Folders != 2 || report.Requests != 1 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if n := count(t, db, &model.Request{}, "request_id = ?", "r2"); n != 1 {
+		t.Errorf("expected r2 to be restored, got %d", n)
+	}
+	if n := count(t, db, &model.Request{}, "request_id = ?", "r1"); n != 0 {
+		t.Errorf("expected r1 to stay in the trash, got %d", n)
+	}
+	if ids := trashIDs(t, db, workspace.ID); len(ids) != 1 || ids[0] != KindRequest+":r1" {
+		t.Errorf("expected only r1 to be listed, got %v", ids)
+	}
+
+	// 恢复请求时一并恢复已删除的上级，只恢复这条路径
+	_, err = DeleteCollections(db, []string{"c"})
+	must(t, err)
+	_, err = Restore(db, KindRequest, "r1", 1)
+	must(t, err)
+	if n := count(t, db, &model.Collections{}, "collection_id = ?", "c"); n != 1 {
+		t.Errorf("expected the collection to be restored, got %d", n)
+	}
+	if n := count(t, db, &model.Folder{}, "folder_id IN (?)", []string{"a", "a1"}); n != 2 {
+		t.Errorf("expected the path to r1 to be restored, got %d", n)
+	}
+	if n := count(t, db, &model.Folder{}, "folder_id = ?", "b"); n != 0 {
+		t.Errorf("expected folders off the path to stay in the trash, got %d", n)
+	}
+	if n := count(t, db, &model.Request{}, "request_id = ?", "r2"); n != 0 {
+		t.Errorf("expected r2 to stay in the trash, got %d", n)
+	}
+
+	if _, err := Restore(db, KindFolder, "missing", 1); err != ErrNotInTrash {
+		t.Errorf("expected ErrNotInTrash, got %v", err)
+	}
+}
+
+func TestPurgeTrashKeepsLiveRows(t *testing.T) {
+	db := servicetest.NewDB(t)
+	workspace := seedCollection(t, db)
+	other := &model.Collections{Name: "Other", OwnerID: 1, WorkspaceID: workspace.ID, CollectionID: "d"}
+	must(t, db.Create(other).Error)
+	must(t, CreateFolder(db, &model.Folder{Name: "d1", CollectionID: "d", FolderID: "d1"}, ""))
+	must(t, db.Create(&model.Request{Name: "d-r", CollectionID: "d", FolderID: "d1", RequestID: "d-r", Method: "GET"}).Error)
+
+	_, err := DeleteFolder(db, "a1", false, 1)
+	must(t, err)
+	_, err = DeleteRequest(db, "r3", 1)
+	must(t, err)
+
+	// 删除时间之前的清理不删除任何内容
+	report, err := PurgeTrash(db, time.Now().Add(-time.Hour))
+	must(t, err)
+	if report.Folders != 0 || report.Requests != 0 {
+		t.Fatalf("expected nothing to be purged, got %+v", report)
+	}
+
+	report, err = PurgeTrash(db, time.Now().Add(time.Minute))
+	must(t, err)
+	if report.Folders != 2 || report.Requests != 3 || report.Examples != 1 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	folders, requests := []string{"a1", "a1x"}, []string{"r1", "r2", "r3"}
+	gone := []struct {
+		table interface{}
+		query string
+		args  []interface{}
+	}{
+		{&model.Folder{}, "folder_id IN (?)", []interface{}{folders}},
+		{&model.FolderClosure{}, "descendant IN (?) OR ancestor IN (?)", []interface{}{folders, folders}},
+		{&model.Request{}, "request_id IN (?)", []interface{}{requests}},
+		{&model.RequestExample{}, "request_id IN (?)", []interface{}{requests}},
+		{&model.RequestRevision{}, "request_id IN (?)", []interface{}{requests}},
+	}
+	for _, g := range gone {
+		var n int64
+		must(t, db.Unscoped().Model(g.table).Where(g.query, g.args...).Count(&n).Error)
+		if n != 0 {
+			t.Errorf("expected %T rows to be purged, got %d", g.table, n)
+		}
+	}
+
+	// 未删除的内容保持不变
+	live := []struct {
+		table interface{}
+		query string
+		arg   interface{}
+		want  int64
+	}{
+		{&model.Collections{}, "collection_id IN (?)", []string{"c", "d"}, 2},
+		{&model.Folder{}, "folder_id IN (?)", []string{"a", "a2", "b", "d1"}, 4},
+		{&model.FolderClosure{}, "descendant IN (?)", []string{"a", "a2", "b", "d1"}, 5},
+		{&model.Request{}, "request_id = ?", "d-r", 1},
+		{&model.Environment{}, "workspace_id = ?", workspace.ID, 2},
+	}
+	for _, l := range live {
+		if n := count(t, db, l.table, l.query, l.arg); n != l.want {
+			t.Errorf("expected %d live %T rows, got %d", l.want, l.table, n)
+		}
+	}
+}
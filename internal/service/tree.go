@@ -0,0 +1,113 @@
+package service
+
+import (
+	"FastGo/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// FolderNode 文件夹树节点
+type FolderNode struct {
+	Folder   model.Folder
+	Folders  []*FolderNode
+	Requests []*model.Request
+}
+
+// CollectionTree 集合及其完整的文件夹、请求树
+type CollectionTree struct {
+	Collection model.Collections
+	Folders    []*FolderNode
+	Requests   []*model.Request
+}
+
+// LoadCollectionTrees 批量构建集合树
+// 通过闭包表中 depth=1 的边确定父子关系，查询次数与集合数量无关
+func LoadCollectionTrees(db *gorm.DB, collections []model.Collections) ([]*CollectionTree, error) {
+	trees := make([]*CollectionTree, 0, len(collections))
+	if len(collections) == 0 {
+		return trees, nil
+	}
+
+	collectionIDs := make([]string, 0, len(collections))
+	treeMap := make(map[string]*CollectionTree, len(collections))
+	for _, c := range collections {
+		tree := &CollectionTree{Collection: c}
+		trees = append(trees, tree)
+		treeMap[c.CollectionID] = tree
+		collectionIDs = append(collectionIDs, c.CollectionID)
+	}
+
+	var folders []model.Folder
+	if err := db.Where("collection_id IN (?)", collectionIDs).Order("sort_order, id").Find(&folders).Error; err != nil {
+		return nil, err
+	}
+
+	var requests []model.Request
+	if err := db.Where("collection_id IN (?)", collectionIDs).Order("sort_order, id").Find(&requests).Error; err != nil {
+		return nil, err
+	}
+
+	parents := map[string]string{}
+	if len(folders) > 0 {
+		folderIDs := make([]string, 0, len(folders))
+		for _, f := range folders {
+			folderIDs = append(folderIDs, f.FolderID)
+		}
+		var closures []model.FolderClosure
+		if err := db.Where("descendant IN (?) AND depth = 1", folderIDs).Find(&closures).Error; err != nil {
+			return nil, err
+		}
+		for _, c := range closures {
+			parents[c.Descendant] = c.Ancestor
+		}
+	}
+
+	nodes := make(map[string]*FolderNode, len(folders))
+	for _, f := range folders {
+		nodes[f.FolderID] = &FolderNode{Folder: f}
+	}
+
+	// 按排序挂载文件夹，父节点缺失的文件夹视为根目录
+	for _, f := range folders {
+		node := nodes[f.FolderID]
+		if parent, ok := nodes[parents[f.FolderID]]; ok {
+			parent.Folders = append(parent.Folders, node)
+			continue
+		}
+		if tree, ok := treeMap[f.CollectionID]; ok {
+			tree.Folders = append(tree.Folders, node)
+		}
+	}
+
+	for i := range requests {
+		r := &requests[i]
+		if node, ok := nodes[r.FolderID]; ok && r.FolderID != "" {
+			node.Requests = append(node.Requests, r)
+			continue
+		}
+		if tree, ok := treeMap[r.CollectionID]; ok {
+			tree.Requests = append(tree.Requests, r)
+		}
+	}
+
+	return trees, nil
+}
+
+// RequestIDs 返回树中全部请求的 RequestID
+func (t *CollectionTree) RequestIDs() []string {
+	ids := make([]string, 0, len(t.Requests))
+	for _, r := range t.Requests {
+		ids = append(ids, r.RequestID)
+	}
+	var walk func(nodes []*FolderNode)
+	walk = func(nodes []*FolderNode) {
+		for _, n := range nodes {
+			for _, r := range n.Requests {
+				ids = append(ids, r.RequestID)
+			}
+			walk(n.Folders)
+		}
+	}
+	walk(t.Folders)
+	return ids
+}
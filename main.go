@@ -19,7 +19,9 @@ func main() {
 
 	// 执行数据库迁移
 	if *migrate {
-		bootstrap.Migrate()
+		if err := bootstrap.Migrate(); err != nil {
+			log.Fatalf("数据库迁移失败: %v", err)
+		}
 		return
 	}
 
@@ -0,0 +1,274 @@
+// Package tagexpr 解析和计算标签表达式，例如 smoke && !deprecated、payments or (auth and not slow)
+//
+// 运算符优先级从高到低为 not（!）、and（&&）、or（||），相邻的两个标签之间省略运算符时按 and 处理。
+// 标签不区分大小写，由字母、数字和 - _ . : / 组成。
+package tagexpr
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Expr 解析后的标签表达式
+type Expr struct {
+	root node
+}
+
+type node interface {
+	eval(tags map[string]bool) bool
+	write(b *strings.Builder, parent int)
+}
+
+// 运算符优先级，用于生成字符串时决定是否加括号
+const (
+	precOr = iota + 1
+	precAnd
+	precNot
+)
+
+type (
+	tagNode string
+	notNode struct{ x node }
+	andNode struct{ l, r node }
+	orNode  struct{ l, r node }
+)
+
+func (n tagNode) eval(tags map[string]bool) bool { return tags[string(n)] }
+func (n notNode) eval(tags map[string]bool) bool { return !n.x.eval(tags) }
+func (n andNode) eval(tags map[string]bool) bool { return n.l.eval(tags) && n.r.eval(tags) }
+func (n orNode) eval(tags map[string]bool) bool  { return n.l.eval(tags) || n.r.eval(tags) }
+
+func (n tagNode) write(b *strings.Builder, _ int) { b.WriteString(string(n)) }
+
+func (n notNode) write(b *strings.Builder, _ int) {
+	b.WriteString("!")
+	n.x.write(b, precNot)
+}
+
+func (n andNode) write(b *strings.Builder, parent int) {
+	writeBinary(b, parent, precAnd, " && ", n.l, n.r)
+}
+
+func (n orNode) write(b *strings.Builder, parent int) {
+	writeBinary(b, parent, precOr, " || ", n.l, n.r)
+}
+
+func writeBinary(b *strings.Builder, parent, prec int, op string, l, r node) {
+	if parent > prec {
+		b.WriteString("(")
+		defer b.WriteString(")")
+	}
+	l.write(b, prec)
+	b.WriteString(op)
+	r.write(b, prec)
+}
+
+// Parse 解析标签表达式
+func Parse(s string) (*Expr, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty tag expression")
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in tag expression", p.tokens[p.pos].text)
+	}
+	return &Expr{root: root}, nil
+}
+
+// Match 判断标签集合是否满足表达式，标签不区分大小写
+func (e *Expr) Match(tags []string) bool {
+	set := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		set[strings.ToLower(t)] = true
+	}
+	return e.root.eval(set)
+}
+
+// String 返回规范化的表达式，可再次解析
+func (e *Expr) String() string {
+	var b strings.Builder
+	e.root.write(&b, 0)
+	return b.String()
+}
+
+// ValidTag 检查标签名称是否可以在表达式中使用
+func ValidTag(tag string) bool {
+	if tag == "" || keywords[strings.ToLower(tag)] != "" {
+		return false
+	}
+	for _, r := range tag {
+		if !isTagRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isTagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("-_.:/", r)
+}
+
+// 词法单元类型
+const (
+	tokTag = iota
+	tokAnd
+	tokOr
+	tokNot
+	tokLParen
+	tokRParen
+)
+
+var keywords = map[string]string{"and": "&&", "or": "||", "not": "!"}
+
+type token struct {
+	kind int
+	text string
+}
+
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{tokLParen, "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokRParen, ")"})
+			i++
+		case r == '!':
+			tokens = append(tokens, token{tokNot, "!"})
+			i++
+		case r == '&' || r == '|':
+			if i+1 >= len(runes) || runes[i+1] != r {
+				return nil, fmt.Errorf("unexpected %q in tag expression, use %c%c", r, r, r)
+			}
+			kind := tokAnd
+			if r == '|' {
+				kind = tokOr
+			}
+			tokens = append(tokens, token{kind, string([]rune{r, r})})
+			i += 2
+		case isTagRune(r):
+			start := i
+			for i < len(runes) && isTagRune(runes[i]) {
+				i++
+			}
+			word := strings.ToLower(string(runes[start:i]))
+			switch keywords[word] {
+			case "&&":
+				tokens = append(tokens, token{tokAnd, word})
+			case "||":
+				tokens = append(tokens, token{tokOr, word})
+			case "!":
+				tokens = append(tokens, token{tokNot, word})
+			default:
+				tokens = append(tokens, token{tokTag, word})
+			}
+		default:
+			return nil, fmt.Errorf("unexpected %q in tag expression", r)
+		}
+	}
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind != tokOr {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+}
+
+// parseAnd 解析 and 连接的项，下一个词法单元可以开始新的项时按省略的 and 处理
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok {
+			return left, nil
+		}
+		switch t.kind {
+		case tokAnd:
+			p.pos++
+		case tokTag, tokNot, tokLParen:
+		default:
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+}
+
+func (p *parser) parseNot() (node, error) {
+	t, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of tag expression")
+	}
+	switch t.kind {
+	case tokNot:
+		p.pos++
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{x}, nil
+	case tokLParen:
+		p.pos++
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t, ok := p.peek(); !ok || t.kind != tokRParen {
+			return nil, fmt.Errorf("missing ) in tag expression")
+		}
+		p.pos++
+		return x, nil
+	case tokTag:
+		p.pos++
+		return tagNode(t.text), nil
+	default:
+		return nil, fmt.Errorf("unexpected %q in tag expression", t.text)
+	}
+}
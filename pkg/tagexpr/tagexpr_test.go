@@ -0,0 +1,72 @@
+package tagexpr
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	tags := []string{"Smoke", "payments"}
+	cases := []struct {
+		expr string
+		want bool
+	}{
+		{"smoke", true},
+		{"SMOKE && !deprecated", true},
+		{"smoke and deprecated", false},
+		{"deprecated or payments", true},
+		{"smoke payments", true},
+		{"!(smoke || auth)", false},
+		{"not deprecated and (auth or payments)", true},
+		{"auth || smoke && payments", true},
+		{"(auth || smoke) && !payments", false},
+	}
+	for _, c := range cases {
+		e, err := Parse(c.expr)
+		if err != nil {
+			t.Fatalf("parse %q: %v", c.expr, err)
+		}
+		if got := e.Match(tags); got != c.want {
+			t.Errorf("%q: expected %v, got %v", c.expr, c.want, got)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	cases := map[string]string{
+		"a or b and not c":      "a || b && !c",
+		"(a or b) c":            "(a || b) && c",
+		"!(a && b)":             "!(a && b)",
+		"env:prod || team/core": "env:prod || team/core",
+	}
+	for in, want := range cases {
+		e, err := Parse(in)
+		if err != nil {
+			t.Fatalf("parse %q: %v", in, err)
+		}
+		if got := e.String(); got != want {
+			t.Errorf("%q: expected %q, got %q", in, want, got)
+		}
+		if _, err := Parse(e.String()); err != nil {
+			t.Errorf("reparse %q: %v", e.String(), err)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, in := range []string{"", "   ", "a &", "a ||", "(a", "a)", "a # b", "and"} {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("expected error for %q", in)
+		}
+	}
+}
+
+func TestValidTag(t *testing.T) {
+	for _, tag := range []string{"smoke", "v1.2", "env:prod", "支付"} {
+		if !ValidTag(tag) {
+			t.Errorf("expected %q to be valid", tag)
+		}
+	}
+	for _, tag := range []string{"", "two words", "a&b", "not", "OR"} {
+		if ValidTag(tag) {
+			t.Errorf("expected %q to be invalid", tag)
+		}
+	}
+}
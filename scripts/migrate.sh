@@ -1,16 +1,14 @@
 #!/bin/bash
 
-# 数据库迁移脚本
+# 数据库迁移脚本，表结构由模型定义，通过 AutoMigrate 创建或补齐字段和索引
 echo "开始数据库迁移..."
 
-# 检查数据库连接
-mysql -h localhost -u root -p fastgo -e "SELECT 1;" > /dev/null 2>&1
-if [ $? -ne 0 ]; then
-    echo "数据库连接失败"
+# 确保在项目根目录运行
+cd "$(dirname "$0")/.." || exit 1
+
+if ! go run . -migrate; then
+    echo "数据库迁移失败"
     exit 1
 fi
 
-# 执行迁移SQL
-mysql -h localhost -u root -p fastgo < ./scripts/sql/init.sql
-
-echo "数据库迁移完成"
\ No newline at end of file
+echo "数据库迁移完成"